@@ -0,0 +1,28 @@
+-- Stock movement ledger.
+-- Every change to products.stock is recorded here with its reason and
+-- reference; products.stock is kept only as a cached balance. The ledger
+-- is the audit trail of the stock, so a product with movements can't be
+-- deleted; it is archived instead.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id             SERIAL PRIMARY KEY,
+    product_id     INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    movement_type  VARCHAR(20) NOT NULL,
+    quantity       INT NOT NULL,          -- signed: positive adds stock, negative removes it
+    balance_after  INT NOT NULL,
+    reason         TEXT NOT NULL DEFAULT '',
+    reference_type VARCHAR(30) NOT NULL DEFAULT '',
+    reference_id   INT,
+    created_by     TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product ON stock_movements (product_id, created_at);
+
+-- Opening balance so the ledger agrees with the stock already on hand
+INSERT INTO stock_movements (product_id, movement_type, quantity, balance_after, reason)
+SELECT id, 'adjustment', stock, stock, 'opening balance'
+FROM products
+WHERE stock <> 0;
+
+-- Archived products keep their history but are no longer listed or sold
+ALTER TABLE products ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
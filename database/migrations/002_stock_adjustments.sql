@@ -0,0 +1,24 @@
+-- Reason codes for manual stock adjustments.
+-- direction tells whether an adjustment with this reason removes stock
+-- ('out') or adds it back ('in').
+CREATE TABLE IF NOT EXISTS adjustment_reasons (
+    code      VARCHAR(30) PRIMARY KEY,
+    name      TEXT NOT NULL,
+    direction VARCHAR(3) NOT NULL CHECK (direction IN ('in', 'out'))
+);
+
+INSERT INTO adjustment_reasons (code, name, direction) VALUES
+    ('damaged', 'Damaged', 'out'),
+    ('expired', 'Expired', 'out'),
+    ('theft', 'Theft', 'out'),
+    ('found', 'Found', 'in')
+ON CONFLICT (code) DO NOTHING;
+
+-- Adjustment header, the lines are the stock movements referencing it
+CREATE TABLE IF NOT EXISTS stock_adjustments (
+    id          SERIAL PRIMARY KEY,
+    reason_code VARCHAR(30) NOT NULL REFERENCES adjustment_reasons(code),
+    notes       TEXT NOT NULL DEFAULT '',
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
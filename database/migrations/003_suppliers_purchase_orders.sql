@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+    id      SERIAL PRIMARY KEY,
+    name    TEXT NOT NULL,
+    phone   TEXT NOT NULL DEFAULT '',
+    email   TEXT NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT ''
+);
+
+-- status: draft -> sent -> partially_received -> received -> closed
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id          SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id),
+    status      VARCHAR(20) NOT NULL DEFAULT 'draft',
+    notes       TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at     TIMESTAMP,
+    closed_at   TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id        INT NOT NULL REFERENCES products(id),
+    quantity_ordered  INT NOT NULL CHECK (quantity_ordered > 0),
+    quantity_received INT NOT NULL DEFAULT 0,
+    unit_cost         INT NOT NULL DEFAULT 0,
+    UNIQUE (purchase_order_id, product_id)
+);
+
+-- One goods receipt per delivery, a PO can be received in several deliveries
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id),
+    notes             TEXT NOT NULL DEFAULT '',
+    created_by        TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_items (
+    id                     SERIAL PRIMARY KEY,
+    goods_receipt_id       INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id INT NOT NULL REFERENCES purchase_order_items(id),
+    product_id             INT NOT NULL REFERENCES products(id),
+    quantity               INT NOT NULL CHECK (quantity > 0),
+    unit_cost              INT NOT NULL,
+    notes                  TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,4 @@
+-- Reorder point and quantity per product. A product is low on stock once
+-- its stock is at or below min_stock; 0 disables the check.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS min_stock INT NOT NULL DEFAULT 0 CHECK (min_stock >= 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_qty INT NOT NULL DEFAULT 0 CHECK (reorder_qty >= 0);
@@ -0,0 +1,39 @@
+-- status: open -> posted | cancelled
+CREATE TABLE IF NOT EXISTS stock_takes (
+    id         SERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    status     VARCHAR(20) NOT NULL DEFAULT 'open',
+    notes      TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    posted_at  TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stock_take_categories (
+    stock_take_id INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    category_id   INT NOT NULL REFERENCES categories(id),
+    PRIMARY KEY (stock_take_id, category_id)
+);
+
+-- snapshot_qty is the stock when the session was opened, counted_qty the
+-- sum of all count batches received for the product
+CREATE TABLE IF NOT EXISTS stock_take_items (
+    id              SERIAL PRIMARY KEY,
+    stock_take_id   INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    product_id      INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    snapshot_qty    INT NOT NULL,
+    counted_qty     INT,
+    last_counted_at TIMESTAMP,
+    UNIQUE (stock_take_id, product_id)
+);
+
+-- Raw count batches as sent by the scanners
+CREATE TABLE IF NOT EXISTS stock_take_counts (
+    id            SERIAL PRIMARY KEY,
+    stock_take_id INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    product_id    INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity      INT NOT NULL CHECK (quantity >= 0),
+    device        TEXT NOT NULL DEFAULT '',
+    counted_by    TEXT NOT NULL DEFAULT '',
+    counted_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
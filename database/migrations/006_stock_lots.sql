@@ -0,0 +1,25 @@
+-- Stock held in lots with a batch number and expiry date. The sum of the
+-- lots of a product can be lower than products.stock, the rest is stock
+-- that was never assigned to a lot.
+CREATE TABLE IF NOT EXISTS stock_lots (
+    id               SERIAL PRIMARY KEY,
+    product_id       INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    batch_number     TEXT NOT NULL,
+    expiry_date      DATE,
+    quantity         INT NOT NULL CHECK (quantity >= 0),
+    goods_receipt_id INT REFERENCES goods_receipts(id),
+    received_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_lots_fefo ON stock_lots (product_id, expiry_date) WHERE quantity > 0;
+
+-- Lots consumed by each sold line
+CREATE TABLE IF NOT EXISTS transaction_detail_lots (
+    id                    SERIAL PRIMARY KEY,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    lot_id                INT NOT NULL REFERENCES stock_lots(id),
+    quantity              INT NOT NULL CHECK (quantity > 0)
+);
+
+-- Manager who allowed expired lots to be sold
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS expired_override_by TEXT;
@@ -0,0 +1,68 @@
+CREATE TABLE IF NOT EXISTS stores (
+    id      SERIAL PRIMARY KEY,
+    name    TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT ''
+);
+
+-- Existing data belongs to the first store
+INSERT INTO stores (id, name) VALUES (1, 'Main store') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('stores', 'id'), GREATEST((SELECT MAX(id) FROM stores), 1));
+
+-- Stock per product per store. products.stock stays the consolidated
+-- balance of all stores.
+CREATE TABLE IF NOT EXISTS product_stocks (
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    store_id   INT NOT NULL REFERENCES stores(id),
+    stock      INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    PRIMARY KEY (product_id, store_id)
+);
+
+INSERT INTO product_stocks (product_id, store_id, stock)
+SELECT id, 1, stock FROM products
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS store_id INT NOT NULL DEFAULT 1 REFERENCES stores(id);
+ALTER TABLE stock_lots ADD COLUMN IF NOT EXISTS store_id INT NOT NULL DEFAULT 1 REFERENCES stores(id);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS store_id INT NOT NULL DEFAULT 1 REFERENCES stores(id);
+ALTER TABLE stock_adjustments ADD COLUMN IF NOT EXISTS store_id INT NOT NULL DEFAULT 1 REFERENCES stores(id);
+ALTER TABLE purchase_orders ADD COLUMN IF NOT EXISTS store_id INT NOT NULL DEFAULT 1 REFERENCES stores(id);
+ALTER TABLE stock_takes ADD COLUMN IF NOT EXISTS store_id INT NOT NULL DEFAULT 1 REFERENCES stores(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_store ON transactions (store_id, created_at);
+
+-- Inter-store transfers. Stock leaves the sending store when the transfer
+-- is sent and arrives when it is received; in between it is in transit.
+-- status: in_transit -> received
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id            SERIAL PRIMARY KEY,
+    from_store_id INT NOT NULL REFERENCES stores(id),
+    to_store_id   INT NOT NULL REFERENCES stores(id),
+    status        VARCHAR(20) NOT NULL DEFAULT 'in_transit',
+    notes         TEXT NOT NULL DEFAULT '',
+    sent_by       TEXT NOT NULL DEFAULT '',
+    sent_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    received_by   TEXT NOT NULL DEFAULT '',
+    received_at   TIMESTAMP,
+    CHECK (from_store_id <> to_store_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_transfer_items (
+    id                SERIAL PRIMARY KEY,
+    stock_transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id        INT NOT NULL REFERENCES products(id),
+    quantity_sent     INT NOT NULL CHECK (quantity_sent > 0),
+    quantity_received INT,
+    UNIQUE (stock_transfer_id, product_id)
+);
+
+-- Lots taken out of the sending store, recreated at the receiving store
+-- with the same batch number and expiry date
+CREATE TABLE IF NOT EXISTS stock_transfer_lots (
+    id                SERIAL PRIMARY KEY,
+    stock_transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id        INT NOT NULL REFERENCES products(id),
+    lot_id            INT NOT NULL REFERENCES stock_lots(id),
+    batch_number      TEXT NOT NULL,
+    expiry_date       DATE,
+    quantity          INT NOT NULL CHECK (quantity > 0)
+);
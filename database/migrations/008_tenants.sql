@@ -0,0 +1,55 @@
+-- Businesses hosted by this API. A request is resolved to a tenant by its
+-- API key (Authorization: Bearer <key>) or by its host name.
+CREATE TABLE IF NOT EXISTS tenants (
+    id      SERIAL PRIMARY KEY,
+    name    TEXT NOT NULL,
+    host    TEXT UNIQUE,
+    api_key TEXT UNIQUE
+);
+
+-- Existing data belongs to the first tenant
+INSERT INTO tenants (id, name) VALUES (1, 'Default') ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('tenants', 'id'), GREATEST((SELECT MAX(id) FROM tenants), 1));
+
+-- Every table gets a tenant_id filled from the app.tenant_id setting of the
+-- current transaction, and a row-level security policy that hides rows of
+-- other tenants. FORCE applies the policies to the table owner as well;
+-- superusers still bypass them, so the API must not connect as one.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'categories', 'products', 'transactions', 'transaction_details',
+        'stock_movements', 'adjustment_reasons', 'stock_adjustments',
+        'suppliers', 'purchase_orders', 'purchase_order_items', 'goods_receipts', 'goods_receipt_items',
+        'stock_takes', 'stock_take_categories', 'stock_take_items', 'stock_take_counts',
+        'stock_lots', 'transaction_detail_lots',
+        'stores', 'product_stocks', 'stock_transfers', 'stock_transfer_items',
+        'stock_transfer_lots'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id INT NOT NULL DEFAULT 1 REFERENCES tenants(id)', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id SET DEFAULT current_setting(''app.tenant_id'')::int', t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (tenant_id)', 'idx_' || t || '_tenant', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), '''')::int)', t);
+    END LOOP;
+END $$;
+
+-- Reason codes are per tenant
+ALTER TABLE stock_adjustments DROP CONSTRAINT IF EXISTS stock_adjustments_reason_code_fkey;
+ALTER TABLE adjustment_reasons DROP CONSTRAINT IF EXISTS adjustment_reasons_pkey;
+ALTER TABLE adjustment_reasons ADD PRIMARY KEY (tenant_id, code);
+ALTER TABLE stock_adjustments ADD CONSTRAINT stock_adjustments_reason_code_fkey
+    FOREIGN KEY (tenant_id, reason_code) REFERENCES adjustment_reasons (tenant_id, code);
+
+-- Each tenant has its own stores, so there is no store everyone defaults to
+ALTER TABLE stock_movements ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE stock_lots ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE transactions ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE stock_adjustments ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE purchase_orders ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE stock_takes ALTER COLUMN store_id DROP DEFAULT;
@@ -0,0 +1,20 @@
+-- SKUs are optional but unique within a tenant
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS products_sku_key ON products (tenant_id, sku);
+
+-- Barcodes are stored as 13 digits, UPC-A codes with a leading zero
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    id         SERIAL PRIMARY KEY,
+    tenant_id  INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    barcode    CHAR(13) NOT NULL,
+    CONSTRAINT product_barcodes_barcode_key UNIQUE (tenant_id, barcode)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product ON product_barcodes (product_id);
+
+ALTER TABLE product_barcodes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_barcodes FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON product_barcodes;
+CREATE POLICY tenant_isolation ON product_barcodes
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
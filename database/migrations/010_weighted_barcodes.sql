@@ -0,0 +1,36 @@
+-- Scale items are sold by weight, so stock and sold quantities are kept
+-- with three decimal places
+ALTER TABLE products ALTER COLUMN stock TYPE NUMERIC(14,3);
+ALTER TABLE product_stocks ALTER COLUMN stock TYPE NUMERIC(14,3);
+ALTER TABLE stock_movements ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_movements ALTER COLUMN balance_after TYPE NUMERIC(14,3);
+ALTER TABLE stock_lots ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE transaction_details ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE transaction_detail_lots ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_take_items ALTER COLUMN snapshot_qty TYPE NUMERIC(14,3);
+ALTER TABLE stock_take_items ALTER COLUMN counted_qty TYPE NUMERIC(14,3);
+ALTER TABLE stock_take_counts ALTER COLUMN quantity TYPE NUMERIC(14,3);
+
+-- PLU is the item code printed in in-store barcodes
+ALTER TABLE products ADD COLUMN IF NOT EXISTS plu INT;
+CREATE UNIQUE INDEX IF NOT EXISTS products_plu_key ON products (tenant_id, plu);
+
+-- Layouts of in-store EAN-13 barcodes, e.g. scale labels that embed a PLU
+-- and a weight or price. value_type: weight | price
+CREATE TABLE IF NOT EXISTS barcode_formats (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    name           TEXT NOT NULL,
+    prefix         VARCHAR(11) NOT NULL,
+    code_length    INT NOT NULL CHECK (code_length > 0),
+    value_type     VARCHAR(10) NOT NULL,
+    value_length   INT NOT NULL CHECK (value_length > 0),
+    value_decimals INT NOT NULL DEFAULT 0 CHECK (value_decimals >= 0),
+    CONSTRAINT barcode_formats_prefix_key UNIQUE (tenant_id, prefix)
+);
+
+ALTER TABLE barcode_formats ENABLE ROW LEVEL SECURITY;
+ALTER TABLE barcode_formats FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON barcode_formats;
+CREATE POLICY tenant_isolation ON barcode_formats
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
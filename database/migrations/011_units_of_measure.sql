@@ -0,0 +1,14 @@
+-- Products are stocked and sold in a unit of measure. The decimal places a
+-- quantity may have depend on the unit and are checked by the API.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'pcs'
+    CHECK (unit IN ('pcs', 'kg', 'g', 'l', 'm'));
+
+-- Every remaining quantity becomes a decimal like stock already is
+ALTER TABLE products ALTER COLUMN min_stock TYPE NUMERIC(14,3);
+ALTER TABLE products ALTER COLUMN reorder_qty TYPE NUMERIC(14,3);
+ALTER TABLE purchase_order_items ALTER COLUMN quantity_ordered TYPE NUMERIC(14,3);
+ALTER TABLE purchase_order_items ALTER COLUMN quantity_received TYPE NUMERIC(14,3);
+ALTER TABLE goods_receipt_items ALTER COLUMN quantity TYPE NUMERIC(14,3);
+ALTER TABLE stock_transfer_items ALTER COLUMN quantity_sent TYPE NUMERIC(14,3);
+ALTER TABLE stock_transfer_items ALTER COLUMN quantity_received TYPE NUMERIC(14,3);
+ALTER TABLE stock_transfer_lots ALTER COLUMN quantity TYPE NUMERIC(14,3);
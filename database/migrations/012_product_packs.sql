@@ -0,0 +1,27 @@
+-- Packs and cases a product is also sold or received in. Stock stays in
+-- the product's own unit; factor is the number of those units in a pack.
+-- A price of 0 sells the pack at the unit price times the factor.
+CREATE TABLE IF NOT EXISTS product_packs (
+    id         SERIAL PRIMARY KEY,
+    tenant_id  INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    factor     NUMERIC(14,3) NOT NULL CHECK (factor > 0),
+    price      INT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    barcode    CHAR(13),
+    UNIQUE (product_id, name),
+    CONSTRAINT product_packs_barcode_key UNIQUE (tenant_id, barcode)
+);
+
+ALTER TABLE product_packs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_packs FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON product_packs;
+CREATE POLICY tenant_isolation ON product_packs
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+-- Lines sold or received in packs keep the pack and how many of them;
+-- quantity stays in the product's unit
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS pack_name TEXT;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS pack_quantity NUMERIC(14,3);
+ALTER TABLE goods_receipt_items ADD COLUMN IF NOT EXISTS pack_name TEXT;
+ALTER TABLE goods_receipt_items ADD COLUMN IF NOT EXISTS pack_quantity NUMERIC(14,3);
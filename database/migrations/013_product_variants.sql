@@ -0,0 +1,11 @@
+-- Variants such as sizes and colors are products of their own under a
+-- parent product. The parent names the attributes its variants differ in,
+-- each variant holds its values, e.g. {"size": "M", "color": "red"}.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES products(id) ON DELETE CASCADE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS variant_attributes TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_products_parent ON products (parent_id);
+CREATE UNIQUE INDEX IF NOT EXISTS products_variant_key ON products (parent_id, attributes) WHERE parent_id IS NOT NULL;
+
+-- A variant without a price of its own sells at the parent's price
+ALTER TABLE products ALTER COLUMN price DROP NOT NULL;
@@ -0,0 +1,36 @@
+-- Composite products such as bundles and recipes hold no stock of their
+-- own; selling one takes quantity of each component per unit sold
+CREATE TABLE IF NOT EXISTS product_components (
+    id           SERIAL PRIMARY KEY,
+    tenant_id    INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    product_id   INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id INT NOT NULL REFERENCES products(id),
+    quantity     NUMERIC(14,3) NOT NULL CHECK (quantity > 0),
+    UNIQUE (product_id, component_id),
+    CHECK (component_id <> product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_components_component ON product_components (component_id);
+
+-- What each sold line of a composite product took from its components
+CREATE TABLE IF NOT EXISTS transaction_detail_components (
+    id                    SERIAL PRIMARY KEY,
+    tenant_id             INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    product_id            INT NOT NULL REFERENCES products(id),
+    quantity              NUMERIC(14,3) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_detail_components_detail ON transaction_detail_components (transaction_detail_id);
+
+ALTER TABLE product_components ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_components FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON product_components;
+CREATE POLICY tenant_isolation ON product_components
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE transaction_detail_components ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transaction_detail_components FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON transaction_detail_components;
+CREATE POLICY tenant_isolation ON transaction_detail_components
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
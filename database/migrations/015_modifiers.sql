@@ -0,0 +1,66 @@
+-- Modifiers such as an extra shot or oat milk come in groups. A sale picks
+-- between min_select and max_select of a group (0 for no maximum), each
+-- modifier may change the price of the item.
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id         SERIAL PRIMARY KEY,
+    tenant_id  INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    name       TEXT NOT NULL,
+    min_select INT NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INT NOT NULL DEFAULT 1 CHECK (max_select >= 0),
+    CONSTRAINT modifier_groups_name_key UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id          SERIAL PRIMARY KEY,
+    tenant_id   INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    group_id    INT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    price_delta INT NOT NULL DEFAULT 0,
+    UNIQUE (group_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS product_modifier_groups (
+    tenant_id  INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    group_id   INT NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    position   INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (product_id, group_id)
+);
+
+-- Modifiers picked for a sold line. Names and prices are copied so
+-- receipts and kitchen tickets still print them after the menu changes.
+CREATE TABLE IF NOT EXISTS transaction_detail_modifiers (
+    id                    SERIAL PRIMARY KEY,
+    tenant_id             INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    modifier_id           INT REFERENCES modifiers(id) ON DELETE SET NULL,
+    group_name            TEXT NOT NULL,
+    name                  TEXT NOT NULL,
+    price_delta           INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_detail_modifiers_detail ON transaction_detail_modifiers (transaction_detail_id);
+
+ALTER TABLE modifier_groups ENABLE ROW LEVEL SECURITY;
+ALTER TABLE modifier_groups FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON modifier_groups;
+CREATE POLICY tenant_isolation ON modifier_groups
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE modifiers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE modifiers FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON modifiers;
+CREATE POLICY tenant_isolation ON modifiers
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE product_modifier_groups ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_modifier_groups FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON product_modifier_groups;
+CREATE POLICY tenant_isolation ON product_modifier_groups
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE transaction_detail_modifiers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transaction_detail_modifiers FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON transaction_detail_modifiers;
+CREATE POLICY tenant_isolation ON transaction_detail_modifiers
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
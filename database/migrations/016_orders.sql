@@ -0,0 +1,44 @@
+-- Open tickets and held carts. Lines are kept as checkout items and priced
+-- when the order is checked out.
+-- status: open <-> held -> paid | cancelled
+CREATE TABLE IF NOT EXISTS orders (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    store_id       INT NOT NULL REFERENCES stores(id),
+    status         VARCHAR(20) NOT NULL DEFAULT 'open',
+    table_name     TEXT NOT NULL DEFAULT '',
+    notes          TEXT NOT NULL DEFAULT '',
+    created_by     TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    transaction_id INT REFERENCES transactions(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (store_id, status);
+
+CREATE TABLE IF NOT EXISTS order_lines (
+    id           SERIAL PRIMARY KEY,
+    tenant_id    INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    order_id     INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id   INT NOT NULL REFERENCES products(id),
+    pack_name    TEXT,
+    quantity     NUMERIC(14,3) NOT NULL CHECK (quantity > 0),
+    amount       INT, -- fixed by a price label
+    modifier_ids INT[] NOT NULL DEFAULT '{}',
+    notes        TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_lines_order ON order_lines (order_id);
+
+ALTER TABLE orders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE orders FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON orders;
+CREATE POLICY tenant_isolation ON orders
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE order_lines ENABLE ROW LEVEL SECURITY;
+ALTER TABLE order_lines FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON order_lines;
+CREATE POLICY tenant_isolation ON order_lines
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
@@ -0,0 +1,45 @@
+-- Orders can be split by moving lines into a child order or by dividing
+-- the total into equal shares. A split order waits in status split until
+-- every share and child order is paid.
+-- status: open <-> held -> split -> paid | cancelled
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES orders(id);
+CREATE INDEX IF NOT EXISTS idx_orders_parent ON orders (parent_id);
+
+CREATE TABLE IF NOT EXISTS order_shares (
+    id        SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    order_id  INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    number    INT NOT NULL CHECK (number > 0),
+    amount    INT NOT NULL CHECK (amount >= 0),
+    paid_at   TIMESTAMP,
+    CONSTRAINT order_shares_order_id_number_key UNIQUE (order_id, number)
+);
+
+-- Tenders recorded against a sale. amount is what the tender paid of the
+-- bill, tendered what was handed over; the difference is change.
+-- type: cash | card | qris
+CREATE TABLE IF NOT EXISTS payments (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    share_id       INT REFERENCES order_shares(id),
+    type           VARCHAR(20) NOT NULL,
+    amount         INT NOT NULL CHECK (amount >= 0),
+    tendered       INT NOT NULL CHECK (tendered >= amount),
+    reference      TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_transaction ON payments (transaction_id);
+
+ALTER TABLE order_shares ENABLE ROW LEVEL SECURITY;
+ALTER TABLE order_shares FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON order_shares;
+CREATE POLICY tenant_isolation ON order_shares
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE payments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE payments FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON payments;
+CREATE POLICY tenant_isolation ON payments
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
@@ -0,0 +1,36 @@
+-- Preparation stations such as "bar" or "kitchen". Lines of products in a
+-- category with a station are routed to that station's queue when an order
+-- is sent or a sale is checked out; an empty station routes nothing.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS station TEXT NOT NULL DEFAULT '';
+
+-- Set once an order line has been routed so it is not routed twice
+ALTER TABLE order_lines ADD COLUMN IF NOT EXISTS sent_at TIMESTAMP;
+
+-- Lines queued at a station, with what the display shows copied from the
+-- line. quantity counts packs when pack_name is set.
+-- status: queued -> preparing -> ready -> served
+CREATE TABLE IF NOT EXISTS kitchen_items (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    station        TEXT NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'queued',
+    order_id       INT REFERENCES orders(id),
+    transaction_id INT REFERENCES transactions(id),
+    table_name     TEXT NOT NULL DEFAULT '',
+    product_name   TEXT NOT NULL,
+    quantity       NUMERIC(14,3) NOT NULL,
+    unit           TEXT NOT NULL DEFAULT '',
+    pack_name      TEXT,
+    modifiers      TEXT[] NOT NULL DEFAULT '{}',
+    notes          TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kitchen_items_station ON kitchen_items (station, status, created_at);
+
+ALTER TABLE kitchen_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE kitchen_items FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON kitchen_items;
+CREATE POLICY tenant_isolation ON kitchen_items
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
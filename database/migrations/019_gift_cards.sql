@@ -0,0 +1,49 @@
+-- Gift card products issue a gift card per unit sold instead of taking
+-- stock. validity_days sets when the cards expire, NULL never.
+-- type: standard | gift_card
+ALTER TABLE products ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'standard';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS validity_days INT CHECK (validity_days > 0);
+
+-- Gift cards and store-credit vouchers. balance is kept in step with the
+-- entries and can never go below zero.
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id                    SERIAL PRIMARY KEY,
+    tenant_id             INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    code                  TEXT NOT NULL,
+    initial_balance       INT NOT NULL CHECK (initial_balance > 0),
+    balance               INT NOT NULL CHECK (balance >= 0),
+    expires_at            TIMESTAMP,
+    transaction_detail_id INT REFERENCES transaction_details(id), -- the sale that issued it
+    issued_by             TEXT NOT NULL DEFAULT '',
+    created_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT gift_cards_code_key UNIQUE (tenant_id, code)
+);
+
+-- Gift cards are redeemed as payments of type gift_card with the card code
+-- as reference. Balance ledger of a gift card, amount is positive for issue and
+-- negative for redemption. type: issue | redeem
+CREATE TABLE IF NOT EXISTS gift_card_entries (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    gift_card_id   INT NOT NULL REFERENCES gift_cards(id),
+    type           VARCHAR(20) NOT NULL,
+    amount         INT NOT NULL,
+    balance_after  INT NOT NULL CHECK (balance_after >= 0),
+    transaction_id INT REFERENCES transactions(id),
+    payment_id     INT REFERENCES payments(id),
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_entries_card ON gift_card_entries (gift_card_id);
+
+ALTER TABLE gift_cards ENABLE ROW LEVEL SECURITY;
+ALTER TABLE gift_cards FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON gift_cards;
+CREATE POLICY tenant_isolation ON gift_cards
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE gift_card_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE gift_card_entries FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON gift_card_entries;
+CREATE POLICY tenant_isolation ON gift_card_entries
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
@@ -0,0 +1,78 @@
+-- Coupon codes. discount_type: percent | fixed; a percent discount_value
+-- is 1-100, a fixed one an amount. min_spend is on the eligible lines.
+-- A limit of 0 is unlimited; per_customer_limit needs the sale to name a
+-- customer. times_used is counted while the coupon row is locked.
+CREATE TABLE IF NOT EXISTS coupons (
+    id                 SERIAL PRIMARY KEY,
+    tenant_id          INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    code               TEXT NOT NULL,
+    description        TEXT NOT NULL DEFAULT '',
+    discount_type      VARCHAR(10) NOT NULL,
+    discount_value     INT NOT NULL CHECK (discount_value > 0),
+    min_spend          INT NOT NULL DEFAULT 0 CHECK (min_spend >= 0),
+    starts_at          TIMESTAMP,
+    ends_at            TIMESTAMP,
+    usage_limit        INT NOT NULL DEFAULT 0 CHECK (usage_limit >= 0),
+    per_customer_limit INT NOT NULL DEFAULT 0 CHECK (per_customer_limit >= 0),
+    times_used         INT NOT NULL DEFAULT 0,
+    created_at         TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT coupons_code_key UNIQUE (tenant_id, code)
+);
+
+-- Lines are eligible when their product, its parent or its category is
+-- listed; a coupon listing neither applies to every line
+CREATE TABLE IF NOT EXISTS coupon_products (
+    tenant_id  INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    coupon_id  INT NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (coupon_id, product_id)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_categories (
+    tenant_id   INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    coupon_id   INT NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (coupon_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    coupon_id      INT NOT NULL REFERENCES coupons(id),
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    customer       TEXT NOT NULL DEFAULT '',
+    discount       INT NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_customer ON coupon_redemptions (coupon_id, customer);
+
+-- The discount of a sale is allocated over its eligible lines, subtotal
+-- stays the gross line amount and total_amount is net of the discount
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS coupon_code TEXT;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS discount INT NOT NULL DEFAULT 0;
+
+ALTER TABLE coupons ENABLE ROW LEVEL SECURITY;
+ALTER TABLE coupons FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON coupons;
+CREATE POLICY tenant_isolation ON coupons
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE coupon_products ENABLE ROW LEVEL SECURITY;
+ALTER TABLE coupon_products FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON coupon_products;
+CREATE POLICY tenant_isolation ON coupon_products
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE coupon_categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE coupon_categories FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON coupon_categories;
+CREATE POLICY tenant_isolation ON coupon_categories
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
+
+ALTER TABLE coupon_redemptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE coupon_redemptions FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON coupon_redemptions;
+CREATE POLICY tenant_isolation ON coupon_redemptions
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
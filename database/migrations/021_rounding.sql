@@ -0,0 +1,19 @@
+-- Rounding of the amount due per tender type, e.g. cash to the nearest 100.
+-- mode: nearest | up | down; tender types without a rule are not rounded
+CREATE TABLE IF NOT EXISTS rounding_rules (
+    tenant_id   INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    tender_type VARCHAR(20) NOT NULL,
+    mode        VARCHAR(10) NOT NULL,
+    increment   INT NOT NULL CHECK (increment > 1),
+    PRIMARY KEY (tenant_id, tender_type)
+);
+
+-- What rounding added to (or took off) the amount due. Payments add up to
+-- total_amount + rounding_amount, so revenue and rounding are booked apart.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS rounding_amount INT NOT NULL DEFAULT 0;
+
+ALTER TABLE rounding_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE rounding_rules FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON rounding_rules;
+CREATE POLICY tenant_isolation ON rounding_rules
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
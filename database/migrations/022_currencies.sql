@@ -0,0 +1,33 @@
+-- Amounts are integers in the minor unit of their currency. A store prices,
+-- sells and reports in its base currency; IDR is kept in whole rupiah.
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- Manually maintained rates: one unit of currency is worth rate units of
+-- base_currency from effective_from on, until a later rate takes over.
+-- Rates keep ten decimal places, as decimal.Rate does, so the rate of a
+-- weak currency in a strong one, e.g. IDR in USD at 0.0000625, fits.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id             SERIAL PRIMARY KEY,
+    tenant_id      INT NOT NULL DEFAULT current_setting('app.tenant_id')::int REFERENCES tenants(id),
+    currency       CHAR(3) NOT NULL,
+    base_currency  CHAR(3) NOT NULL,
+    rate           NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    effective_from TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_by     TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT exchange_rates_effective_key UNIQUE (tenant_id, currency, base_currency, effective_from)
+);
+
+-- Payments keep their amounts in the base currency of the sale. A foreign
+-- cash tender also keeps what was handed over in its own currency and the
+-- rate it was converted at.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS foreign_tendered INT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS rate NUMERIC(20,10);
+
+ALTER TABLE exchange_rates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE exchange_rates FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON exchange_rates;
+CREATE POLICY tenant_isolation ON exchange_rates
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::int);
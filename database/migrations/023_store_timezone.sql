@@ -0,0 +1,12 @@
+-- IANA timezone of a store. Report dates are whole days in this timezone,
+-- e.g. a WIB store's day runs from 00:00 to 24:00 Asia/Jakarta.
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'Asia/Jakarta';
+
+-- created_at and the other TIMESTAMP columns hold the wall-clock time of the
+-- database session, as written by NOW(). A range bound such as midnight WIB
+-- is an instant; compared with created_at as is, it would be read as a
+-- TIMESTAMP and lose its offset. session_timestamp turns it into the wall
+-- clock the column uses.
+CREATE OR REPLACE FUNCTION session_timestamp(t TIMESTAMPTZ) RETURNS TIMESTAMP
+    LANGUAGE sql STABLE
+    AS $$ SELECT t AT TIME ZONE current_setting('TimeZone') $$;
@@ -15,6 +15,112 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/barcode-formats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barcode Formats"
+                ],
+                "summary": "Get all in-store barcode formats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BarcodeFormat"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Describes EAN-13 labels that embed a PLU and a weight or price. The 12 digits before the check digit are the prefix, the PLU, any unused digits and the value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barcode Formats"
+                ],
+                "summary": "Create in-store barcode format",
+                "parameters": [
+                    {
+                        "description": "Create barcode format payload",
+                        "name": "format",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateBarcodeFormatRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.BarcodeFormat"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/barcode-formats/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barcode Formats"
+                ],
+                "summary": "Update in-store barcode format by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barcode format ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update barcode format payload",
+                        "name": "format",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateBarcodeFormatRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Barcode Formats"
+                ],
+                "summary": "Delete in-store barcode format by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Barcode format ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
         "/api/categories": {
             "get": {
                 "consumes": [
@@ -141,31 +247,29 @@ const docTemplate = `{
                 "responses": {}
             }
         },
-        "/api/products": {
+        "/api/coupons": {
             "get": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Coupons"
                 ],
-                "summary": "Get all products",
+                "summary": "Get all coupons",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.ProductResponseSwagger"
+                                "$ref": "#/definitions/model.Coupon"
                             }
                         }
                     }
                 }
             },
             "post": {
+                "description": "Codes are case-insensitive. discount_type is percent (discount_value 1-100) or fixed (an amount). The coupon applies to the listed products, their variants and the listed categories, or to every line when none are listed; min_spend is on those lines. A usage_limit or per_customer_limit of 0 is unlimited.",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,47 +277,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Coupons"
                 ],
-                "summary": "Create product",
+                "summary": "Create coupon",
                 "parameters": [
                     {
-                        "description": "Create product payload",
-                        "name": "product",
+                        "description": "Create coupon payload",
+                        "name": "coupon",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateProductRequestSwagger"
+                            "$ref": "#/definitions/model.CreateCouponRequestSwagger"
                         }
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Coupon"
+                        }
+                    }
+                }
             }
         },
-        "/api/products/{id}": {
+        "/api/coupons/{id}": {
             "get": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Coupons"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get coupon by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Coupon ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Coupon"
+                        }
+                    }
+                }
             },
             "put": {
+                "description": "Replaces the coupon, the usage count is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -221,44 +337,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Coupons"
                 ],
-                "summary": "Update product by ID",
+                "summary": "Update coupon by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Coupon ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update product payload",
-                        "name": "product",
+                        "description": "Update coupon payload",
+                        "name": "coupon",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateProductRequestSwagger"
+                            "$ref": "#/definitions/model.CreateCouponRequestSwagger"
                         }
                     }
                 ],
                 "responses": {}
             },
             "delete": {
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Only a coupon that was never redeemed can be deleted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Coupons"
                 ],
-                "summary": "Delete product by ID",
+                "summary": "Delete coupon by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Product ID",
+                        "description": "Coupon ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -266,65 +380,4951 @@ const docTemplate = `{
                 ],
                 "responses": {}
             }
-        }
-    },
-    "definitions": {
-        "model.Category": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                }
-            }
         },
-        "model.CreateCategoryRequestSwagger": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+        "/api/exchange-rates": {
+            "get": {
+                "description": "Lists the rates of every currency, latest first. The rate in force is the latest one whose effective_from has passed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange Rates"
+                ],
+                "summary": "Get exchange rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only rates of this currency, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ExchangeRate"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "One unit of currency is worth rate units of base_currency (IDR by default) from effective_from (now by default) on. Foreign cash tenders are converted at the rate in force at checkout. The X-User header is recorded as the creator.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange Rates"
+                ],
+                "summary": "Create exchange rate",
+                "parameters": [
+                    {
+                        "description": "Create exchange rate payload",
+                        "name": "rate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateExchangeRateRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ExchangeRate"
+                        }
+                    }
                 }
             }
         },
-        "model.CreateProductRequestSwagger": {
-            "type": "object",
-            "properties": {
-                "category_id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "integer"
-                },
-                "stock": {
-                    "type": "integer"
+        "/api/exports/reports/{name}": {
+            "get": {
+                "description": "Takes the parameters of the report's /api/reports endpoint. Bundle sales are written one row per component.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Exports"
+                ],
+                "summary": "Export a report as CSV or XLSX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sales, shrinkage, bundle-sales, reorder-suggestions, expiring-lots or outstanding-purchase-orders",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or xlsx (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "en or id to format numbers in CSV",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date of sales, shrinkage and bundle-sales",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date of sales, shrinkage and bundle-sales",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping of sales",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the N best selling groups of sales",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the N worst selling groups of sales",
+                        "name": "bottom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "parent to report variants as their parent product",
+                        "name": "rollup",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period of shrinkage",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of reorder-suggestions and expiring-lots (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
                 }
             }
         },
-        "model.ProductResponseSwagger": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "integer"
-                },
+        "/api/exports/transaction-details": {
+            "get": {
+                "description": "One row per sold line with its sale, streamed from the database. Net is the subtotal less the line's share of the coupon discount.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Exports"
+                ],
+                "summary": "Export sold lines as CSV or XLSX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv or xlsx (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "en or id to format numbers in CSV",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD in store time, or RFC 3339)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/exports/transactions": {
+            "get": {
+                "description": "One row per sale, streamed from the database. Times are in store time. Give locale to format numbers for a spreadsheet; without it numbers are plain, e.g. 1234.5.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Exports"
+                ],
+                "summary": "Export sales as CSV or XLSX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv or xlsx (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "en or id to format numbers in CSV",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD in store time, or RFC 3339)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gift-cards": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gift Cards"
+                ],
+                "summary": "Get gift cards",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.GiftCard"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Issues a gift card outside of a sale, e.g. for a refund. The code is generated and the X-User header is recorded as the issuer. Gift cards are sold through checkout with a gift_card product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gift Cards"
+                ],
+                "summary": "Issue store-credit voucher",
+                "parameters": [
+                    {
+                        "description": "Issue gift card payload",
+                        "name": "card",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.IssueGiftCardRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.GiftCard"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/gift-cards/{code}": {
+            "get": {
+                "description": "Returns the balance, expiry and balance ledger of a card. Spaces and dashes in the code are ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Gift Cards"
+                ],
+                "summary": "Look up gift card by code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Gift card code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GiftCard"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/inventory/adjustments": {
+            "post": {
+                "description": "Applies all lines atomically. The X-User header is recorded as the author.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Create stock adjustment",
+                "parameters": [
+                    {
+                        "description": "Create adjustment payload",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateStockAdjustmentRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockAdjustment"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/inventory/lots": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get stock lots of a product in FEFO order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockLot"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Receive stock into a new lot",
+                "parameters": [
+                    {
+                        "description": "Create lot payload",
+                        "name": "lot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateStockLotRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockLot"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/inventory/low-stock": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get products at or below their reorder point",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LowStockProduct"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/inventory/movements": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get stock movement history of a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockMovement"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/inventory/reasons": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get stock adjustment reason codes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AdjustmentReason"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Create stock adjustment reason code",
+                "parameters": [
+                    {
+                        "description": "Create reason payload",
+                        "name": "reason",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AdjustmentReason"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/inventory/stock": {
+            "get": {
+                "description": "In transit is the quantity sent to the store by transfers that weren't received yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get stock of a product per store",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ProductStock"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/kitchen/items/{id}/bump": {
+            "post": {
+                "description": "Moves an item one step on: queued, preparing, ready, served.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kitchen"
+                ],
+                "summary": "Bump kitchen item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kitchen item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.KitchenItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/kitchen/items/{id}/recall": {
+            "post": {
+                "description": "Moves an item one step back, e.g. after bumping it by mistake.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kitchen"
+                ],
+                "summary": "Recall kitchen item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kitchen item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.KitchenItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/kitchen/stations/{station}": {
+            "get": {
+                "description": "Items routed to a station, oldest first. Without status, everything not yet served.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Kitchen"
+                ],
+                "summary": "Get station queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Station, e.g. bar or kitchen",
+                        "name": "station",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status (queued, preparing, ready, served)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.KitchenItem"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/kitchen/stations/{station}/events": {
+            "get": {
+                "description": "Server-sent events stream with a kitchen_item event for every item routed to the station or changing status. Items are dropped when the client falls behind, poll the queue to catch up.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Kitchen"
+                ],
+                "summary": "Subscribe to station queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Station, e.g. bar or kitchen",
+                        "name": "station",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.KitchenItem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/modifier-groups": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifier Groups"
+                ],
+                "summary": "Get all modifier groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ModifierGroup"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "A sale picks between min_select and max_select modifiers of the group, a max_select of 0 allows any number.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifier Groups"
+                ],
+                "summary": "Create modifier group",
+                "parameters": [
+                    {
+                        "description": "Create modifier group payload",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateModifierGroupRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ModifierGroup"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/modifier-groups/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifier Groups"
+                ],
+                "summary": "Get modifier group by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Modifier group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ModifierGroup"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the group. Modifiers sent with their id keep it, modifiers left out are removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifier Groups"
+                ],
+                "summary": "Update modifier group by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Modifier group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update modifier group payload",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateModifierGroupRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Modifier Groups"
+                ],
+                "summary": "Delete modifier group by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Modifier group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/orders": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (open, held, paid, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Order"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Opens an empty ticket or cart. The X-User header is recorded as its creator.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Open order",
+                "parameters": [
+                    {
+                        "description": "Create order payload",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateOrderRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}": {
+            "get": {
+                "description": "Line subtotals and the total are priced at the current prices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/checkout": {
+            "post": {
+                "description": "Sells the lines of an open or held order through the regular checkout and marks the order paid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Check out order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Checkout options",
+                        "name": "checkout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CheckoutOrderRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/hold": {
+            "post": {
+                "description": "Parks an open order. Lines cannot change until it is recalled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Hold order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/lines": {
+            "post": {
+                "description": "Takes a product_id or a scanned barcode, like a checkout item.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Add line to order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order line payload",
+                        "name": "line",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderLineRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/lines/{lineID}": {
+            "put": {
+                "description": "Changes the quantity, modifiers or notes of a line. Omitted quantity and modifier_ids are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Update order line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order line ID",
+                        "name": "lineID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order line payload",
+                        "name": "line",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderLineRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Remove order line",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order line ID",
+                        "name": "lineID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/recall": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Recall held order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/send": {
+            "post": {
+                "description": "Routes the lines not sent yet to the station of their category. Sent lines cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Send order to preparation stations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/shares/{number}/pay": {
+            "post": {
+                "description": "Pays one share of an order split into equal shares. Only cash may be tendered above the share, the rest is change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Pay order share",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Share number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenders",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/orders/{id}/split": {
+            "post": {
+                "description": "Give line_ids to move those lines into a new order for the same table, which is returned. Give shares to sell the order now and divide its total into equal shares, the first shares take one extra of any remainder; the order is returned in status split and is paid once every share and split order is paid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Split order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Split payload",
+                        "name": "split",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderSplitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products": {
+            "get": {
+                "description": "Variants are nested under their parent product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get all products",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ProductResponseSwagger"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "A product with parent_id is a variant of that product and needs a value for each of its variant_attributes.\nA product with components is sold from their stock and holds none of its own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create product",
+                "parameters": [
+                    {
+                        "description": "Create product payload",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateProductRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/products/lookup": {
+            "get": {
+                "description": "Exact match as needed by a barcode scanner. UPC-A codes match their EAN-13 form.\nCodes of a configured in-store format (e.g. scale labels) resolve their PLU and also return the quantity and amount they carry.\nA pack barcode returns the product with the scanned pack.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Look up a product by barcode or SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "EAN-13 or UPC-A barcode",
+                        "name": "barcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock keeping unit",
+                        "name": "sku",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScannedProduct"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Update product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update product payload, fields left out are kept",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "description": "A product with history such as stock movements or sales is archived instead. It is no longer listed, looked up or sold, and its SKU, PLU and barcodes are free for other products.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Delete product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/purchase-orders": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Get purchase orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by supplier",
+                        "name": "supplier_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PurchaseOrder"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Create draft purchase order",
+                "parameters": [
+                    {
+                        "description": "Create purchase order payload",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreatePurchaseOrderRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/purchase-orders/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Get purchase order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrder"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Update draft purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update purchase order payload",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreatePurchaseOrderRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/purchase-orders/{id}/close": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Close purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/purchase-orders/{id}/receive": {
+            "post": {
+                "description": "Quantities may be more or less than ordered. The X-User header is recorded as the receiver.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Receive goods against purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Goods receipt payload",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReceivePurchaseOrderRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/purchase-orders/{id}/send": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Purchase Orders"
+                ],
+                "summary": "Mark purchase order as sent",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PurchaseOrder"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/bundle-sales": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get composite product sales with component consumption",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD in store time, or RFC 3339)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BundleSalesRow"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/expiring-lots": {
+            "get": {
+                "description": "Includes lots that already expired and still hold stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get lots expiring soon",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Expiring within this many days (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ExpiringLot"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/outstanding-purchase-orders": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get outstanding purchase orders per supplier",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OutstandingPurchaseOrders"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/reorder-suggestions": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get reorder suggestions based on average daily sales",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days of sales to average (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stock and sales of one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ReorderSuggestion"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/sales": {
+            "get": {
+                "description": "Quantity, sales count, gross, coupon discounts and net per group. Give top or bottom with group_by category or product to keep only that many groups with the highest or lowest net sales; totals always cover the whole range.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get sales report grouped by period, category or product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD in store time, or RFC 3339)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "hour, day, week, month, category or product (default day)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the N best selling groups",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only the N worst selling groups",
+                        "name": "bottom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "parent to report variants as their parent product",
+                        "name": "rollup",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SalesReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/shrinkage": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get shrinkage report by reason and period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD in store time, or RFC 3339)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default month)",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit to one store",
+                        "name": "store_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ShrinkageReportRow"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/rounding-rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rounding Rules"
+                ],
+                "summary": "Get all rounding rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RoundingRule"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/rounding-rules/{tender_type}": {
+            "put": {
+                "description": "When the last tender of a sale or share is of this type, the balance it settles is rounded to a multiple of increment: nearest (halves up), up or down. The difference is stored as the rounding_amount of the transaction. Tender types without a rule are not rounded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rounding Rules"
+                ],
+                "summary": "Set rounding rule of a tender type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tender type, e.g. cash",
+                        "name": "tender_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rounding rule payload",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SaveRoundingRuleRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RoundingRule"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rounding Rules"
+                ],
+                "summary": "Delete rounding rule of a tender type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tender type, e.g. cash",
+                        "name": "tender_type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/stock-takes": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Takes"
+                ],
+                "summary": "Get stock take sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockTake"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Snapshots the expected stock of every product in the selected categories.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Takes"
+                ],
+                "summary": "Open stock take session",
+                "parameters": [
+                    {
+                        "description": "Create stock take payload",
+                        "name": "stock_take",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateStockTakeRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTake"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-takes/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Takes"
+                ],
+                "summary": "Get stock take with variances",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock take ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTake"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-takes/{id}/cancel": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Takes"
+                ],
+                "summary": "Cancel stock take",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock take ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTake"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-takes/{id}/counts": {
+            "post": {
+                "description": "Counts for the same product add up across batches and scanners.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Takes"
+                ],
+                "summary": "Add a batch of counted quantities",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock take ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Count batch payload",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTakeCountBatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTake"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-takes/{id}/post": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock Takes"
+                ],
+                "summary": "Post stock take variances to stock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Stock take ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTake"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stores": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Get all stores",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Store"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "currency is the ISO code of the base currency the store prices, sells and reports in, IDR by default. timezone is the IANA timezone report dates are days in, Asia/Jakarta (WIB) by default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Create store",
+                "parameters": [
+                    {
+                        "description": "Create store payload",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateStoreRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/stores/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Get store by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stores"
+                ],
+                "summary": "Update store by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update store payload",
+                        "name": "store",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateStoreRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/suppliers": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Get all suppliers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Supplier"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Create supplier",
+                "parameters": [
+                    {
+                        "description": "Create supplier payload",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateSupplierRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/suppliers/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Get supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Update supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update supplier payload",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateSupplierRequestSwagger"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppliers"
+                ],
+                "summary": "Delete supplier by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/api/transfers": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Get stock transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "in_transit or received",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StockTransfer"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Stock leaves the sending store immediately and stays in transit until received. The X-User header is recorded as the sender.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Send stock to another store",
+                "parameters": [
+                    {
+                        "description": "Send transfer payload",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SendStockTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTransfer"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Get stock transfer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTransfer"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}/receive": {
+            "post": {
+                "description": "Books the transfer into the receiving store. Products left out of lines are received in full.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Receive stock transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Received quantities",
+                        "name": "receipt",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReceiveStockTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StockTransfer"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AdjustmentReason": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BarcodeFormat": {
+            "type": "object",
+            "properties": {
+                "code_length": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "value_decimals": {
+                    "description": "e.g. 3 when the weight is in grams and the product is priced per kg",
+                    "type": "integer"
+                },
+                "value_length": {
+                    "type": "integer"
+                },
+                "value_type": {
+                    "description": "weight or price",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BundleSalesRow": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity_sold": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Category": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "station": {
+                    "description": "preparation station its lines are routed to, empty for none",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CheckoutOrderRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "coupon_code": {
+                    "type": "string"
+                },
+                "customer": {
+                    "type": "string"
+                },
+                "override_expired_by": {
+                    "type": "string"
+                },
+                "tenders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Tender"
+                    }
+                }
+            }
+        },
+        "model.Coupon": {
+            "type": "object",
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
+                    "description": "percent or amount",
+                    "type": "integer"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_spend": {
+                    "description": "on the eligible lines",
+                    "type": "integer"
+                },
+                "per_customer_limit": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "times_used": {
+                    "type": "integer"
+                },
+                "usage_limit": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateBarcodeFormatRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "code_length": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Deli scale weight"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "21"
+                },
+                "value_decimals": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "value_length": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "value_type": {
+                    "type": "string",
+                    "example": "weight"
+                }
+            }
+        },
+        "model.CreateCategoryRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "station": {
+                    "type": "string",
+                    "example": "bar"
+                }
+            }
+        },
+        "model.CreateCouponRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "example": "HEMAT10"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string",
+                    "example": "percent"
+                },
+                "discount_value": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "min_spend": {
+                    "type": "integer"
+                },
+                "per_customer_limit": {
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateExchangeRateRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 16250
+                }
+            }
+        },
+        "model.CreateModifierGroupRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "max_select": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "min_select": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Modifier"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Milk"
+                }
+            }
+        },
+        "model.CreateOrderRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string",
+                    "example": "Table 4"
+                }
+            }
+        },
+        "model.CreateProductRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "min_stock": {
+                    "type": "number"
+                },
+                "modifier_group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductPack"
+                    }
+                },
+                "parent_id": {
+                    "description": "A variant names its parent and its value of each of the parent's\nvariant attributes. Its price may be left at 0 to sell at the\nparent's price.",
+                    "type": "integer"
+                },
+                "plu": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "type": {
+                    "description": "A gift card product sells a gift card worth its price per unit",
+                    "type": "string",
+                    "example": "standard"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "validity_days": {
+                    "type": "integer"
+                },
+                "variant_attributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.CreatePurchaseOrderItemSwagger": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity_ordered": {
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreatePurchaseOrderRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CreatePurchaseOrderItemSwagger"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateStockAdjustmentRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockAdjustmentLine"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateStockLotRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string",
+                    "example": "2026-12-31"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateStockTakeRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CreateStoreRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Jakarta"
+                }
+            }
+        },
+        "model.CreateSupplierRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "IDR"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 16250
+                }
+            }
+        },
+        "model.ExpiringLot": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "days_left": {
+                    "description": "negative when already expired",
+                    "type": "integer"
+                },
+                "expiry_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GiftCard": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GiftCardEntry"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "initial_balance": {
+                    "type": "integer"
+                },
+                "issued_by": {
+                    "type": "string"
+                },
+                "transaction_detail_id": {
+                    "type": "integer"
+                },
+                "validity_days": {
+                    "description": "when issuing, sets ExpiresAt",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GiftCardEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "balance_after": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GoodsReceiptLine": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "pack": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.IssueGiftCardRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "initial_balance": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "validity_days": {
+                    "type": "integer",
+                    "example": 365
+                }
+            }
+        },
+        "model.KitchenItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "pack": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "station": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LowStockProduct": {
+            "type": "object",
+            "properties": {
+                "min_stock": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "store_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Modifier": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price_delta": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ModifierGroup": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "max_select": {
+                    "type": "integer"
+                },
+                "min_select": {
+                    "type": "integer"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Modifier"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Order": {
+            "type": "object",
+            "properties": {
+                "child_ids": {
+                    "description": "orders split off by lines",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderLine"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "the order it was split from",
+                    "type": "integer"
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderShare"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "at current prices",
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "description": "the sale of the order's lines",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OrderLine": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "description": "resolved when the line is added",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modifier_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionDetailModifier"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "pack": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "sent_at": {
+                    "description": "routed to its preparation station",
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OrderLineRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "modifier_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "pack": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.OrderShare": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Payment"
+                    }
+                }
+            }
+        },
+        "model.OrderSplitRequest": {
+            "type": "object",
+            "properties": {
+                "line_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "shares": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OutstandingPurchaseOrders": {
+            "type": "object",
+            "properties": {
+                "open_orders": {
+                    "type": "integer"
+                },
+                "outstanding_quantity": {
+                    "type": "number"
+                },
+                "outstanding_value": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PayRequest": {
+            "type": "object",
+            "properties": {
+                "tenders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Tender"
+                    }
+                }
+            }
+        },
+        "model.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "foreign_tendered": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "share_id": {
+                    "type": "integer"
+                },
+                "tendered": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Product": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "of a variant, a value per attribute of the parent",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "barcodes": {
+                    "description": "EAN-13, UPC-A codes are stored with a leading zero",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "$ref": "#/definitions/model.Category"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Components make a composite product such as a bundle or recipe. It\nholds no stock of its own, Stock is how many its components allow.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inherits_price": {
+                    "description": "the variant sells at the parent's price",
+                    "type": "boolean"
+                },
+                "min_stock": {
+                    "type": "number"
+                },
+                "modifier_group_ids": {
+                    "description": "ModifierGroupIDs attaches modifier groups, ModifierGroups lists them\nwith their modifiers",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "modifier_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ModifierGroup"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "packs": {
+                    "description": "packs and cases the product is also sold or received in",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductPack"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "plu": {
+                    "description": "item code in scale and other in-store barcodes",
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "per unit",
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "type": {
+                    "description": "standard or gift_card, fixed once created",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "pcs, kg, g, l or m, defaults to pcs",
+                    "type": "string"
+                },
+                "validity_days": {
+                    "description": "ValidityDays is how long the gift cards a gift card product issues\nstay valid, nil for no expiry",
+                    "type": "integer"
+                },
+                "variant_attributes": {
+                    "description": "of a parent, e.g. size and color",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Product"
+                    }
+                }
+            }
+        },
+        "model.ProductComponent": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProductPack": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "factor": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProductResponseSwagger": {
+            "type": "object",
+            "properties": {
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_stock": {
+                    "type": "number"
+                },
+                "modifier_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ModifierGroup"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductPack"
+                    }
+                },
+                "plu": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "variant_attributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductVariantSwagger"
+                    }
+                }
+            }
+        },
+        "model.ProductStock": {
+            "type": "object",
+            "properties": {
+                "in_transit": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "store_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProductVariantSwagger": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inherits_price": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductPack"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PurchaseOrderItem"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "description": "store the goods are delivered to",
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "integer"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PurchaseOrderItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "integer"
+                },
+                "quantity_ordered": {
+                    "type": "number"
+                },
+                "quantity_received": {
+                    "type": "number"
+                },
+                "unit_cost": {
+                    "description": "per unit of the product",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReceivePurchaseOrderRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GoodsReceiptLine"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ReceiveStockTransferRequest": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockTransferLine"
+                    }
+                }
+            }
+        },
+        "model.ReorderSuggestion": {
+            "type": "object",
+            "properties": {
+                "average_daily_sales": {
+                    "type": "number"
+                },
+                "days_of_cover": {
+                    "description": "nil when the product didn't sell",
+                    "type": "number"
+                },
+                "min_stock": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "suggested_quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.RoundingRule": {
+            "type": "object",
+            "properties": {
+                "increment": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "nearest"
+                },
+                "tender_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SalesReport": {
+            "type": "object",
+            "properties": {
+                "group_by": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SalesReportRow"
+                    }
+                },
+                "totals": {
+                    "description": "of the whole range, also when rows are limited",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SalesReportRow"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.SalesReportRow": {
+            "type": "object",
+            "properties": {
+                "discounts": {
+                    "type": "integer"
+                },
+                "gross": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "category or product, none for uncategorized",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "net": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "transactions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SaveRoundingRuleRequestSwagger": {
+            "type": "object",
+            "properties": {
+                "increment": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "nearest"
+                }
+            }
+        },
+        "model.ScannedProduct": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "attributes": {
+                    "description": "of a variant, a value per attribute of the parent",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "barcodes": {
+                    "description": "EAN-13, UPC-A codes are stored with a leading zero",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "$ref": "#/definitions/model.Category"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "components": {
+                    "description": "Components make a composite product such as a bundle or recipe. It\nholds no stock of its own, Stock is how many its components allow.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inherits_price": {
+                    "description": "the variant sells at the parent's price",
+                    "type": "boolean"
+                },
+                "min_stock": {
+                    "type": "number"
+                },
+                "modifier_group_ids": {
+                    "description": "ModifierGroupIDs attaches modifier groups, ModifierGroups lists them\nwith their modifiers",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "modifier_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ModifierGroup"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pack": {
+                    "$ref": "#/definitions/model.ProductPack"
+                },
+                "packs": {
+                    "description": "packs and cases the product is also sold or received in",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductPack"
+                    }
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "plu": {
+                    "description": "item code in scale and other in-store barcodes",
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "per unit",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "number"
+                },
+                "type": {
+                    "description": "standard or gift_card, fixed once created",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "pcs, kg, g, l or m, defaults to pcs",
+                    "type": "string"
+                },
+                "validity_days": {
+                    "description": "ValidityDays is how long the gift cards a gift card product issues\nstay valid, nil for no expiry",
+                    "type": "integer"
+                },
+                "variant_attributes": {
+                    "description": "of a parent, e.g. size and color",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Product"
+                    }
+                }
+            }
+        },
+        "model.SendStockTransferRequest": {
+            "type": "object",
+            "properties": {
+                "from_store_id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockTransferLine"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "to_store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ShrinkageReportRow": {
+            "type": "object",
+            "properties": {
+                "period": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reason_code": {
+                    "type": "string"
+                },
+                "reason_name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StockAdjustment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockAdjustmentLine"
+                    }
+                },
+                "movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockMovement"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StockAdjustmentLine": {
+            "type": "object",
+            "properties": {
+                "lot_id": {
+                    "description": "optional lot the quantity is taken from or returned to",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "always positive, the sign comes from the reason direction",
+                    "type": "number"
+                }
+            }
+        },
+        "model.StockLot": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "goods_receipt_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StockMovement": {
+            "type": "object",
+            "properties": {
+                "balance_after": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference_id": {
+                    "type": "integer"
+                },
+                "reference_type": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StockTake": {
+            "type": "object",
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockTakeItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StockTakeCountBatch": {
+            "type": "object",
+            "properties": {
+                "device": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockTakeCountLine"
+                    }
+                }
+            }
+        },
+        "model.StockTakeCountLine": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.StockTakeItem": {
+            "type": "object",
+            "properties": {
+                "counted_qty": {
+                    "type": "number"
+                },
+                "expected_qty": {
+                    "type": "number"
+                },
+                "last_counted_at": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "snapshot_qty": {
+                    "type": "number"
+                },
+                "variance": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.StockTransfer": {
+            "type": "object",
+            "properties": {
+                "from_store_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StockTransferItem"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "received_by": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "sent_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_store_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StockTransferItem": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity_received": {
+                    "type": "number"
+                },
+                "quantity_sent": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.StockTransferLine": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.Store": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency is the base currency the store prices, sells and reports in",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA timezone report dates are days in",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Supplier": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Tender": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "the base currency when empty",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "reference": {
+                    "description": "e.g. card approval code",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "cash"
+                }
+            }
+        },
+        "model.Transaction": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "integer"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "base currency of the store",
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionDetail"
+                    }
+                },
+                "discount_amount": {
+                    "type": "integer"
+                },
+                "expired_override_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Payment"
+                    }
+                },
+                "rounding_amount": {
+                    "description": "paid on top of TotalAmount",
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "total_amount": {
+                    "description": "net of DiscountAmount",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransactionDetail": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "description": "Components is what a composite product line took from the stock of\nits components, the line itself takes none",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "discount": {
+                    "description": "share of the coupon discount, not taken off Subtotal",
+                    "type": "integer"
+                },
+                "gift_cards": {
+                    "description": "GiftCards issued by a gift card line, one per unit",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GiftCard"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionDetailLot"
+                    }
+                },
+                "modifiers": {
+                    "description": "Modifiers picked for the line, their price deltas are in Subtotal",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TransactionDetailModifier"
+                    }
+                },
+                "pack": {
+                    "description": "Pack and PackQuantity are set when the line was sold in packs;\nQuantity is always in the product's unit",
+                    "type": "string"
+                },
+                "pack_quantity": {
+                    "type": "number"
+                },
+                "parent_id": {
+                    "description": "of a variant, for rolling up to the parent",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TransactionDetailLot": {
+            "type": "object",
+            "properties": {
+                "batch_number": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "lot_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.TransactionDetailModifier": {
+            "type": "object",
+            "properties": {
+                "group_name": {
+                    "type": "string"
+                },
+                "modifier_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price_delta": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Transaction"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.UpdateProductRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "barcodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductComponent"
+                    }
+                },
+                "min_stock": {
+                    "description": "0 turns off low-stock alerts",
+                    "type": "number"
+                },
+                "modifier_group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
+                "packs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProductPack"
+                    }
+                },
+                "plu": {
+                    "type": "integer"
+                },
                 "price": {
+                    "description": "0 sells a variant at the parent's price",
                     "type": "integer"
                 },
-                "stock": {
+                "reorder_qty": {
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "validity_days": {
+                    "description": "0 removes the expiry",
                     "type": "integer"
+                },
+                "variant_attributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
@@ -338,7 +5338,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "Go Cashier API",
-	Description:      "This is a sample API for a cashier system.",
+	Description:      "This is a sample API for a cashier system.\nEvery /api request is scoped to the tenant resolved from its \"Authorization: Bearer <api key>\" header or, without one, its Host header.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
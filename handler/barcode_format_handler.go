@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type BarcodeFormatHandler struct {
+	service service.BarcodeFormatService
+}
+
+func NewBarcodeFormatHandler(s service.BarcodeFormatService) *BarcodeFormatHandler {
+	return &BarcodeFormatHandler{service: s}
+}
+
+func (h *BarcodeFormatHandler) HandleBarcodeFormats(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *BarcodeFormatHandler) HandleBarcodeFormatByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.update(w, r)
+	case http.MethodDelete:
+		h.delete(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get all in-store barcode formats
+// @Tags Barcode Formats
+// @Produce json
+// @Success 200 {array} model.BarcodeFormat
+// @Router /api/barcode-formats [get]
+func (h *BarcodeFormatHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	formats, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch barcode formats")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, formats)
+}
+
+// create godoc
+// @Summary Create in-store barcode format
+// @Description Describes EAN-13 labels that embed a PLU and a weight or price. The 12 digits before the check digit are the prefix, the PLU, any unused digits and the value.
+// @Tags Barcode Formats
+// @Accept json
+// @Produce json
+// @Param format body model.CreateBarcodeFormatRequestSwagger true "Create barcode format payload"
+// @Success 201 {object} model.BarcodeFormat
+// @Router /api/barcode-formats [post]
+func (h *BarcodeFormatHandler) create(w http.ResponseWriter, r *http.Request) {
+	var format model.BarcodeFormat
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&format); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &format); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, format)
+}
+
+// update godoc
+// @Summary Update in-store barcode format by ID
+// @Tags Barcode Formats
+// @Accept json
+// @Produce json
+// @Param id path int true "Barcode format ID"
+// @Param format body model.CreateBarcodeFormatRequestSwagger true "Update barcode format payload"
+// @Router /api/barcode-formats/{id} [put]
+func (h *BarcodeFormatHandler) update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/barcode-formats/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid barcode format ID")
+		return
+	}
+
+	var format model.BarcodeFormat
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&format); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &format); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Barcode format updated successfully",
+		"data":    format,
+	})
+}
+
+// delete godoc
+// @Summary Delete in-store barcode format by ID
+// @Tags Barcode Formats
+// @Produce json
+// @Param id path int true "Barcode format ID"
+// @Router /api/barcode-formats/{id} [delete]
+func (h *BarcodeFormatHandler) delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/barcode-formats/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid barcode format ID")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Barcode format deleted successfully"})
+}
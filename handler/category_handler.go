@@ -22,7 +22,7 @@ func NewCategoryHandler(s service.CategoryService) *CategoryHandler {
 func (h *CategoryHandler) HandleCategories(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
-		h.getAll(w)
+		h.getAll(w, r)
 	case http.MethodPost:
 		h.create(w, r)
 	default:
@@ -50,8 +50,8 @@ func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Requ
 // @Produce json
 // @Success 200 {array} model.Category
 // @Router /api/categories [get]
-func (h *CategoryHandler) getAll(w http.ResponseWriter) {
-	categories, err := h.service.GetAll()
+func (h *CategoryHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.service.GetAll(r.Context())
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to fetch categories")
 		return
@@ -92,7 +92,7 @@ func (h *CategoryHandler) create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Create(&newCategory); err != nil {
+	if err := h.service.Create(r.Context(), &newCategory); err != nil {
 		// Map service errors to appropriate HTTP status codes
 		statusCode := http.StatusBadRequest
 		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "exists") {
@@ -124,7 +124,7 @@ func (h *CategoryHandler) getByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Fetch category by ID
-	category, err := h.service.GetByID(id)
+	category, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
 		// Differentiate between not found and internal errors
 		if strings.Contains(err.Error(), "not found") {
@@ -170,7 +170,7 @@ func (h *CategoryHandler) update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update category
-	err = h.service.Update(id, &category)
+	err = h.service.Update(r.Context(), id, &category)
 	if err != nil {
 		// Map errors to appropriate status codes
 		statusCode := http.StatusBadRequest
@@ -183,7 +183,7 @@ func (h *CategoryHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Return success response - optionally fetch updated category
-	updatedCategory, _ := h.service.GetByID(id)
+	updatedCategory, _ := h.service.GetByID(r.Context(), id)
 	response.JSON(w, http.StatusOK, map[string]interface{}{
 		"message": "Category updated successfully",
 		"data":    updatedCategory,
@@ -206,7 +206,7 @@ func (h *CategoryHandler) delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete category
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(r.Context(), id); err != nil {
 		statusCode := http.StatusBadRequest
 		if strings.Contains(err.Error(), "not found") {
 			statusCode = http.StatusNotFound
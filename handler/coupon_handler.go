@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type CouponHandler struct {
+	service service.CouponService
+}
+
+func NewCouponHandler(s service.CouponService) *CouponHandler {
+	return &CouponHandler{service: s}
+}
+
+func (h *CouponHandler) HandleCoupons(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *CouponHandler) HandleCouponByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r)
+	case http.MethodPut:
+		h.update(w, r)
+	case http.MethodDelete:
+		h.delete(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get all coupons
+// @Tags Coupons
+// @Produce json
+// @Success 200 {array} model.Coupon
+// @Router /api/coupons [get]
+func (h *CouponHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	coupons, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch coupons")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, coupons)
+}
+
+// create godoc
+// @Summary Create coupon
+// @Description Codes are case-insensitive. discount_type is percent (discount_value 1-100) or fixed (an amount). The coupon applies to the listed products, their variants and the listed categories, or to every line when none are listed; min_spend is on those lines. A usage_limit or per_customer_limit of 0 is unlimited.
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param coupon body model.CreateCouponRequestSwagger true "Create coupon payload"
+// @Success 201 {object} model.Coupon
+// @Router /api/coupons [post]
+func (h *CouponHandler) create(w http.ResponseWriter, r *http.Request) {
+	var coupon model.Coupon
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&coupon); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &coupon); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, coupon)
+}
+
+// getByID godoc
+// @Summary Get coupon by ID
+// @Tags Coupons
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Success 200 {object} model.Coupon
+// @Router /api/coupons/{id} [get]
+func (h *CouponHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/coupons/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	coupon, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, coupon)
+}
+
+// update godoc
+// @Summary Update coupon by ID
+// @Description Replaces the coupon, the usage count is kept.
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Param coupon body model.CreateCouponRequestSwagger true "Update coupon payload"
+// @Router /api/coupons/{id} [put]
+func (h *CouponHandler) update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/coupons/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	var coupon model.Coupon
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&coupon); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &coupon); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Coupon updated successfully",
+		"data":    coupon,
+	})
+}
+
+// delete godoc
+// @Summary Delete coupon by ID
+// @Description Only a coupon that was never redeemed can be deleted.
+// @Tags Coupons
+// @Produce json
+// @Param id path int true "Coupon ID"
+// @Router /api/coupons/{id} [delete]
+func (h *CouponHandler) delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/coupons/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "cannot be deleted") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Coupon deleted successfully"})
+}
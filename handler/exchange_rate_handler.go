@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type ExchangeRateHandler struct {
+	service service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(s service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: s}
+}
+
+func (h *ExchangeRateHandler) HandleExchangeRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get exchange rates
+// @Description Lists the rates of every currency, latest first. The rate in force is the latest one whose effective_from has passed.
+// @Tags Exchange Rates
+// @Produce json
+// @Param currency query string false "Only rates of this currency, e.g. USD"
+// @Success 200 {array} model.ExchangeRate
+// @Router /api/exchange-rates [get]
+func (h *ExchangeRateHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.GetAll(r.Context(), r.URL.Query().Get("currency"))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch exchange rates")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rates)
+}
+
+// create godoc
+// @Summary Create exchange rate
+// @Description One unit of currency is worth rate units of base_currency (IDR by default) from effective_from (now by default) on. Foreign cash tenders are converted at the rate in force at checkout. The X-User header is recorded as the creator.
+// @Tags Exchange Rates
+// @Accept json
+// @Produce json
+// @Param rate body model.CreateExchangeRateRequestSwagger true "Create exchange rate payload"
+// @Success 201 {object} model.ExchangeRate
+// @Router /api/exchange-rates [post]
+func (h *ExchangeRateHandler) create(w http.ResponseWriter, r *http.Request) {
+	var rate model.ExchangeRate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&rate); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	rate.CreatedBy = requestUser(r)
+
+	if err := h.service.Create(r.Context(), &rate); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, rate)
+}
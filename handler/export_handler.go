@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/export"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type ExportHandler struct {
+	service service.ExportService
+}
+
+func NewExportHandler(service service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportTransactions godoc
+// @Summary Export sales as CSV or XLSX
+// @Description One row per sale, streamed from the database. Times are in store time. Give locale to format numbers for a spreadsheet; without it numbers are plain, e.g. 1234.5.
+// @Tags Exports
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv or xlsx (default csv)"
+// @Param locale query string false "en or id to format numbers in CSV"
+// @Param start_date query string true "Start date (YYYY-MM-DD in store time, or RFC 3339)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {file} file
+// @Router /api/exports/transactions [get]
+func (h *ExportHandler) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, "transactions", h.service.Transactions)
+}
+
+// ExportTransactionDetails godoc
+// @Summary Export sold lines as CSV or XLSX
+// @Description One row per sold line with its sale, streamed from the database. Net is the subtotal less the line's share of the coupon discount.
+// @Tags Exports
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv or xlsx (default csv)"
+// @Param locale query string false "en or id to format numbers in CSV"
+// @Param start_date query string true "Start date (YYYY-MM-DD in store time, or RFC 3339)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {file} file
+// @Router /api/exports/transaction-details [get]
+func (h *ExportHandler) ExportTransactionDetails(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, "transaction-details", h.service.TransactionDetails)
+}
+
+// ExportReport godoc
+// @Summary Export a report as CSV or XLSX
+// @Description Takes the parameters of the report's /api/reports endpoint. Bundle sales are written one row per component.
+// @Tags Exports
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param name path string true "sales, shrinkage, bundle-sales, reorder-suggestions, expiring-lots or outstanding-purchase-orders"
+// @Param format query string false "csv or xlsx (default csv)"
+// @Param locale query string false "en or id to format numbers in CSV"
+// @Param start_date query string false "Start date of sales, shrinkage and bundle-sales"
+// @Param end_date query string false "End date of sales, shrinkage and bundle-sales"
+// @Param store_id query int false "Limit to one store"
+// @Param group_by query string false "Grouping of sales"
+// @Param top query int false "Only the N best selling groups of sales"
+// @Param bottom query int false "Only the N worst selling groups of sales"
+// @Param rollup query string false "parent to report variants as their parent product"
+// @Param period query string false "Period of shrinkage"
+// @Param days query int false "Days of reorder-suggestions and expiring-lots (default 30)"
+// @Success 200 {file} file
+// @Router /api/exports/reports/{name} [get]
+func (h *ExportHandler) ExportReport(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/exports/reports/")
+	h.export(w, r, name, func(ctx context.Context, query model.ExportQuery, w io.Writer) error {
+		return h.service.Report(ctx, name, query, w)
+	})
+}
+
+// export parses the export parameters and runs write. Headers are only sent
+// once write produces output, so errors found before that, including a
+// query that fails before its first row, are answered as JSON. An error
+// after that is logged and the connection is aborted, so the client sees a
+// failed download rather than a short file with a 200.
+func (h *ExportHandler) export(w http.ResponseWriter, r *http.Request, name string,
+	write func(ctx context.Context, query model.ExportQuery, w io.Writer) error) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query, err := exportQueryParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out := &exportWriter{
+		w:           w,
+		contentType: export.ContentType(query.Format),
+		filename:    name + "." + query.Format,
+	}
+	err = write(r.Context(), query, out)
+	if err != nil && out.started {
+		log.Printf("Export %s failed after it started: %v", name, err)
+		panic(http.ErrAbortHandler)
+	}
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must") ||
+			strings.Contains(err.Error(), "cannot") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+	}
+}
+
+// exportQueryParams reads the query string of an export
+func exportQueryParams(r *http.Request) (model.ExportQuery, error) {
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		return model.ExportQuery{}, err
+	}
+
+	params := r.URL.Query()
+	query := model.ExportQuery{
+		Format:    strings.ToLower(params.Get("format")),
+		Locale:    strings.ToLower(params.Get("locale")),
+		StartDate: params.Get("start_date"),
+		EndDate:   params.Get("end_date"),
+		StoreID:   storeID,
+		GroupBy:   strings.ToLower(params.Get("group_by")),
+		Period:    params.Get("period"),
+		RollUp:    rollUpParam(r),
+		Days:      30,
+	}
+	if query.Format == "" {
+		query.Format = export.CSV
+	}
+	if top := params.Get("top"); top != "" {
+		query.Top, err = strconv.Atoi(top)
+		if err != nil {
+			return model.ExportQuery{}, errors.New("Invalid top")
+		}
+	}
+	if bottom := params.Get("bottom"); bottom != "" {
+		query.Bottom, err = strconv.Atoi(bottom)
+		if err != nil {
+			return model.ExportQuery{}, errors.New("Invalid bottom")
+		}
+	}
+	if days := params.Get("days"); days != "" {
+		query.Days, err = strconv.Atoi(days)
+		if err != nil {
+			return model.ExportQuery{}, errors.New("Invalid days")
+		}
+	}
+	return query, nil
+}
+
+// exportWriter sends the file headers with the first write of an export
+type exportWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", e.contentType)
+		e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
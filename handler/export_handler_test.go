@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/export"
+)
+
+// fakeExports writes rows of a one column table and then fails with err,
+// as a query that breaks while its rows are read would
+type fakeExports struct {
+	rows int
+	err  error
+}
+
+func (f fakeExports) Transactions(ctx context.Context, query model.ExportQuery, w io.Writer) error {
+	writer, err := export.NewWriter(w, query.Format, query.Locale, "Transactions", []string{"id"})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < f.rows; i++ {
+		if err := writer.Write([]interface{}{i}); err != nil {
+			return err
+		}
+	}
+	if f.err != nil {
+		return f.err
+	}
+	return writer.Close()
+}
+
+func (f fakeExports) TransactionDetails(ctx context.Context, query model.ExportQuery, w io.Writer) error {
+	return f.Transactions(ctx, query, w)
+}
+
+func (f fakeExports) Report(ctx context.Context, name string, query model.ExportQuery, w io.Writer) error {
+	return f.Transactions(ctx, query, w)
+}
+
+func TestExportErrors(t *testing.T) {
+	queryFailed := errors.New("failed to get transactions: connection reset")
+
+	tests := []struct {
+		name       string
+		exports    fakeExports
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"csv", fakeExports{rows: 2}, "", http.StatusOK, "id\n0\n1\n"},
+		{"empty csv has the header", fakeExports{}, "", http.StatusOK, "id\n"},
+		{"query fails before the first row", fakeExports{err: queryFailed}, "", http.StatusInternalServerError, "connection reset"},
+		{"xlsx query fails before the first row", fakeExports{err: queryFailed}, "?format=xlsx", http.StatusInternalServerError, "connection reset"},
+		{"buffered rows are not sent", fakeExports{rows: 10, err: queryFailed}, "", http.StatusInternalServerError, "connection reset"},
+		{"invalid format", fakeExports{}, "?format=pdf", http.StatusBadRequest, "invalid format"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewExportHandler(tc.exports)
+			w := httptest.NewRecorder()
+			h.ExportTransactions(w, httptest.NewRequest(http.MethodGet, "/api/exports/transactions"+tc.query, nil))
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tc.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", w.Body, tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestExportAbortsAfterItStarted(t *testing.T) {
+	// More rows than the writer buffers, so the file has started
+	h := NewExportHandler(fakeExports{rows: 10000, err: errors.New("connection reset")})
+	w := httptest.NewRecorder()
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", r)
+		}
+		if w.Code != http.StatusOK || w.Header().Get("Content-Disposition") == "" {
+			t.Errorf("status = %d, headers = %v, want the file to have started", w.Code, w.Header())
+		}
+	}()
+	h.ExportTransactions(w, httptest.NewRequest(http.MethodGet, "/api/exports/transactions", nil))
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// GiftCardHandler handles HTTP requests for gift cards
+type GiftCardHandler struct {
+	service service.GiftCardService
+}
+
+// NewGiftCardHandler creates a new GiftCardHandler with the given GiftCardService
+func NewGiftCardHandler(service service.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{service: service}
+}
+
+// HandleGiftCards - GET/POST /api/gift-cards
+func (h *GiftCardHandler) HandleGiftCards(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.issue(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleGiftCardByCode - GET /api/gift-cards/{code}
+func (h *GiftCardHandler) HandleGiftCardByCode(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, "/api/gift-cards/")
+	if code == "" || strings.Contains(code, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.lookup(w, r, code)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get gift cards
+// @Tags Gift Cards
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.GiftCard
+// @Router /api/gift-cards [get]
+func (h *GiftCardHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	cards, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch gift cards")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, cards)
+}
+
+// issue godoc
+// @Summary Issue store-credit voucher
+// @Description Issues a gift card outside of a sale, e.g. for a refund. The code is generated and the X-User header is recorded as the issuer. Gift cards are sold through checkout with a gift_card product.
+// @Tags Gift Cards
+// @Accept json
+// @Produce json
+// @Param card body model.IssueGiftCardRequestSwagger true "Issue gift card payload"
+// @Success 201 {object} model.GiftCard
+// @Router /api/gift-cards [post]
+func (h *GiftCardHandler) issue(w http.ResponseWriter, r *http.Request) {
+	var card model.GiftCard
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&card); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	card.IssuedBy = requestUser(r)
+
+	if err := h.service.Issue(r.Context(), &card); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, card)
+}
+
+// lookup godoc
+// @Summary Look up gift card by code
+// @Description Returns the balance, expiry and balance ledger of a card. Spaces and dashes in the code are ignored.
+// @Tags Gift Cards
+// @Accept json
+// @Produce json
+// @Param code path string true "Gift card code"
+// @Success 200 {object} model.GiftCard
+// @Router /api/gift-cards/{code} [get]
+func (h *GiftCardHandler) lookup(w http.ResponseWriter, r *http.Request, code string) {
+	card, err := h.service.Lookup(r.Context(), code)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Gift card not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch gift card")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, card)
+}
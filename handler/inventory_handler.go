@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"encoding/json" //Encode/decode JSON  API response
+	"net/http"      //HTTP server & request handling
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// InventoryHandler handles HTTP requests for stock operations
+type InventoryHandler struct {
+	service service.InventoryService
+}
+
+// NewInventoryHandler creates a new InventoryHandler with the given InventoryService
+func NewInventoryHandler(service service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+// HandleMovements - GET /api/inventory/movements?product_id={id}
+func (h *InventoryHandler) HandleMovements(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getMovements(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleAdjustmentReasons - GET/POST /api/inventory/reasons
+func (h *InventoryHandler) HandleAdjustmentReasons(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAdjustmentReasons(w, r)
+	case http.MethodPost:
+		h.createAdjustmentReason(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleAdjustments - POST /api/inventory/adjustments
+func (h *InventoryHandler) HandleAdjustments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.createAdjustment(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleLowStock - GET /api/inventory/low-stock
+func (h *InventoryHandler) HandleLowStock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getLowStock(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleLots - GET /api/inventory/lots?product_id={id}, POST /api/inventory/lots
+func (h *InventoryHandler) HandleLots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getLots(w, r)
+	case http.MethodPost:
+		h.createLot(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getMovements godoc
+// @Summary Get stock movement history of a product
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param product_id query int true "Product ID"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {array} model.StockMovement
+// @Router /api/inventory/movements [get]
+func (h *InventoryHandler) getMovements(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil || productID <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	movements, err := h.service.GetMovements(r.Context(), productID, storeID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Product not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch stock movements")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, movements)
+}
+
+// getAdjustmentReasons godoc
+// @Summary Get stock adjustment reason codes
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.AdjustmentReason
+// @Router /api/inventory/reasons [get]
+func (h *InventoryHandler) getAdjustmentReasons(w http.ResponseWriter, r *http.Request) {
+	reasons, err := h.service.GetAdjustmentReasons(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch adjustment reasons")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, reasons)
+}
+
+// createAdjustmentReason godoc
+// @Summary Create stock adjustment reason code
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param reason body model.AdjustmentReason true "Create reason payload"
+// @Router /api/inventory/reasons [post]
+func (h *InventoryHandler) createAdjustmentReason(w http.ResponseWriter, r *http.Request) {
+	var reason model.AdjustmentReason
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&reason); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.CreateAdjustmentReason(r.Context(), &reason); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, reason)
+}
+
+// createAdjustment godoc
+// @Summary Create stock adjustment
+// @Description Applies all lines atomically. The X-User header is recorded as the author.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param adjustment body model.CreateStockAdjustmentRequestSwagger true "Create adjustment payload"
+// @Success 201 {object} model.StockAdjustment
+// @Router /api/inventory/adjustments [post]
+func (h *InventoryHandler) createAdjustment(w http.ResponseWriter, r *http.Request) {
+	var adjustment model.StockAdjustment
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&adjustment); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	adjustment.CreatedBy = requestUser(r)
+
+	if err := h.service.CreateAdjustment(r.Context(), &adjustment); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, adjustment)
+}
+
+// GetShrinkageReport godoc
+// @Summary Get shrinkage report by reason and period
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param start_date query string true "Start date (YYYY-MM-DD in store time, or RFC 3339)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp"
+// @Param period query string false "day, week or month (default month)"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {array} model.ShrinkageReportRow
+// @Router /api/reports/shrinkage [get]
+func (h *InventoryHandler) GetShrinkageReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.service.GetShrinkageReport(r.Context(), query.Get("start_date"), query.Get("end_date"), query.Get("period"), storeID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
+
+// GetBundleSalesReport godoc
+// @Summary Get composite product sales with component consumption
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param start_date query string true "Start date (YYYY-MM-DD in store time, or RFC 3339)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {array} model.BundleSalesRow
+// @Router /api/reports/bundle-sales [get]
+func (h *InventoryHandler) GetBundleSalesReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.service.GetBundleSalesReport(r.Context(), query.Get("start_date"), query.Get("end_date"), storeID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
+
+// getLowStock godoc
+// @Summary Get products at or below their reorder point
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {array} model.LowStockProduct
+// @Router /api/inventory/low-stock [get]
+func (h *InventoryHandler) getLowStock(w http.ResponseWriter, r *http.Request) {
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products, err := h.service.GetLowStock(r.Context(), storeID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch low stock products")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, products)
+}
+
+// GetReorderSuggestions godoc
+// @Summary Get reorder suggestions based on average daily sales
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param days query int false "Number of days of sales to average (default 30)"
+// @Param store_id query int false "Stock and sales of one store"
+// @Success 200 {array} model.ReorderSuggestion
+// @Router /api/reports/reorder-suggestions [get]
+func (h *InventoryHandler) GetReorderSuggestions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		days, err = strconv.Atoi(d)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid days")
+			return
+		}
+	}
+
+	suggestions, err := h.service.GetReorderSuggestions(r.Context(), days, storeID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "must") || strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, suggestions)
+}
+
+// getLots godoc
+// @Summary Get stock lots of a product in FEFO order
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param product_id query int true "Product ID"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {array} model.StockLot
+// @Router /api/inventory/lots [get]
+func (h *InventoryHandler) getLots(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil || productID <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lots, err := h.service.GetLots(r.Context(), productID, storeID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Product not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch stock lots")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, lots)
+}
+
+// createLot godoc
+// @Summary Receive stock into a new lot
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param lot body model.CreateStockLotRequestSwagger true "Create lot payload"
+// @Success 201 {object} model.StockLot
+// @Router /api/inventory/lots [post]
+func (h *InventoryHandler) createLot(w http.ResponseWriter, r *http.Request) {
+	var lot model.StockLot
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&lot); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.CreateLot(r.Context(), &lot, requestUser(r)); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, lot)
+}
+
+// GetExpiringLots godoc
+// @Summary Get lots expiring soon
+// @Description Includes lots that already expired and still hold stock.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param days query int false "Expiring within this many days (default 30)"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {array} model.ExpiringLot
+// @Router /api/reports/expiring-lots [get]
+func (h *InventoryHandler) GetExpiringLots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		var err error
+		days, err = strconv.Atoi(d)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid days")
+			return
+		}
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lots, err := h.service.GetExpiringLots(r.Context(), days, storeID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "cannot") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, lots)
+}
+
+// requestUser returns the name of the user performing the request
+func requestUser(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get("X-User"))
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// KitchenHandler handles HTTP requests from kitchen displays
+type KitchenHandler struct {
+	service service.KitchenService
+}
+
+// NewKitchenHandler creates a new KitchenHandler with the given KitchenService
+func NewKitchenHandler(service service.KitchenService) *KitchenHandler {
+	return &KitchenHandler{service: service}
+}
+
+// HandleStation - GET /api/kitchen/stations/{station} and
+// GET /api/kitchen/stations/{station}/events
+func (h *KitchenHandler) HandleStation(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/kitchen/stations/")
+	station, action, _ := strings.Cut(path, "/")
+	if station == "" {
+		response.Error(w, http.StatusBadRequest, "Invalid station")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getQueue(w, r, station)
+	case action == "events" && r.Method == http.MethodGet:
+		h.subscribe(w, r, station)
+	case action != "" && action != "events":
+		http.NotFound(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleItemByID - POST /api/kitchen/items/{id}/{bump|recall}
+func (h *KitchenHandler) HandleItemByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/kitchen/items/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid kitchen item ID")
+		return
+	}
+
+	switch {
+	case action == "bump" && r.Method == http.MethodPost:
+		h.bump(w, r, id)
+	case action == "recall" && r.Method == http.MethodPost:
+		h.recall(w, r, id)
+	case action != "bump" && action != "recall":
+		http.NotFound(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getQueue godoc
+// @Summary Get station queue
+// @Description Items routed to a station, oldest first. Without status, everything not yet served.
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Param station path string true "Station, e.g. bar or kitchen"
+// @Param status query string false "Filter by status (queued, preparing, ready, served)"
+// @Success 200 {array} model.KitchenItem
+// @Router /api/kitchen/stations/{station} [get]
+func (h *KitchenHandler) getQueue(w http.ResponseWriter, r *http.Request, station string) {
+	items, err := h.service.GetQueue(r.Context(), station, r.URL.Query().Get("status"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			response.Error(w, http.StatusBadRequest, err.Error())
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch kitchen queue")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, items)
+}
+
+// subscribe godoc
+// @Summary Subscribe to station queue
+// @Description Server-sent events stream with a kitchen_item event for every item routed to the station or changing status. Items are dropped when the client falls behind, poll the queue to catch up.
+// @Tags Kitchen
+// @Produce text/event-stream
+// @Param station path string true "Station, e.g. bar or kitchen"
+// @Success 200 {object} model.KitchenItem
+// @Router /api/kitchen/stations/{station}/events [get]
+func (h *KitchenHandler) subscribe(w http.ResponseWriter, r *http.Request, station string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.Error(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	items, unsubscribe := h.service.Subscribe(r.Context(), station)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Comments keep idle connections from being closed by proxies
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case item := <-items:
+			data, err := json.Marshal(item)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: kitchen_item\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
+// bump godoc
+// @Summary Bump kitchen item
+// @Description Moves an item one step on: queued, preparing, ready, served.
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Param id path int true "Kitchen item ID"
+// @Success 200 {object} model.KitchenItem
+// @Router /api/kitchen/items/{id}/bump [post]
+func (h *KitchenHandler) bump(w http.ResponseWriter, r *http.Request, id int) {
+	item, err := h.service.Bump(r.Context(), id)
+	if err != nil {
+		response.Error(w, kitchenErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, item)
+}
+
+// recall godoc
+// @Summary Recall kitchen item
+// @Description Moves an item one step back, e.g. after bumping it by mistake.
+// @Tags Kitchen
+// @Accept json
+// @Produce json
+// @Param id path int true "Kitchen item ID"
+// @Success 200 {object} model.KitchenItem
+// @Router /api/kitchen/items/{id}/recall [post]
+func (h *KitchenHandler) recall(w http.ResponseWriter, r *http.Request, id int) {
+	item, err := h.service.Recall(r.Context(), id)
+	if err != nil {
+		response.Error(w, kitchenErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, item)
+}
+
+// kitchenErrorStatus maps service errors to HTTP status codes
+func kitchenErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "cannot be") || strings.Contains(err.Error(), "modified"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type ModifierGroupHandler struct {
+	service service.ModifierGroupService
+}
+
+func NewModifierGroupHandler(s service.ModifierGroupService) *ModifierGroupHandler {
+	return &ModifierGroupHandler{service: s}
+}
+
+func (h *ModifierGroupHandler) HandleModifierGroups(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *ModifierGroupHandler) HandleModifierGroupByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r)
+	case http.MethodPut:
+		h.update(w, r)
+	case http.MethodDelete:
+		h.delete(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get all modifier groups
+// @Tags Modifier Groups
+// @Produce json
+// @Success 200 {array} model.ModifierGroup
+// @Router /api/modifier-groups [get]
+func (h *ModifierGroupHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch modifier groups")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, groups)
+}
+
+// create godoc
+// @Summary Create modifier group
+// @Description A sale picks between min_select and max_select modifiers of the group, a max_select of 0 allows any number.
+// @Tags Modifier Groups
+// @Accept json
+// @Produce json
+// @Param group body model.CreateModifierGroupRequestSwagger true "Create modifier group payload"
+// @Success 201 {object} model.ModifierGroup
+// @Router /api/modifier-groups [post]
+func (h *ModifierGroupHandler) create(w http.ResponseWriter, r *http.Request) {
+	var group model.ModifierGroup
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&group); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &group); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, group)
+}
+
+// getByID godoc
+// @Summary Get modifier group by ID
+// @Tags Modifier Groups
+// @Produce json
+// @Param id path int true "Modifier group ID"
+// @Success 200 {object} model.ModifierGroup
+// @Router /api/modifier-groups/{id} [get]
+func (h *ModifierGroupHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/modifier-groups/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid modifier group ID")
+		return
+	}
+
+	group, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, group)
+}
+
+// update godoc
+// @Summary Update modifier group by ID
+// @Description Replaces the group. Modifiers sent with their id keep it, modifiers left out are removed.
+// @Tags Modifier Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Modifier group ID"
+// @Param group body model.CreateModifierGroupRequestSwagger true "Update modifier group payload"
+// @Router /api/modifier-groups/{id} [put]
+func (h *ModifierGroupHandler) update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/modifier-groups/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid modifier group ID")
+		return
+	}
+
+	var group model.ModifierGroup
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&group); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &group); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "already exists") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Modifier group updated successfully",
+		"data":    group,
+	})
+}
+
+// delete godoc
+// @Summary Delete modifier group by ID
+// @Tags Modifier Groups
+// @Produce json
+// @Param id path int true "Modifier group ID"
+// @Router /api/modifier-groups/{id} [delete]
+func (h *ModifierGroupHandler) delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/modifier-groups/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid modifier group ID")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Modifier group deleted successfully"})
+}
@@ -0,0 +1,435 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// OrderHandler handles HTTP requests for open orders and held carts
+type OrderHandler struct {
+	service service.OrderService
+}
+
+// NewOrderHandler creates a new OrderHandler with the given OrderService
+func NewOrderHandler(service service.OrderService) *OrderHandler {
+	return &OrderHandler{service: service}
+}
+
+// HandleOrders - GET/POST /api/orders
+func (h *OrderHandler) HandleOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleOrderByID - GET/DELETE /api/orders/{id},
+// POST /api/orders/{id}/{hold|recall|send|checkout|split},
+// POST /api/orders/{id}/lines, PUT/DELETE /api/orders/{id}/lines/{lineID}
+// and POST /api/orders/{id}/shares/{number}/pay
+func (h *OrderHandler) HandleOrderByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/orders/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	lineID := 0
+	if lineStr, ok := strings.CutPrefix(action, "lines/"); ok {
+		lineID, err = strconv.Atoi(lineStr)
+		if err != nil || lineID <= 0 {
+			response.Error(w, http.StatusBadRequest, "Invalid order line ID")
+			return
+		}
+		action = "lines/"
+	}
+
+	shareNumber := 0
+	if shareStr, ok := strings.CutPrefix(action, "shares/"); ok {
+		numberStr, ok := strings.CutSuffix(shareStr, "/pay")
+		shareNumber, err = strconv.Atoi(numberStr)
+		if !ok || err != nil || shareNumber <= 0 {
+			http.NotFound(w, r)
+			return
+		}
+		action = "shares/pay"
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "" && r.Method == http.MethodDelete:
+		h.cancel(w, r, id)
+	case action == "lines" && r.Method == http.MethodPost:
+		h.addLine(w, r, id)
+	case action == "lines/" && r.Method == http.MethodPut:
+		h.updateLine(w, r, id, lineID)
+	case action == "lines/" && r.Method == http.MethodDelete:
+		h.deleteLine(w, r, id, lineID)
+	case action == "hold" && r.Method == http.MethodPost:
+		h.hold(w, r, id)
+	case action == "recall" && r.Method == http.MethodPost:
+		h.recall(w, r, id)
+	case action == "send" && r.Method == http.MethodPost:
+		h.send(w, r, id)
+	case action == "checkout" && r.Method == http.MethodPost:
+		h.checkout(w, r, id)
+	case action == "split" && r.Method == http.MethodPost:
+		h.split(w, r, id)
+	case action == "shares/pay" && r.Method == http.MethodPost:
+		h.payShare(w, r, id, shareNumber)
+	case action != "" && action != "lines" && action != "lines/" && action != "hold" &&
+		action != "recall" && action != "send" && action != "checkout" && action != "split" && action != "shares/pay":
+		http.NotFound(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get orders
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (open, held, paid, cancelled)"
+// @Param store_id query int false "Filter by store"
+// @Success 200 {array} model.Order
+// @Router /api/orders [get]
+func (h *OrderHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orders, err := h.service.GetAll(r.Context(), r.URL.Query().Get("status"), storeID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch orders")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, orders)
+}
+
+// create godoc
+// @Summary Open order
+// @Description Opens an empty ticket or cart. The X-User header is recorded as its creator.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param order body model.CreateOrderRequestSwagger true "Create order payload"
+// @Success 201 {object} model.Order
+// @Router /api/orders [post]
+func (h *OrderHandler) create(w http.ResponseWriter, r *http.Request) {
+	var order model.Order
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&order); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	order.CreatedBy = requestUser(r)
+
+	if err := h.service.Create(r.Context(), &order); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, order)
+}
+
+// getByID godoc
+// @Summary Get order by ID
+// @Description Line subtotals and the total are priced at the current prices.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id} [get]
+func (h *OrderHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Order not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch order")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// cancel godoc
+// @Summary Cancel order
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id} [delete]
+func (h *OrderHandler) cancel(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.Cancel(r.Context(), id)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// addLine godoc
+// @Summary Add line to order
+// @Description Takes a product_id or a scanned barcode, like a checkout item.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param line body model.OrderLineRequestSwagger true "Order line payload"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/lines [post]
+func (h *OrderHandler) addLine(w http.ResponseWriter, r *http.Request, id int) {
+	var line model.OrderLine
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&line); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	order, err := h.service.AddLine(r.Context(), id, &line)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// updateLine godoc
+// @Summary Update order line
+// @Description Changes the quantity, modifiers or notes of a line. Omitted quantity and modifier_ids are kept.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param lineID path int true "Order line ID"
+// @Param line body model.OrderLineRequestSwagger true "Order line payload"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/lines/{lineID} [put]
+func (h *OrderHandler) updateLine(w http.ResponseWriter, r *http.Request, id, lineID int) {
+	var line model.OrderLine
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&line); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	order, err := h.service.UpdateLine(r.Context(), id, lineID, &line)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// deleteLine godoc
+// @Summary Remove order line
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param lineID path int true "Order line ID"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/lines/{lineID} [delete]
+func (h *OrderHandler) deleteLine(w http.ResponseWriter, r *http.Request, id, lineID int) {
+	order, err := h.service.DeleteLine(r.Context(), id, lineID)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// hold godoc
+// @Summary Hold order
+// @Description Parks an open order. Lines cannot change until it is recalled.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/hold [post]
+func (h *OrderHandler) hold(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.Hold(r.Context(), id)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// recall godoc
+// @Summary Recall held order
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/recall [post]
+func (h *OrderHandler) recall(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.Recall(r.Context(), id)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// send godoc
+// @Summary Send order to preparation stations
+// @Description Routes the lines not sent yet to the station of their category. Sent lines cannot be changed.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/send [post]
+func (h *OrderHandler) send(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.Send(r.Context(), id)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// checkout godoc
+// @Summary Check out order
+// @Description Sells the lines of an open or held order through the regular checkout and marks the order paid.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param checkout body model.CheckoutOrderRequestSwagger false "Checkout options"
+// @Success 201 {object} model.TransactionResponse
+// @Router /api/orders/{id}/checkout [post]
+func (h *OrderHandler) checkout(w http.ResponseWriter, r *http.Request, id int) {
+	// The body is optional
+	var options struct {
+		OverrideExpiredBy string         `json:"override_expired_by"`
+		Tenders           []model.Tender `json:"tenders"`
+		CouponCode        string         `json:"coupon_code"`
+		Customer          string         `json:"customer"`
+	}
+	if r.ContentLength != 0 {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields() // Disallow unknown fields
+		if err := decoder.Decode(&options); err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	responseData, err := h.service.Checkout(r.Context(), id, model.CheckoutRequest{
+		OverrideExpiredBy: options.OverrideExpiredBy,
+		Tenders:           options.Tenders,
+		CouponCode:        options.CouponCode,
+		Customer:          options.Customer,
+	})
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, responseData)
+}
+
+// split godoc
+// @Summary Split order
+// @Description Give line_ids to move those lines into a new order for the same table, which is returned. Give shares to sell the order now and divide its total into equal shares, the first shares take one extra of any remainder; the order is returned in status split and is paid once every share and split order is paid.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param split body model.OrderSplitRequest true "Split payload"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/split [post]
+func (h *OrderHandler) split(w http.ResponseWriter, r *http.Request, id int) {
+	var request model.OrderSplitRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&request); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	order, err := h.service.Split(r.Context(), id, request, requestUser(r))
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// payShare godoc
+// @Summary Pay order share
+// @Description Pays one share of an order split into equal shares. Only cash may be tendered above the share, the rest is change.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param number path int true "Share number"
+// @Param payment body model.PayRequest true "Tenders"
+// @Success 200 {object} model.Order
+// @Router /api/orders/{id}/shares/{number}/pay [post]
+func (h *OrderHandler) payShare(w http.ResponseWriter, r *http.Request, id, number int) {
+	var request model.PayRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&request); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	order, err := h.service.PayShare(r.Context(), id, number, request.Tenders)
+	if err != nil {
+		response.Error(w, orderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// orderErrorStatus maps service errors to HTTP status codes
+func orderErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "order not found") || strings.Contains(err.Error(), "order line not found") ||
+		strings.Contains(err.Error(), "order share not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "cannot be") || strings.Contains(err.Error(), "modified"):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
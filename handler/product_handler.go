@@ -49,6 +49,7 @@ func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Reques
 
 // getAll godoc
 // @Summary Get all products
+// @Description Variants are nested under their parent product.
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -59,7 +60,7 @@ func (h *ProductHandler) getAll(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
 
 	// GET /api/products
-	products, err := h.service.GetAll(name)
+	products, err := h.service.GetAll(r.Context(), name)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "Failed to fetch products")
 		return
@@ -72,6 +73,8 @@ func (h *ProductHandler) getAll(w http.ResponseWriter, r *http.Request) {
 
 // create godoc
 // @Summary Create product
+// @Description A product with parent_id is a variant of that product and needs a value for each of its variant_attributes.
+// @Description A product with components is sold from their stock and holds none of its own.
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -88,9 +91,13 @@ func (h *ProductHandler) create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create new product
-	err := h.service.Create(&newProduct)
+	err := h.service.Create(r.Context(), &newProduct)
 	if err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error())
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			status = http.StatusConflict
+		}
+		response.Error(w, status, err.Error())
 		return
 	}
 	// Return the created product as JSON
@@ -113,7 +120,7 @@ func (h *ProductHandler) getByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Fetch product by ID
-	product, err := h.service.GetByID(id)
+	product, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
 		response.Error(w, http.StatusNotFound, "Product not found")
 		return
@@ -122,20 +129,65 @@ func (h *ProductHandler) getByID(w http.ResponseWriter, r *http.Request) {
 	// Return the product as JSON
 	response.JSON(w, http.StatusOK, map[string]interface{}{
 		"name":          product.Name,
+		"sku":           product.SKU,
+		"barcodes":      product.Barcodes,
+		"plu":           product.PLU,
 		"price":         product.Price,
+		"unit":          product.Unit,
 		"stock":         product.Stock,
+		"min_stock":     product.MinStock,
+		"reorder_qty":   product.ReorderQty,
 		"category_id":   product.CategoryID,
 		"category_name": product.Category.Name,
+		"packs":         product.Packs,
+
+		"parent_id":          product.ParentID,
+		"variant_attributes": product.VariantAttributes,
+		"attributes":         product.Attributes,
+		"inherits_price":     product.InheritsPrice,
+		"variants":           product.Variants,
+		"components":         product.Components,
+		"modifier_groups":    product.ModifierGroups,
 	})
 }
 
+// HandleLookup godoc
+// @Summary Look up a product by barcode or SKU
+// @Description Exact match as needed by a barcode scanner. UPC-A codes match their EAN-13 form.
+// @Description Codes of a configured in-store format (e.g. scale labels) resolve their PLU and also return the quantity and amount they carry.
+// @Description A pack barcode returns the product with the scanned pack.
+// @Tags Products
+// @Produce json
+// @Param barcode query string false "EAN-13 or UPC-A barcode"
+// @Param sku query string false "Stock keeping unit"
+// @Success 200 {object} model.ScannedProduct
+// @Router /api/products/lookup [get]
+func (h *ProductHandler) HandleLookup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	product, err := h.service.Lookup(r.Context(), r.URL.Query().Get("barcode"), r.URL.Query().Get("sku"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		response.Error(w, status, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, product)
+}
+
 // update godoc
 // @Summary Update product by ID
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
-// @Param product body model.CreateProductRequestSwagger true "Update product payload"
+// @Param product body model.UpdateProductRequest true "Update product payload, fields left out are kept"
 // @Router /api/products/{id} [put]
 func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/products/")
@@ -145,28 +197,35 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Decode request body into Product struct
-	var product model.Product
+	// Decode request body into the update request
+	var request model.UpdateProductRequest
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields() // Disallow unknown fields
-	if err := decoder.Decode(&product); err != nil {
+	if err := decoder.Decode(&request); err != nil {
 		response.Error(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 
 	// Update product
-	product.ID = id // Ensure the ID is set from the URL
-	err = h.service.Update(id, &product)
+	err = h.service.Update(r.Context(), id, &request)
 	if err != nil {
-		response.Error(w, http.StatusBadRequest, err.Error())
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "already exists") {
+			status = http.StatusConflict
+		} else if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		response.Error(w, status, err.Error())
 		return
 	}
 	// Return the updated product as JSON
+	product, _ := h.service.GetByID(r.Context(), id)
 	response.JSON(w, http.StatusOK, map[string]interface{}{"message": "Product updated successfully", "data": product})
 }
 
 // delete godoc
 // @Summary Delete product by ID
+// @Description A product with history such as stock movements or sales is archived instead. It is no longer listed, looked up or sold, and its SKU, PLU and barcodes are free for other products.
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -181,7 +240,7 @@ func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete product
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(r.Context(), id); err != nil {
 		response.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"encoding/json" //Encode/decode JSON  API response
+	"net/http"      //HTTP server & request handling
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// PurchaseOrderHandler handles HTTP requests for purchase orders
+type PurchaseOrderHandler struct {
+	service service.PurchaseOrderService
+}
+
+// NewPurchaseOrderHandler creates a new PurchaseOrderHandler with the given PurchaseOrderService
+func NewPurchaseOrderHandler(service service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// HandlePurchaseOrders - GET/POST /api/purchase-orders
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandlePurchaseOrderByID - GET/PUT /api/purchase-orders/{id} and
+// POST /api/purchase-orders/{id}/{send|receive|close}
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/purchase-orders/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid purchase order ID")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.update(w, r, id)
+	case action == "send" && r.Method == http.MethodPost:
+		h.send(w, r, id)
+	case action == "receive" && r.Method == http.MethodPost:
+		h.receive(w, r, id)
+	case action == "close" && r.Method == http.MethodPost:
+		h.close(w, r, id)
+	case action != "" && action != "send" && action != "receive" && action != "close":
+		http.NotFound(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get purchase orders
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param supplier_id query int false "Filter by supplier"
+// @Success 200 {array} model.PurchaseOrder
+// @Router /api/purchase-orders [get]
+func (h *PurchaseOrderHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	supplierID := 0
+	if s := r.URL.Query().Get("supplier_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil || id <= 0 {
+			response.Error(w, http.StatusBadRequest, "Invalid supplier ID")
+			return
+		}
+		supplierID = id
+	}
+
+	orders, err := h.service.GetAll(r.Context(), status, supplierID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch purchase orders")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, orders)
+}
+
+// create godoc
+// @Summary Create draft purchase order
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param order body model.CreatePurchaseOrderRequestSwagger true "Create purchase order payload"
+// @Success 201 {object} model.PurchaseOrder
+// @Router /api/purchase-orders [post]
+func (h *PurchaseOrderHandler) create(w http.ResponseWriter, r *http.Request) {
+	var order model.PurchaseOrder
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&order); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &order); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, order)
+}
+
+// getByID godoc
+// @Summary Get purchase order by ID
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} model.PurchaseOrder
+// @Router /api/purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Purchase order not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch purchase order")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// update godoc
+// @Summary Update draft purchase order
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param order body model.CreatePurchaseOrderRequestSwagger true "Update purchase order payload"
+// @Router /api/purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var order model.PurchaseOrder
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&order); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &order); err != nil {
+		response.Error(w, purchaseOrderErrorStatus(err), err.Error())
+		return
+	}
+
+	updatedOrder, _ := h.service.GetByID(r.Context(), id)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Purchase order updated successfully",
+		"data":    updatedOrder,
+	})
+}
+
+// send godoc
+// @Summary Mark purchase order as sent
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} model.PurchaseOrder
+// @Router /api/purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) send(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.Send(r.Context(), id)
+	if err != nil {
+		response.Error(w, purchaseOrderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// receive godoc
+// @Summary Receive goods against purchase order
+// @Description Quantities may be more or less than ordered. The X-User header is recorded as the receiver.
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param receipt body model.ReceivePurchaseOrderRequestSwagger true "Goods receipt payload"
+// @Success 200 {object} model.PurchaseOrder
+// @Router /api/purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) receive(w http.ResponseWriter, r *http.Request, id int) {
+	var receipt model.GoodsReceipt
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&receipt); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	receipt.PurchaseOrderID = id
+	receipt.CreatedBy = requestUser(r)
+
+	order, err := h.service.Receive(r.Context(), &receipt)
+	if err != nil {
+		response.Error(w, purchaseOrderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// close godoc
+// @Summary Close purchase order
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} model.PurchaseOrder
+// @Router /api/purchase-orders/{id}/close [post]
+func (h *PurchaseOrderHandler) close(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.Close(r.Context(), id)
+	if err != nil {
+		response.Error(w, purchaseOrderErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, order)
+}
+
+// GetOutstandingReport godoc
+// @Summary Get outstanding purchase orders per supplier
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.OutstandingPurchaseOrders
+// @Router /api/reports/outstanding-purchase-orders [get]
+func (h *PurchaseOrderHandler) GetOutstandingReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	report, err := h.service.GetOutstanding(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch outstanding purchase orders")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
+
+// purchaseOrderErrorStatus maps service errors to HTTP status codes
+func purchaseOrderErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "purchase order not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "cannot be") || strings.Contains(err.Error(), "only draft") ||
+		strings.Contains(err.Error(), "modified"):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
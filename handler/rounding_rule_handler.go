@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type RoundingRuleHandler struct {
+	service service.RoundingRuleService
+}
+
+func NewRoundingRuleHandler(s service.RoundingRuleService) *RoundingRuleHandler {
+	return &RoundingRuleHandler{service: s}
+}
+
+func (h *RoundingRuleHandler) HandleRoundingRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *RoundingRuleHandler) HandleRoundingRuleByTenderType(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		h.save(w, r)
+	case http.MethodDelete:
+		h.delete(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get all rounding rules
+// @Tags Rounding Rules
+// @Produce json
+// @Success 200 {array} model.RoundingRule
+// @Router /api/rounding-rules [get]
+func (h *RoundingRuleHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch rounding rules")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rules)
+}
+
+// save godoc
+// @Summary Set rounding rule of a tender type
+// @Description When the last tender of a sale or share is of this type, the balance it settles is rounded to a multiple of increment: nearest (halves up), up or down. The difference is stored as the rounding_amount of the transaction. Tender types without a rule are not rounded.
+// @Tags Rounding Rules
+// @Accept json
+// @Produce json
+// @Param tender_type path string true "Tender type, e.g. cash"
+// @Param rule body model.SaveRoundingRuleRequestSwagger true "Rounding rule payload"
+// @Success 200 {object} model.RoundingRule
+// @Router /api/rounding-rules/{tender_type} [put]
+func (h *RoundingRuleHandler) save(w http.ResponseWriter, r *http.Request) {
+	tenderType := strings.TrimPrefix(r.URL.Path, "/api/rounding-rules/")
+
+	var rule model.RoundingRule
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&rule); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Save(r.Context(), tenderType, &rule); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, rule)
+}
+
+// delete godoc
+// @Summary Delete rounding rule of a tender type
+// @Tags Rounding Rules
+// @Produce json
+// @Param tender_type path string true "Tender type, e.g. cash"
+// @Router /api/rounding-rules/{tender_type} [delete]
+func (h *RoundingRuleHandler) delete(w http.ResponseWriter, r *http.Request) {
+	tenderType := strings.TrimPrefix(r.URL.Path, "/api/rounding-rules/")
+
+	if err := h.service.Delete(r.Context(), tenderType); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Rounding rule deleted successfully"})
+}
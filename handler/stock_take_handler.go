@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json" //Encode/decode JSON  API response
+	"net/http"      //HTTP server & request handling
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// StockTakeHandler handles HTTP requests for stock take sessions
+type StockTakeHandler struct {
+	service service.StockTakeService
+}
+
+// NewStockTakeHandler creates a new StockTakeHandler with the given StockTakeService
+func NewStockTakeHandler(service service.StockTakeService) *StockTakeHandler {
+	return &StockTakeHandler{service: service}
+}
+
+// HandleStockTakes - GET/POST /api/stock-takes
+func (h *StockTakeHandler) HandleStockTakes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleStockTakeByID - GET /api/stock-takes/{id} and
+// POST /api/stock-takes/{id}/{counts|post|cancel}
+func (h *StockTakeHandler) HandleStockTakeByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/stock-takes/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid stock take ID")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "counts" && r.Method == http.MethodPost:
+		h.addCounts(w, r, id)
+	case action == "post" && r.Method == http.MethodPost:
+		h.post(w, r, id)
+	case action == "cancel" && r.Method == http.MethodPost:
+		h.cancel(w, r, id)
+	case action != "" && action != "counts" && action != "post" && action != "cancel":
+		http.NotFound(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get stock take sessions
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.StockTake
+// @Router /api/stock-takes [get]
+func (h *StockTakeHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	stockTakes, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch stock takes")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stockTakes)
+}
+
+// create godoc
+// @Summary Open stock take session
+// @Description Snapshots the expected stock of every product in the selected categories.
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param stock_take body model.CreateStockTakeRequestSwagger true "Create stock take payload"
+// @Success 201 {object} model.StockTake
+// @Router /api/stock-takes [post]
+func (h *StockTakeHandler) create(w http.ResponseWriter, r *http.Request) {
+	var stockTake model.StockTake
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&stockTake); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	stockTake.CreatedBy = requestUser(r)
+
+	if err := h.service.Create(r.Context(), &stockTake); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, stockTake)
+}
+
+// getByID godoc
+// @Summary Get stock take with variances
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock take ID"
+// @Success 200 {object} model.StockTake
+// @Router /api/stock-takes/{id} [get]
+func (h *StockTakeHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	stockTake, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Stock take not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch stock take")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stockTake)
+}
+
+// addCounts godoc
+// @Summary Add a batch of counted quantities
+// @Description Counts for the same product add up across batches and scanners.
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock take ID"
+// @Param batch body model.StockTakeCountBatch true "Count batch payload"
+// @Success 200 {object} model.StockTake
+// @Router /api/stock-takes/{id}/counts [post]
+func (h *StockTakeHandler) addCounts(w http.ResponseWriter, r *http.Request, id int) {
+	var batch model.StockTakeCountBatch
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&batch); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	batch.CountedBy = requestUser(r)
+
+	stockTake, err := h.service.AddCounts(r.Context(), id, &batch)
+	if err != nil {
+		response.Error(w, stockTakeErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stockTake)
+}
+
+// post godoc
+// @Summary Post stock take variances to stock
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock take ID"
+// @Success 200 {object} model.StockTake
+// @Router /api/stock-takes/{id}/post [post]
+func (h *StockTakeHandler) post(w http.ResponseWriter, r *http.Request, id int) {
+	stockTake, err := h.service.Post(r.Context(), id, requestUser(r))
+	if err != nil {
+		response.Error(w, stockTakeErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stockTake)
+}
+
+// cancel godoc
+// @Summary Cancel stock take
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock take ID"
+// @Success 200 {object} model.StockTake
+// @Router /api/stock-takes/{id}/cancel [post]
+func (h *StockTakeHandler) cancel(w http.ResponseWriter, r *http.Request, id int) {
+	stockTake, err := h.service.Cancel(r.Context(), id)
+	if err != nil {
+		response.Error(w, stockTakeErrorStatus(err), err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stockTake)
+}
+
+// stockTakeErrorStatus maps service errors to HTTP status codes
+func stockTakeErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "stock take not found"):
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "cannot be") || strings.Contains(err.Error(), "modified"):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
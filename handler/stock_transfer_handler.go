@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json" //Encode/decode JSON  API response
+	"net/http"      //HTTP server & request handling
+	"strconv"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+// StockTransferHandler handles HTTP requests for inter-store transfers
+type StockTransferHandler struct {
+	service service.StockTransferService
+}
+
+// NewStockTransferHandler creates a new StockTransferHandler with the given StockTransferService
+func NewStockTransferHandler(service service.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{service: service}
+}
+
+// HandleTransfers - GET/POST /api/transfers
+func (h *StockTransferHandler) HandleTransfers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.send(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleTransferByID - GET /api/transfers/{id} and POST /api/transfers/{id}/receive
+func (h *StockTransferHandler) HandleTransferByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/transfers/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid transfer ID")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "receive" && r.Method == http.MethodPost:
+		h.receive(w, r, id)
+	case action != "" && action != "receive":
+		http.NotFound(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get stock transfers
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Param status query string false "in_transit or received"
+// @Success 200 {array} model.StockTransfer
+// @Router /api/transfers [get]
+func (h *StockTransferHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	transfers, err := h.service.GetAll(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, transfers)
+}
+
+// send godoc
+// @Summary Send stock to another store
+// @Description Stock leaves the sending store immediately and stays in transit until received. The X-User header is recorded as the sender.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Param transfer body model.SendStockTransferRequest true "Send transfer payload"
+// @Success 201 {object} model.StockTransfer
+// @Router /api/transfers [post]
+func (h *StockTransferHandler) send(w http.ResponseWriter, r *http.Request) {
+	var request model.SendStockTransferRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&request); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	transfer, err := h.service.Send(r.Context(), request, requestUser(r))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, transfer)
+}
+
+// getByID godoc
+// @Summary Get stock transfer by ID
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} model.StockTransfer
+// @Router /api/transfers/{id} [get]
+func (h *StockTransferHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	transfer, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Stock transfer not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch stock transfer")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, transfer)
+}
+
+// receive godoc
+// @Summary Receive stock transfer
+// @Description Books the transfer into the receiving store. Products left out of lines are received in full.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Param receipt body model.ReceiveStockTransferRequest false "Received quantities"
+// @Success 200 {object} model.StockTransfer
+// @Router /api/transfers/{id}/receive [post]
+func (h *StockTransferHandler) receive(w http.ResponseWriter, r *http.Request, id int) {
+	var request model.ReceiveStockTransferRequest
+	if r.ContentLength != 0 {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields() // Disallow unknown fields
+		if err := decoder.Decode(&request); err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	transfer, err := h.service.Receive(r.Context(), id, request, requestUser(r))
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "stock transfer not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "cannot be") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, transfer)
+}
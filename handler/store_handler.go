@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json" //Encode/decode JSON  API response
+	"errors"
+	"net/http" //HTTP server & request handling
+	"strconv"  //Convert string to number (for ID from URL)
+	"strings"  //String manipulation (trim, split, etc)
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type StoreHandler struct {
+	service service.StoreService
+}
+
+func NewStoreHandler(s service.StoreService) *StoreHandler {
+	return &StoreHandler{service: s}
+}
+
+func (h *StoreHandler) HandleStores(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *StoreHandler) HandleStoreByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r)
+	case http.MethodPut:
+		h.update(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleProductStock - GET /api/inventory/stock?product_id={id}
+func (h *StoreHandler) HandleProductStock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getProductStock(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get all stores
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Store
+// @Router /api/stores [get]
+func (h *StoreHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	stores, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch stores")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stores)
+}
+
+// create godoc
+// @Summary Create store
+// @Description currency is the ISO code of the base currency the store prices, sells and reports in, IDR by default. timezone is the IANA timezone report dates are days in, Asia/Jakarta (WIB) by default.
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param store body model.CreateStoreRequestSwagger true "Create store payload"
+// @Router /api/stores [post]
+func (h *StoreHandler) create(w http.ResponseWriter, r *http.Request) {
+	var store model.Store
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&store); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &store); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, store)
+}
+
+// getByID godoc
+// @Summary Get store by ID
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param id path int true "Store ID"
+// @Router /api/stores/{id} [get]
+func (h *StoreHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/stores/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid store ID")
+		return
+	}
+
+	store, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Store not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch store")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, store)
+}
+
+// update godoc
+// @Summary Update store by ID
+// @Tags Stores
+// @Accept json
+// @Produce json
+// @Param id path int true "Store ID"
+// @Param store body model.CreateStoreRequestSwagger true "Update store payload"
+// @Router /api/stores/{id} [put]
+func (h *StoreHandler) update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/stores/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid store ID")
+		return
+	}
+
+	var store model.Store
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&store); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &store); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	updatedStore, _ := h.service.GetByID(r.Context(), id)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Store updated successfully",
+		"data":    updatedStore,
+	})
+}
+
+// getProductStock godoc
+// @Summary Get stock of a product per store
+// @Description In transit is the quantity sent to the store by transfers that weren't received yet.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param product_id query int true "Product ID"
+// @Success 200 {array} model.ProductStock
+// @Router /api/inventory/stock [get]
+func (h *StoreHandler) getProductStock(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.Atoi(r.URL.Query().Get("product_id"))
+	if err != nil || productID <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	stocks, err := h.service.GetProductStock(r.Context(), productID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Product not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch product stock")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, stocks)
+}
+
+// storeIDParam reads the optional store_id query parameter. 0 means all
+// stores.
+func storeIDParam(r *http.Request) (int, error) {
+	s := r.URL.Query().Get("store_id")
+	if s == "" {
+		return 0, nil
+	}
+
+	storeID, err := strconv.Atoi(s)
+	if err != nil || storeID <= 0 {
+		return 0, errors.New("Invalid store ID")
+	}
+	return storeID, nil
+}
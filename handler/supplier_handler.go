@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json" //Encode/decode JSON  API response
+	"net/http"      //HTTP server & request handling
+	"strconv"       //Convert string to number (for ID from URL)
+	"strings"       //String manipulation (trim, split, etc)
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/service"
+)
+
+type SupplierHandler struct {
+	service service.SupplierService
+}
+
+func NewSupplierHandler(s service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: s}
+}
+
+func (h *SupplierHandler) HandleSuppliers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *SupplierHandler) HandleSupplierByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r)
+	case http.MethodPut:
+		h.update(w, r)
+	case http.MethodDelete:
+		h.delete(w, r)
+	default:
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getAll godoc
+// @Summary Get all suppliers
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Supplier
+// @Router /api/suppliers [get]
+func (h *SupplierHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.service.GetAll(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch suppliers")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, suppliers)
+}
+
+// create godoc
+// @Summary Create supplier
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Param supplier body model.CreateSupplierRequestSwagger true "Create supplier payload"
+// @Router /api/suppliers [post]
+func (h *SupplierHandler) create(w http.ResponseWriter, r *http.Request) {
+	var supplier model.Supplier
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&supplier); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &supplier); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, supplier)
+}
+
+// getByID godoc
+// @Summary Get supplier by ID
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Router /api/suppliers/{id} [get]
+func (h *SupplierHandler) getByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/suppliers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid supplier ID")
+		return
+	}
+
+	supplier, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.Error(w, http.StatusNotFound, "Supplier not found")
+		} else {
+			response.Error(w, http.StatusInternalServerError, "Failed to fetch supplier")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, supplier)
+}
+
+// update godoc
+// @Summary Update supplier by ID
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param supplier body model.CreateSupplierRequestSwagger true "Update supplier payload"
+// @Router /api/suppliers/{id} [put]
+func (h *SupplierHandler) update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/suppliers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid supplier ID")
+		return
+	}
+
+	var supplier model.Supplier
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields() // Disallow unknown fields
+	if err := decoder.Decode(&supplier); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &supplier); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	updatedSupplier, _ := h.service.GetByID(r.Context(), id)
+	response.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Supplier updated successfully",
+		"data":    updatedSupplier,
+	})
+}
+
+// delete godoc
+// @Summary Delete supplier by ID
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Router /api/suppliers/{id} [delete]
+func (h *SupplierHandler) delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/suppliers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "Invalid supplier ID")
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		statusCode := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "in use") {
+			statusCode = http.StatusConflict
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"message": "Supplier deleted successfully"})
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http" //HTTP server & request handling
+	"strings"
+
+	"go-cashier-api/pkg/response"
+	"go-cashier-api/pkg/tenant"
+	"go-cashier-api/service"
+)
+
+// TenantMiddleware resolves the tenant of every /api request from its
+// bearer token or Host header and stores it in the request context, where
+// the repositories pick it up. Requests that can't be resolved are refused.
+func TenantMiddleware(tenants service.TenantService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		apiKey := ""
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			token, ok := strings.CutPrefix(auth, "Bearer ")
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "Invalid authorization header")
+				return
+			}
+			apiKey = strings.TrimSpace(token)
+		}
+
+		t, err := tenants.Resolve(apiKey, r.Host)
+		if err != nil {
+			if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "not found") {
+				response.Error(w, http.StatusUnauthorized, "Unknown tenant")
+			} else {
+				response.Error(w, http.StatusInternalServerError, "Failed to resolve tenant")
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), t.ID)))
+	})
+}
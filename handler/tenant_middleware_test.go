@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-cashier-api/internal/testutil"
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/event"
+	"go-cashier-api/pkg/tenant"
+	"go-cashier-api/repository"
+	"go-cashier-api/service"
+)
+
+// testTenant is a tenant seeded with a category, a product and a sale of
+// Amount on 12 May 2026
+type testTenant struct {
+	model.Tenant
+	Category model.Category
+	Product  model.Product
+	StoreID  int
+	Amount   int
+}
+
+func seedTenant(t *testing.T, db *sql.DB, name string, amount int) testTenant {
+	t.Helper()
+
+	tt := testTenant{Tenant: testutil.CreateTenant(t, db, name, "transactions", "stores", "products", "categories"), Amount: amount}
+	ctx := tenant.WithID(context.Background(), tt.ID)
+
+	tt.Category = model.Category{Name: "Category of " + name}
+	if err := repository.NewCategoryRepository(db).Create(ctx, &tt.Category); err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	tt.Product = model.Product{Name: "Product of " + name, Type: model.ProductStandard, Price: amount, Unit: "pcs",
+		CategoryID: tt.Category.ID}
+	if err := repository.NewProductRepository(db).Create(ctx, &tt.Product); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("SELECT set_config('app.tenant_id', $1, true)", strconv.Itoa(tt.ID)); err != nil {
+		t.Fatalf("set tenant: %v", err)
+	}
+	err = tx.QueryRow("INSERT INTO stores (name, timezone) VALUES ($1, 'Asia/Jakarta') RETURNING id", "Store of "+name).
+		Scan(&tt.StoreID)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	_, err = tx.Exec("INSERT INTO transactions (store_id, total_amount, created_at) VALUES ($1, $2, '2026-05-12 12:00')",
+		tt.StoreID, amount)
+	if err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	return tt
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	db := testutil.DB(t)
+	categoryRepo := repository.NewCategoryRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	barcodeFormatRepo := repository.NewBarcodeFormatRepository(db)
+	categoryHandler := NewCategoryHandler(service.NewCategoryService(categoryRepo))
+	productHandler := NewProductHandler(service.NewProductService(productRepo, categoryRepo, barcodeFormatRepo))
+	transactionHandler := NewTransactionHandler(service.NewTransactionService(repository.NewTransactionRepository(db),
+		productRepo, barcodeFormatRepo, repository.NewRoundingRuleRepository(db), repository.NewStoreRepository(db), event.NewBus()))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/categories", categoryHandler.HandleCategories)
+	mux.HandleFunc("/api/categories/", categoryHandler.HandleCategoryByID)
+	mux.HandleFunc("/api/products", productHandler.HandleProducts)
+	mux.HandleFunc("/api/products/", productHandler.HandleProductByID)
+	mux.HandleFunc("/api/report", transactionHandler.GetTransactionsByDate)
+	mux.HandleFunc("/health", HealthHandler)
+	server := TenantMiddleware(service.NewTenantService(repository.NewTenantRepository(db)), mux)
+
+	a := seedTenant(t, db, "tenant-a", 1000)
+	b := seedTenant(t, db, "tenant-b", 2000)
+
+	// do sends a request as the tenant of the given API key and host,
+	// either may be empty
+	do := func(method, path, apiKey, host, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Host = host
+		if apiKey != "" {
+			r.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+		if body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	// categoryIDs lists the categories in a GET /api/categories response
+	categoryIDs := func(t *testing.T, w *httptest.ResponseRecorder) []int {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		var categories []model.Category
+		if err := json.Unmarshal(w.Body.Bytes(), &categories); err != nil {
+			t.Fatalf("decode categories: %v: %s", err, w.Body)
+		}
+		ids := make([]int, 0, len(categories))
+		for _, c := range categories {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	reads := []struct {
+		name   string
+		apiKey string
+		host   string
+		want   int
+	}{
+		{"api key of A", a.APIKey, "", a.Category.ID},
+		{"api key of B", b.APIKey, "", b.Category.ID},
+		{"host of A", "", a.Host, a.Category.ID},
+		{"host of B with port", "", b.Host + ":8080", b.Category.ID},
+		{"api key wins over host", a.APIKey, b.Host, a.Category.ID},
+	}
+	for _, tc := range reads {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := categoryIDs(t, do(http.MethodGet, "/api/categories", tc.apiKey, tc.host, ""))
+			if len(ids) != 1 || ids[0] != tc.want {
+				t.Errorf("categories = %v, want only %d", ids, tc.want)
+			}
+		})
+	}
+
+	refused := []struct {
+		name   string
+		apiKey string
+		host   string
+	}{
+		{"neither api key nor host", "", ""},
+		{"unknown api key", "no-such-key", a.Host},
+		{"unknown host", "", "nobody.test"},
+	}
+	for _, tc := range refused {
+		t.Run(tc.name, func(t *testing.T) {
+			w := do(http.MethodGet, "/api/categories", tc.apiKey, tc.host, "")
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401: %s", w.Code, w.Body)
+			}
+		})
+	}
+
+	t.Run("malformed authorization header", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+		r.Host = a.Host
+		r.Header.Set("Authorization", "Basic "+a.APIKey)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401: %s", w.Code, w.Body)
+		}
+	})
+
+	t.Run("routes outside /api need no tenant", func(t *testing.T) {
+		w := do(http.MethodGet, "/health", "", "", "")
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+	})
+
+	pathB := "/api/categories/" + strconv.Itoa(b.Category.ID)
+	crossTenant := []struct {
+		name   string
+		method string
+		apiKey string
+		host   string
+		body   string
+	}{
+		{"get by api key", http.MethodGet, a.APIKey, "", ""},
+		{"get by host", http.MethodGet, "", a.Host, ""},
+		{"update by api key", http.MethodPut, a.APIKey, "", `{"name":"Taken by A"}`},
+		{"update by host", http.MethodPut, "", a.Host, `{"name":"Taken by A"}`},
+		{"delete by api key", http.MethodDelete, a.APIKey, "", ""},
+		{"delete by host", http.MethodDelete, "", a.Host, ""},
+	}
+	for _, tc := range crossTenant {
+		t.Run("cross tenant "+tc.name, func(t *testing.T) {
+			w := do(tc.method, pathB, tc.apiKey, tc.host, tc.body)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404: %s", w.Code, w.Body)
+			}
+		})
+	}
+
+	t.Run("B's category is untouched", func(t *testing.T) {
+		w := do(http.MethodGet, pathB, b.APIKey, "", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		var got model.Category
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode category: %v", err)
+		}
+		if got.Name != b.Category.Name {
+			t.Errorf("name = %q, want %q", got.Name, b.Category.Name)
+		}
+	})
+
+	t.Run("products of A only", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/products", a.APIKey, "", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		var products []model.Product
+		if err := json.Unmarshal(w.Body.Bytes(), &products); err != nil {
+			t.Fatalf("decode products: %v: %s", err, w.Body)
+		}
+		if len(products) != 1 || products[0].ID != a.Product.ID {
+			t.Errorf("products = %+v, want only %d", products, a.Product.ID)
+		}
+	})
+
+	productB := "/api/products/" + strconv.Itoa(b.Product.ID)
+	crossTenantProduct := []struct {
+		name   string
+		method string
+		body   string
+	}{
+		{"get", http.MethodGet, ""},
+		{"update", http.MethodPut, `{"name":"Taken by A"}`},
+		{"delete", http.MethodDelete, ""},
+	}
+	for _, tc := range crossTenantProduct {
+		t.Run("cross tenant product "+tc.name, func(t *testing.T) {
+			w := do(tc.method, productB, a.APIKey, "", tc.body)
+			if w.Code < 400 {
+				t.Errorf("status = %d, want an error: %s", w.Code, w.Body)
+			}
+		})
+	}
+
+	t.Run("B's product is untouched", func(t *testing.T) {
+		w := do(http.MethodGet, productB, b.APIKey, "", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		var got model.Product
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode product: %v", err)
+		}
+		if got.Name != b.Product.Name {
+			t.Errorf("name = %q, want %q", got.Name, b.Product.Name)
+		}
+	})
+
+	// Both tenants sold on the same day; each sees its own sale and revenue
+	for _, tt := range []testTenant{a, b} {
+		t.Run("revenue of "+tt.Name, func(t *testing.T) {
+			w := do(http.MethodGet, "/api/report?start_date=2026-05-12&end_date=2026-05-12", tt.APIKey, "", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+			}
+			var report model.TransactionsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("decode report: %v: %s", err, w.Body)
+			}
+			if report.TotalTransactions != 1 || report.TotalRevenue != tt.Amount {
+				t.Errorf("transactions = %d, revenue = %d, want 1 and %d",
+					report.TotalTransactions, report.TotalRevenue, tt.Amount)
+			}
+		})
+	}
+
+	t.Run("revenue of B's store as A", func(t *testing.T) {
+		path := "/api/report?start_date=2026-05-12&end_date=2026-05-12&store_id=" + strconv.Itoa(b.StoreID)
+		w := do(http.MethodGet, path, a.APIKey, "", "")
+		if w.Code == http.StatusOK {
+			t.Errorf("status = 200, want an error: %s", w.Body)
+		}
+	})
+}
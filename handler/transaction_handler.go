@@ -3,6 +3,8 @@ package handler
 import (
 	"encoding/json" // JSON parsing
 	"net/http"      // HTTP operations
+	"strconv"
+	"strings"
 
 	"go-cashier-api/model"
 	"go-cashier-api/pkg/response" // Alias the package
@@ -44,7 +46,7 @@ func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	// }
 
 	// Call service layer
-	responseData, err := h.service.Checkout(request)
+	responseData, err := h.service.Checkout(r.Context(), request)
 	if err != nil {
 		response.Error(w, http.StatusBadRequest, err.Error())
 		return
@@ -66,7 +68,12 @@ func (h *TransactionHandler) GetTransactionsByDate(w http.ResponseWriter, r *htt
 	endDate := r.URL.Query().Get("end_date")
 
 	var responseData interface{}
-	var err error
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if startDate != "" && endDate == "" || startDate == "" && endDate != "" {
 		response.Error(w, http.StatusBadRequest, "start_date and end_date must be provided together")
@@ -74,10 +81,15 @@ func (h *TransactionHandler) GetTransactionsByDate(w http.ResponseWriter, r *htt
 	}
 
 	if startDate != "" && endDate != "" {
-		responseData, err = h.service.GetTransactionsByDate(startDate, endDate)
+		responseData, err = h.service.GetTransactionsByDate(r.Context(), startDate, endDate, storeID, rollUpParam(r))
 	}
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, err.Error())
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must") ||
+			strings.Contains(err.Error(), "not found") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
 
 		return
 	}
@@ -87,7 +99,13 @@ func (h *TransactionHandler) GetTransactionsByDate(w http.ResponseWriter, r *htt
 }
 
 func (h *TransactionHandler) GetTransactionsToday(w http.ResponseWriter, r *http.Request) {
-	data, err := h.service.GetTransactionsToday()
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.service.GetTransactionsToday(r.Context(), storeID, rollUpParam(r))
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -96,3 +114,69 @@ func (h *TransactionHandler) GetTransactionsToday(w http.ResponseWriter, r *http
 	response.JSON(w, http.StatusOK, data)
 
 }
+
+// GetSalesReport godoc
+// @Summary Get sales report grouped by period, category or product
+// @Description Quantity, sales count, gross, coupon discounts and net per group. Give top or bottom with group_by category or product to keep only that many groups with the highest or lowest net sales; totals always cover the whole range.
+// @Tags Reports
+// @Produce json
+// @Param start_date query string true "Start date (YYYY-MM-DD in store time, or RFC 3339)"
+// @Param end_date query string true "End date, inclusive (YYYY-MM-DD), or exclusive RFC 3339 timestamp"
+// @Param group_by query string false "hour, day, week, month, category or product (default day)"
+// @Param top query int false "Only the N best selling groups"
+// @Param bottom query int false "Only the N worst selling groups"
+// @Param rollup query string false "parent to report variants as their parent product"
+// @Param store_id query int false "Limit to one store"
+// @Success 200 {object} model.SalesReport
+// @Router /api/reports/sales [get]
+func (h *TransactionHandler) GetSalesReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	storeID, err := storeIDParam(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	request := model.SalesReportQuery{
+		GroupBy: strings.ToLower(query.Get("group_by")),
+		StoreID: storeID,
+		RollUp:  rollUpParam(r),
+	}
+	if top := query.Get("top"); top != "" {
+		request.Top, err = strconv.Atoi(top)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid top")
+			return
+		}
+	}
+	if bottom := query.Get("bottom"); bottom != "" {
+		request.Bottom, err = strconv.Atoi(bottom)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid bottom")
+			return
+		}
+	}
+
+	report, err := h.service.GetSalesReport(r.Context(), query.Get("start_date"), query.Get("end_date"), request)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must") {
+			statusCode = http.StatusBadRequest
+		}
+		response.Error(w, statusCode, err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, report)
+}
+
+// rollUpParam reports whether ?rollup=parent asks for variants to be
+// reported as their parent product
+func rollUpParam(r *http.Request) bool {
+	return r.URL.Query().Get("rollup") == "parent"
+}
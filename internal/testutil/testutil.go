@@ -0,0 +1,94 @@
+// Package testutil holds the helpers shared by tests that run against a
+// real database
+package testutil
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"go-cashier-api/model"
+)
+
+// DB connects to the database in TEST_DB_CONN, which must have every
+// migration applied, and skips the test when it is not set. The role must
+// not bypass row-level security, or the isolation tests would pass for the
+// wrong reason.
+func DB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	conn := os.Getenv("TEST_DB_CONN")
+	if conn == "" {
+		t.Skip("TEST_DB_CONN is not set")
+	}
+
+	db, err := sql.Open("postgres", conn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("ping database: %v", err)
+	}
+
+	var bypass bool
+	err = db.QueryRow("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypass)
+	if err != nil {
+		t.Fatalf("check role: %v", err)
+	}
+	if bypass {
+		t.Fatal("TEST_DB_CONN must connect as a role that does not bypass row-level security")
+	}
+	return db
+}
+
+// CreateTenant adds a tenant with a unique host and API key. When the test
+// ends it deletes what the tenant has in tables, in the order given, and
+// then the tenant itself.
+func CreateTenant(t *testing.T, db *sql.DB, name string, tables ...string) model.Tenant {
+	t.Helper()
+
+	suffix := fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+	tn := model.Tenant{Name: name, Host: suffix + ".test", APIKey: "key-" + suffix}
+	err := db.QueryRow("INSERT INTO tenants (name, host, api_key) VALUES ($1, $2, $3) RETURNING id",
+		tn.Name, tn.Host, tn.APIKey).Scan(&tn.ID)
+	if err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := deleteTenant(db, tn.ID, tables); err != nil {
+			t.Errorf("clean up tenant %d: %v", tn.ID, err)
+		}
+	})
+	return tn
+}
+
+func deleteTenant(db *sql.DB, tenantID int, tables []string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Row-level security limits every delete to the rows of this tenant
+	if _, err := tx.Exec("SELECT set_config('app.tenant_id', $1, true)", strconv.Itoa(tenantID)); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("delete from %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec("DELETE FROM tenants WHERE id = $1", tenantID)
+	return err
+}
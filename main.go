@@ -1,10 +1,11 @@
 package main
 
 import (
-	"log"      // Logging package
-	"net/http" // HTTP server package
-	"os"       // Operating system functionality package
-	"strings"  // String manipulation package
+	"log"           // Logging package
+	"net/http"      // HTTP server package
+	"os"            // Operating system functionality package
+	"strings"       // String manipulation package
+	_ "time/tzdata" // Timezone database for store timezones on hosts without one
 
 	_ "go-cashier-api/docs"
 
@@ -15,6 +16,8 @@ import (
 
 	"go-cashier-api/database" // Import database package
 	"go-cashier-api/handler"  // Import handler package
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/event"
 	"go-cashier-api/repository"
 	"go-cashier-api/service" // Import service package
 )
@@ -27,6 +30,7 @@ type Config struct {
 // @title Go Cashier API
 // @version 1.0
 // @description This is a sample API for a cashier system.
+// @description Every /api request is scoped to the tenant resolved from its "Authorization: Bearer <api key>" header or, without one, its Host header.
 // @BasePath /
 func main() {
 	viper.AutomaticEnv()                                   // read in environment variables
@@ -50,20 +54,77 @@ func main() {
 	}
 	defer db.Close()
 
+	// Event bus for notifications between services
+	events := event.NewBus()
+	events.Subscribe(event.LowStock, func(e event.Event) {
+		if p, ok := e.Payload.(model.LowStockProduct); ok {
+			log.Printf("Low stock: tenant %d: %s (id %d) has %s left in %s, reorder point %s",
+				p.TenantID, p.Name, p.ProductID, p.Stock, p.StoreName, p.MinStock)
+		}
+	})
+
 	// Initialize repositories
+	tenantRepo := repository.NewTenantRepository(db)
 	productRepo := repository.NewProductRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	stockAdjustmentRepo := repository.NewStockAdjustmentRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	kitchenRepo := repository.NewKitchenRepository(db)
+	giftCardRepo := repository.NewGiftCardRepository(db)
+	stockTakeRepo := repository.NewStockTakeRepository(db)
+	stockLotRepo := repository.NewStockLotRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	stockTransferRepo := repository.NewStockTransferRepository(db)
+	barcodeFormatRepo := repository.NewBarcodeFormatRepository(db)
+	modifierGroupRepo := repository.NewModifierGroupRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+	roundingRuleRepo := repository.NewRoundingRuleRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
 
 	// Initialize services
-	productService := service.NewProductService(productRepo, categoryRepo)
+	tenantService := service.NewTenantService(tenantRepo)
+	productService := service.NewProductService(productRepo, categoryRepo, barcodeFormatRepo)
 	categoryService := service.NewCategoryService(categoryRepo)
-	transactionService := service.NewTransactionService(transactionRepo, productRepo)
+	transactionService := service.NewTransactionService(transactionRepo, productRepo, barcodeFormatRepo, roundingRuleRepo, storeRepo, events)
+	inventoryService := service.NewInventoryService(stockMovementRepo, stockAdjustmentRepo, stockLotRepo, productRepo, storeRepo)
+	supplierService := service.NewSupplierService(supplierRepo)
+	purchaseOrderService := service.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, productRepo)
+	orderService := service.NewOrderService(orderRepo, productRepo, barcodeFormatRepo, roundingRuleRepo, transactionService, events)
+	kitchenService := service.NewKitchenService(kitchenRepo, events)
+	giftCardService := service.NewGiftCardService(giftCardRepo)
+	stockTakeService := service.NewStockTakeService(stockTakeRepo, categoryRepo)
+	storeService := service.NewStoreService(storeRepo, productRepo)
+	stockTransferService := service.NewStockTransferService(stockTransferRepo, storeRepo)
+	barcodeFormatService := service.NewBarcodeFormatService(barcodeFormatRepo)
+	modifierGroupService := service.NewModifierGroupService(modifierGroupRepo)
+	couponService := service.NewCouponService(couponRepo)
+	roundingRuleService := service.NewRoundingRuleService(roundingRuleRepo)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo)
+	exportService := service.NewExportService(transactionRepo, storeRepo, transactionService, inventoryService, purchaseOrderService)
 
 	// Initialize handlers
 	productHandler := handler.NewProductHandler(productService)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	supplierHandler := handler.NewSupplierHandler(supplierService)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(purchaseOrderService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	kitchenHandler := handler.NewKitchenHandler(kitchenService)
+	giftCardHandler := handler.NewGiftCardHandler(giftCardService)
+	stockTakeHandler := handler.NewStockTakeHandler(stockTakeService)
+	storeHandler := handler.NewStoreHandler(storeService)
+	stockTransferHandler := handler.NewStockTransferHandler(stockTransferService)
+	barcodeFormatHandler := handler.NewBarcodeFormatHandler(barcodeFormatService)
+	modifierGroupHandler := handler.NewModifierGroupHandler(modifierGroupService)
+	couponHandler := handler.NewCouponHandler(couponService)
+	roundingRuleHandler := handler.NewRoundingRuleHandler(roundingRuleService)
+	exchangeRateHandler := handler.NewExchangeRateHandler(exchangeRateService)
+	exportHandler := handler.NewExportHandler(exportService)
 
 	// Setup HTTP server and routes
 	mux := http.NewServeMux()
@@ -75,12 +136,53 @@ func main() {
 	mux.HandleFunc("/health", handler.HealthHandler)
 	// API endpoints
 	mux.HandleFunc("/api/products", productHandler.HandleProducts)
+	mux.HandleFunc("/api/products/lookup", productHandler.HandleLookup)
 	mux.HandleFunc("/api/products/", productHandler.HandleProductByID)
+	mux.HandleFunc("/api/barcode-formats", barcodeFormatHandler.HandleBarcodeFormats)
+	mux.HandleFunc("/api/barcode-formats/", barcodeFormatHandler.HandleBarcodeFormatByID)
+	mux.HandleFunc("/api/modifier-groups", modifierGroupHandler.HandleModifierGroups)
+	mux.HandleFunc("/api/modifier-groups/", modifierGroupHandler.HandleModifierGroupByID)
 	mux.HandleFunc("/api/categories", categoryHandler.HandleCategories)
 	mux.HandleFunc("/api/categories/", categoryHandler.HandleCategoryByID)
 	mux.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
 	mux.HandleFunc("/api/report", transactionHandler.GetTransactionsByDate)
 	mux.HandleFunc("/api/report/today", transactionHandler.GetTransactionsToday)
+	mux.HandleFunc("/api/inventory/movements", inventoryHandler.HandleMovements)
+	mux.HandleFunc("/api/inventory/reasons", inventoryHandler.HandleAdjustmentReasons)
+	mux.HandleFunc("/api/inventory/adjustments", inventoryHandler.HandleAdjustments)
+	mux.HandleFunc("/api/inventory/low-stock", inventoryHandler.HandleLowStock)
+	mux.HandleFunc("/api/inventory/lots", inventoryHandler.HandleLots)
+	mux.HandleFunc("/api/inventory/stock", storeHandler.HandleProductStock)
+	mux.HandleFunc("/api/reports/expiring-lots", inventoryHandler.GetExpiringLots)
+	mux.HandleFunc("/api/reports/reorder-suggestions", inventoryHandler.GetReorderSuggestions)
+	mux.HandleFunc("/api/reports/shrinkage", inventoryHandler.GetShrinkageReport)
+	mux.HandleFunc("/api/reports/bundle-sales", inventoryHandler.GetBundleSalesReport)
+	mux.HandleFunc("/api/reports/sales", transactionHandler.GetSalesReport)
+	mux.HandleFunc("/api/exports/transactions", exportHandler.ExportTransactions)
+	mux.HandleFunc("/api/exports/transaction-details", exportHandler.ExportTransactionDetails)
+	mux.HandleFunc("/api/exports/reports/", exportHandler.ExportReport)
+	mux.HandleFunc("/api/suppliers", supplierHandler.HandleSuppliers)
+	mux.HandleFunc("/api/suppliers/", supplierHandler.HandleSupplierByID)
+	mux.HandleFunc("/api/purchase-orders", purchaseOrderHandler.HandlePurchaseOrders)
+	mux.HandleFunc("/api/purchase-orders/", purchaseOrderHandler.HandlePurchaseOrderByID)
+	mux.HandleFunc("/api/reports/outstanding-purchase-orders", purchaseOrderHandler.GetOutstandingReport)
+	mux.HandleFunc("/api/orders", orderHandler.HandleOrders)
+	mux.HandleFunc("/api/orders/", orderHandler.HandleOrderByID)
+	mux.HandleFunc("/api/kitchen/stations/", kitchenHandler.HandleStation)
+	mux.HandleFunc("/api/kitchen/items/", kitchenHandler.HandleItemByID)
+	mux.HandleFunc("/api/gift-cards", giftCardHandler.HandleGiftCards)
+	mux.HandleFunc("/api/gift-cards/", giftCardHandler.HandleGiftCardByCode)
+	mux.HandleFunc("/api/coupons", couponHandler.HandleCoupons)
+	mux.HandleFunc("/api/coupons/", couponHandler.HandleCouponByID)
+	mux.HandleFunc("/api/rounding-rules", roundingRuleHandler.HandleRoundingRules)
+	mux.HandleFunc("/api/rounding-rules/", roundingRuleHandler.HandleRoundingRuleByTenderType)
+	mux.HandleFunc("/api/exchange-rates", exchangeRateHandler.HandleExchangeRates)
+	mux.HandleFunc("/api/stock-takes", stockTakeHandler.HandleStockTakes)
+	mux.HandleFunc("/api/stock-takes/", stockTakeHandler.HandleStockTakeByID)
+	mux.HandleFunc("/api/stores", storeHandler.HandleStores)
+	mux.HandleFunc("/api/stores/", storeHandler.HandleStoreByID)
+	mux.HandleFunc("/api/transfers", stockTransferHandler.HandleTransfers)
+	mux.HandleFunc("/api/transfers/", stockTransferHandler.HandleTransferByID)
 	// Redirect root to Swagger UI
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/" {
@@ -91,5 +193,6 @@ func main() {
 	})
 
 	log.Println("Server running on :" + config.Port)
-	log.Fatal(http.ListenAndServe(":"+config.Port, mux))
+	// Every API request runs as the tenant resolved from its token or host
+	log.Fatal(http.ListenAndServe(":"+config.Port, handler.TenantMiddleware(tenantService, mux)))
 }
@@ -0,0 +1,34 @@
+package model
+
+import "go-cashier-api/pkg/decimal"
+
+// BarcodeFormat is the layout of in-store barcodes such as scale labels.
+// See barcode.Format for how the digits are laid out.
+type BarcodeFormat struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Prefix        string `json:"prefix"`
+	CodeLength    int    `json:"code_length"`
+	ValueType     string `json:"value_type"` // weight or price
+	ValueLength   int    `json:"value_length"`
+	ValueDecimals int    `json:"value_decimals"` // e.g. 3 when the weight is in grams and the product is priced per kg
+}
+
+type CreateBarcodeFormatRequestSwagger struct {
+	Name          string `json:"name" example:"Deli scale weight"`
+	Prefix        string `json:"prefix" example:"21"`
+	CodeLength    int    `json:"code_length" example:"5"`
+	ValueType     string `json:"value_type" example:"weight"`
+	ValueLength   int    `json:"value_length" example:"5"`
+	ValueDecimals int    `json:"value_decimals" example:"3"`
+}
+
+// ScannedProduct is the product behind a scanned barcode. Scale and other
+// in-store labels also carry the quantity, and price labels the amount.
+// Pack barcodes scan as the pack.
+type ScannedProduct struct {
+	Product
+	Pack     *ProductPack     `json:"pack,omitempty"`
+	Quantity *decimal.Decimal `json:"quantity,omitempty" swaggertype:"number"`
+	Amount   *int             `json:"amount,omitempty"`
+}
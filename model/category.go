@@ -4,9 +4,11 @@ type Category struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Station     string `json:"station"` // preparation station its lines are routed to, empty for none
 }
 
 type CreateCategoryRequestSwagger struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Station     string `json:"station" example:"bar"`
 }
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Coupon discount types
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Coupon is a code that discounts the eligible lines of a sale. Lines are
+// eligible when their product, its parent or its category is listed, or
+// always when neither is; gift cards never are.
+type Coupon struct {
+	ID               int        `json:"id"`
+	Code             string     `json:"code"`
+	Description      string     `json:"description,omitempty"`
+	DiscountType     string     `json:"discount_type"`
+	DiscountValue    int        `json:"discount_value"` // percent or amount
+	MinSpend         int        `json:"min_spend"`      // on the eligible lines
+	ProductIDs       []int      `json:"product_ids"`
+	CategoryIDs      []int      `json:"category_ids"`
+	StartsAt         *time.Time `json:"starts_at,omitempty"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+	UsageLimit       int        `json:"usage_limit"`        // 0 is unlimited
+	PerCustomerLimit int        `json:"per_customer_limit"` // 0 is unlimited
+	TimesUsed        int        `json:"times_used"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type CreateCouponRequestSwagger struct {
+	Code             string     `json:"code" example:"HEMAT10"`
+	Description      string     `json:"description"`
+	DiscountType     string     `json:"discount_type" example:"percent"`
+	DiscountValue    int        `json:"discount_value" example:"10"`
+	MinSpend         int        `json:"min_spend"`
+	ProductIDs       []int      `json:"product_ids"`
+	CategoryIDs      []int      `json:"category_ids"`
+	StartsAt         *time.Time `json:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at"`
+	UsageLimit       int        `json:"usage_limit"`
+	PerCustomerLimit int        `json:"per_customer_limit"`
+}
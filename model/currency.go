@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"go-cashier-api/pkg/decimal"
+)
+
+// CurrencyIDR is the base currency of a store unless set otherwise
+const CurrencyIDR = "IDR"
+
+// currencyMinorUnits is the number of decimal places of the minor unit of
+// each accepted currency. Rupiah is kept in whole rupiah as prices are.
+var currencyMinorUnits = map[string]int{
+	"IDR": 0,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"AUD": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"THB": 2,
+	"CNY": 2,
+	"SAR": 2,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// MinorUnits returns the decimal places of the minor unit of a currency,
+// e.g. 2 for cents, and whether the currency is known
+func MinorUnits(currency string) (int, bool) {
+	places, ok := currencyMinorUnits[currency]
+	return places, ok
+}
+
+// ExchangeRate says one unit of Currency is worth Rate units of
+// BaseCurrency from EffectiveFrom on, until a later rate takes over
+type ExchangeRate struct {
+	ID            int          `json:"id"`
+	Currency      string       `json:"currency" example:"USD"`
+	BaseCurrency  string       `json:"base_currency" example:"IDR"`
+	Rate          decimal.Rate `json:"rate" swaggertype:"number" example:"16250"`
+	EffectiveFrom time.Time    `json:"effective_from"`
+	CreatedBy     string       `json:"created_by"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type CreateExchangeRateRequestSwagger struct {
+	Currency      string    `json:"currency" example:"USD"`
+	BaseCurrency  string    `json:"base_currency" example:"IDR"`
+	Rate          float64   `json:"rate" example:"16250"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
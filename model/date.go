@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout is the YYYY-MM-DD format used for calendar dates
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date without time of day. It is encoded as
+// "YYYY-MM-DD" in JSON and maps to a DATE column.
+type Date struct {
+	time.Time
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(DateLayout))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, use YYYY-MM-DD", s)
+	}
+	d.Time = t
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (d *Date) Scan(value interface{}) error {
+	t, ok := value.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Date", value)
+	}
+	d.Time = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return nil
+}
+
+// Value implements driver.Valuer
+func (d Date) Value() (driver.Value, error) {
+	return d.Format(DateLayout), nil
+}
@@ -0,0 +1,17 @@
+package model
+
+// ExportQuery selects what an export covers and how it is written. Only
+// the fields a report uses are read, the others are ignored.
+type ExportQuery struct {
+	Format    string // csv or xlsx
+	Locale    string // en or id to format numbers, empty for plain numbers
+	StartDate string
+	EndDate   string
+	StoreID   int
+	GroupBy   string // sales report
+	Period    string // shrinkage report
+	Top       int    // sales report
+	Bottom    int    // sales report
+	RollUp    bool   // sales report
+	Days      int    // reorder suggestions and expiring lots
+}
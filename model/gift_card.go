@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Gift card entry types
+const (
+	GiftCardIssue  = "issue"
+	GiftCardRedeem = "redeem"
+)
+
+// GiftCard is a gift card or store-credit voucher. It is paid with as a
+// gift_card tender whose reference is the code, partly or in full.
+type GiftCard struct {
+	ID             int             `json:"id"`
+	Code           string          `json:"code"`
+	InitialBalance int             `json:"initial_balance"`
+	Balance        int             `json:"balance"`
+	ExpiresAt      *time.Time      `json:"expires_at,omitempty"`
+	ValidityDays   int             `json:"validity_days,omitempty"` // when issuing, sets ExpiresAt
+	SoldIn         *int            `json:"transaction_detail_id,omitempty"`
+	IssuedBy       string          `json:"issued_by,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Entries        []GiftCardEntry `json:"entries,omitempty"`
+}
+
+// GiftCardEntry is a change of a gift card's balance
+type GiftCardEntry struct {
+	ID            int       `json:"id"`
+	Type          string    `json:"type"`
+	Amount        int       `json:"amount"`
+	BalanceAfter  int       `json:"balance_after"`
+	TransactionID *int      `json:"transaction_id,omitempty"`
+	PaymentID     *int      `json:"payment_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type IssueGiftCardRequestSwagger struct {
+	InitialBalance int `json:"initial_balance" example:"100000"`
+	ValidityDays   int `json:"validity_days" example:"365"`
+}
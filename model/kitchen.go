@@ -0,0 +1,38 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Kitchen item statuses, in the order a line moves through them
+const (
+	KitchenQueued    = "queued"
+	KitchenPreparing = "preparing"
+	KitchenReady     = "ready"
+	KitchenServed    = "served"
+)
+
+// KitchenStatuses lists the statuses in order; bumping moves an item one
+// step forward and recalling one step back
+var KitchenStatuses = []string{KitchenQueued, KitchenPreparing, KitchenReady, KitchenServed}
+
+// KitchenItem is a line routed to a preparation station. Quantity counts
+// packs when Pack is set.
+type KitchenItem struct {
+	ID            int             `json:"id"`
+	TenantID      int             `json:"-"` // for filtering published items
+	Station       string          `json:"station"`
+	Status        string          `json:"status"`
+	OrderID       *int            `json:"order_id,omitempty"`
+	TransactionID *int            `json:"transaction_id,omitempty"`
+	TableName     string          `json:"table_name,omitempty"`
+	ProductName   string          `json:"product_name"`
+	Quantity      decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit          string          `json:"unit,omitempty"`
+	Pack          string          `json:"pack,omitempty"`
+	Modifiers     []string        `json:"modifiers"`
+	Notes         string          `json:"notes,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
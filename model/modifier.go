@@ -0,0 +1,81 @@
+package model
+
+import "fmt"
+
+// ModifierGroup is a set of modifiers for a product such as milk choices or
+// extras. A sale picks between MinSelect and MaxSelect of them, a MaxSelect
+// of 0 allows any number.
+type ModifierGroup struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	MinSelect int        `json:"min_select"`
+	MaxSelect int        `json:"max_select"`
+	Modifiers []Modifier `json:"modifiers"`
+}
+
+// Modifier changes the price of one unit of the item by PriceDelta, which
+// may be zero or negative
+type Modifier struct {
+	ID         int    `json:"id,omitempty"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+}
+
+// TransactionDetailModifier is a modifier picked for a sold line, with its
+// name and price at the time of the sale
+type TransactionDetailModifier struct {
+	ModifierID int    `json:"modifier_id"`
+	GroupName  string `json:"group_name"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+}
+
+type CreateModifierGroupRequestSwagger struct {
+	Name      string     `json:"name" example:"Milk"`
+	MinSelect int        `json:"min_select" example:"0"`
+	MaxSelect int        `json:"max_select" example:"1"`
+	Modifiers []Modifier `json:"modifiers"`
+}
+
+// SelectModifiers checks the modifiers picked for an item against the
+// groups of its product: each must belong to one of them, none twice, and
+// every group must get between its min and max picks
+func SelectModifiers(productName string, groups []ModifierGroup, modifierIDs []int) ([]TransactionDetailModifier, error) {
+	picked := make(map[int]bool, len(modifierIDs))
+	for _, id := range modifierIDs {
+		if picked[id] {
+			return nil, fmt.Errorf("modifier id %d is selected twice", id)
+		}
+		picked[id] = true
+	}
+
+	selected := make([]TransactionDetailModifier, 0, len(modifierIDs))
+	for _, group := range groups {
+		count := 0
+		for _, modifier := range group.Modifiers {
+			if !picked[modifier.ID] {
+				continue
+			}
+			delete(picked, modifier.ID)
+			count++
+			selected = append(selected, TransactionDetailModifier{
+				ModifierID: modifier.ID,
+				GroupName:  group.Name,
+				Name:       modifier.Name,
+				PriceDelta: modifier.PriceDelta,
+			})
+		}
+
+		if count < group.MinSelect {
+			return nil, fmt.Errorf("%s needs at least %d %s", productName, group.MinSelect, group.Name)
+		}
+		if group.MaxSelect > 0 && count > group.MaxSelect {
+			return nil, fmt.Errorf("%s allows at most %d %s", productName, group.MaxSelect, group.Name)
+		}
+	}
+
+	for id := range picked {
+		return nil, fmt.Errorf("modifier id %d is not available for %s", id, productName)
+	}
+	return selected, nil
+}
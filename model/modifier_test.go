@@ -0,0 +1,60 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectModifiers(t *testing.T) {
+	groups := []ModifierGroup{
+		{ID: 1, Name: "milk", MinSelect: 1, MaxSelect: 1, Modifiers: []Modifier{
+			{ID: 10, Name: "oat", PriceDelta: 5000}, {ID: 11, Name: "whole"},
+		}},
+		{ID: 2, Name: "extras", MaxSelect: 2, Modifiers: []Modifier{
+			{ID: 20, Name: "shot", PriceDelta: 6000}, {ID: 21, Name: "syrup", PriceDelta: 4000}, {ID: 22, Name: "cream", PriceDelta: 3000},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		ids     []int
+		want    []int
+		wantErr string
+	}{
+		{name: "required group picked", ids: []int{11}, want: []int{11}},
+		{name: "in group order", ids: []int{21, 10, 20}, want: []int{10, 20, 21}},
+		{name: "required group missing", ids: []int{20}, wantErr: "needs at least 1 milk"},
+		{name: "too many in a group", ids: []int{10, 11}, wantErr: "allows at most 1 milk"},
+		{name: "too many extras", ids: []int{10, 20, 21, 22}, wantErr: "allows at most 2 extras"},
+		{name: "picked twice", ids: []int{10, 10}, wantErr: "selected twice"},
+		{name: "not on the product", ids: []int{10, 99}, wantErr: "modifier id 99 is not available"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SelectModifiers("Latte", groups, tc.ids)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ids := make([]int, len(got))
+			for i, m := range got {
+				ids[i] = m.ModifierID
+			}
+			if len(ids) != len(tc.want) {
+				t.Fatalf("selected %v, want %v", ids, tc.want)
+			}
+			for i := range ids {
+				if ids[i] != tc.want[i] {
+					t.Fatalf("selected %v, want %v", ids, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Order statuses
+const (
+	OrderOpen      = "open"
+	OrderHeld      = "held"
+	OrderSplit     = "split" // sold, waiting for its shares or split orders to be paid
+	OrderPaid      = "paid"
+	OrderCancelled = "cancelled"
+)
+
+// Order is an open ticket for a table or a cart held at the till. Lines can
+// be added, changed and removed while it is open; checking it out prices
+// the lines and takes the stock the same way as a direct checkout.
+type Order struct {
+	ID            int          `json:"id"`
+	StoreID       int          `json:"store_id"`
+	Status        string       `json:"status"`
+	TableName     string       `json:"table_name,omitempty"`
+	Notes         string       `json:"notes,omitempty"`
+	CreatedBy     string       `json:"created_by,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	TransactionID *int         `json:"transaction_id,omitempty"` // the sale of the order's lines
+	ParentID      *int         `json:"parent_id,omitempty"`      // the order it was split from
+	Total         int          `json:"total"`                    // at current prices
+	Lines         []OrderLine  `json:"lines"`
+	ChildIDs      []int        `json:"child_ids,omitempty"` // orders split off by lines
+	Shares        []OrderShare `json:"shares,omitempty"`
+}
+
+// OrderShare is an equal part of the total of an order split by shares
+type OrderShare struct {
+	ID       int        `json:"id"`
+	Number   int        `json:"number"`
+	Amount   int        `json:"amount"`
+	PaidAt   *time.Time `json:"paid_at,omitempty"`
+	Payments []Payment  `json:"payments,omitempty"`
+}
+
+// OrderSplitRequest splits an open order either by moving lines into a
+// new order or by dividing its total into equal shares
+type OrderSplitRequest struct {
+	LineIDs []int `json:"line_ids,omitempty"`
+	Shares  int   `json:"shares,omitempty"`
+}
+
+// PayRequest pays an order share
+type PayRequest struct {
+	Tenders []Tender `json:"tenders"`
+}
+
+// OrderLine is a checkout item kept on an order. Subtotal is priced at the
+// current prices each time the order is read.
+type OrderLine struct {
+	ID          int                         `json:"id"`
+	ProductID   int                         `json:"product_id,omitempty"`
+	Barcode     string                      `json:"barcode,omitempty"` // resolved when the line is added
+	ProductName string                      `json:"product_name,omitempty"`
+	Pack        string                      `json:"pack,omitempty"`
+	Quantity    decimal.Decimal             `json:"quantity" swaggertype:"number"`
+	Unit        string                      `json:"unit,omitempty"`
+	Amount      *int                        `json:"-"` // fixed by a price label
+	ModifierIDs []int                       `json:"modifier_ids"`
+	Modifiers   []TransactionDetailModifier `json:"modifiers,omitempty"`
+	Notes       string                      `json:"notes,omitempty"`
+	Subtotal    int                         `json:"subtotal"`
+	SentAt      *time.Time                  `json:"sent_at,omitempty"` // routed to its preparation station
+	CreatedAt   time.Time                   `json:"created_at"`
+}
+
+type CreateOrderRequestSwagger struct {
+	StoreID   int    `json:"store_id"`
+	TableName string `json:"table_name" example:"Table 4"`
+	Notes     string `json:"notes"`
+}
+
+type OrderLineRequestSwagger struct {
+	ProductID   int             `json:"product_id"`
+	Barcode     string          `json:"barcode"`
+	Pack        string          `json:"pack"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	ModifierIDs []int           `json:"modifier_ids"`
+	Notes       string          `json:"notes"`
+}
+
+type CheckoutOrderRequestSwagger struct {
+	OverrideExpiredBy string   `json:"override_expired_by"`
+	Tenders           []Tender `json:"tenders"`
+	CouponCode        string   `json:"coupon_code"`
+	Customer          string   `json:"customer"`
+}
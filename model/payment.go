@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"go-cashier-api/pkg/decimal"
+)
+
+// Tender types
+const (
+	TenderCash = "cash"
+	TenderCard = "card"
+	TenderQRIS = "qris"
+
+	// TenderGiftCard redeems a gift card, the reference is its code
+	TenderGiftCard = "gift_card"
+)
+
+// Tender is one way a customer pays part of a bill. Only cash can be
+// tendered above what is due, the excess is given back as change.
+// Cash may be in a foreign Currency, its Amount is then in that currency.
+type Tender struct {
+	Type      string `json:"type" example:"cash"`
+	Amount    int    `json:"amount"`
+	Currency  string `json:"currency,omitempty" example:"USD"` // the base currency when empty
+	Reference string `json:"reference,omitempty"`              // e.g. card approval code
+}
+
+// Payment is a tender recorded against a sale. Amount is what it paid of
+// the bill and Tendered what was handed over, they differ by the change.
+// Both are in the base currency of the sale; foreign cash also keeps what
+// was handed over in its Currency and the rate it was converted at.
+type Payment struct {
+	ID              int           `json:"id"`
+	TransactionID   int           `json:"transaction_id"`
+	ShareID         *int          `json:"share_id,omitempty"`
+	Type            string        `json:"type"`
+	Amount          int           `json:"amount"`
+	Tendered        int           `json:"tendered"`
+	Currency        string        `json:"currency"`
+	ForeignTendered *int          `json:"foreign_tendered,omitempty"`
+	Rate            *decimal.Rate `json:"rate,omitempty" swaggertype:"number"`
+	Reference       string        `json:"reference,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
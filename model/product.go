@@ -1,33 +1,219 @@
 package model
 
+import "go-cashier-api/pkg/decimal"
+
+// Product types
+const (
+	ProductStandard = "standard"
+	ProductGiftCard = "gift_card" // sells a gift card per unit, worth the price
+)
+
+// Product is sold on its own or, when it has variants, through one of
+// them. A variant is a product with a ParentID and its own SKU, barcodes,
+// stock and optionally price; it takes the unit and category of the parent.
 type Product struct {
-	ID         int      `json:"id"`
-	Name       string   `json:"name"`
-	Price      int      `json:"price"`
-	Stock      int      `json:"stock"`
-	CategoryID int      `json:"category_id,omitempty"`
-	Category   Category `json:"category,omitzero"`
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"` // standard or gift_card, fixed once created
+	SKU        string          `json:"sku"`
+	Barcodes   []string        `json:"barcodes"`      // EAN-13, UPC-A codes are stored with a leading zero
+	PLU        int             `json:"plu,omitempty"` // item code in scale and other in-store barcodes
+	Price      int             `json:"price"`         // per unit
+	Unit       string          `json:"unit"`          // pcs, kg, g, l or m, defaults to pcs
+	Stock      decimal.Decimal `json:"stock" swaggertype:"number"`
+	MinStock   decimal.Decimal `json:"min_stock" swaggertype:"number"`
+	ReorderQty decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	CategoryID int             `json:"category_id,omitempty"`
+	Category   Category        `json:"category,omitzero"`
+	Packs      []ProductPack   `json:"packs"` // packs and cases the product is also sold or received in
+
+	ParentID          *int              `json:"parent_id,omitempty"`
+	VariantAttributes []string          `json:"variant_attributes,omitempty"` // of a parent, e.g. size and color
+	Attributes        map[string]string `json:"attributes,omitempty"`         // of a variant, a value per attribute of the parent
+	InheritsPrice     bool              `json:"inherits_price,omitempty"`     // the variant sells at the parent's price
+	Variants          []Product         `json:"variants,omitempty"`
+
+	// Components make a composite product such as a bundle or recipe. It
+	// holds no stock of its own, Stock is how many its components allow.
+	Components []ProductComponent `json:"components,omitempty"`
+
+	// ModifierGroupIDs attaches modifier groups, ModifierGroups lists them
+	// with their modifiers
+	ModifierGroupIDs []int           `json:"modifier_group_ids,omitempty"`
+	ModifierGroups   []ModifierGroup `json:"modifier_groups,omitempty"`
+
+	// ValidityDays is how long the gift cards a gift card product issues
+	// stay valid, nil for no expiry
+	ValidityDays *int `json:"validity_days,omitempty"`
+}
+
+// ProductComponent is the quantity of another product that goes into one
+// unit of a composite product
+type ProductComponent struct {
+	ProductID int             `json:"product_id"`
+	Name      string          `json:"name,omitempty"`
+	Quantity  decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit      string          `json:"unit,omitempty"`
+}
+
+// BundleSalesRow is a composite product sold in a date range with what it
+// took from each of its components
+type BundleSalesRow struct {
+	ProductID    int                `json:"product_id"`
+	Name         string             `json:"name"`
+	QuantitySold decimal.Decimal    `json:"quantity_sold" swaggertype:"number"`
+	Revenue      int                `json:"revenue"`
+	Components   []ProductComponent `json:"components"`
+}
+
+// ProductPack is a pack of Factor units of a product, e.g. a 6-pack or a
+// carton of 24. It has its own price and barcode; a zero price sells it at
+// the unit price times the factor.
+type ProductPack struct {
+	ID      int             `json:"id,omitempty"`
+	Name    string          `json:"name"`
+	Factor  decimal.Decimal `json:"factor" swaggertype:"number"`
+	Price   int             `json:"price"`
+	Barcode string          `json:"barcode,omitempty"`
 }
 
 type ProductResponseSwagger struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	Price int    `json:"price"`
-	Stock int    `json:"stock"`
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	SKU        string          `json:"sku"`
+	Barcodes   []string        `json:"barcodes"`
+	PLU        int             `json:"plu,omitempty"`
+	Price      int             `json:"price"`
+	Unit       string          `json:"unit"`
+	Stock      decimal.Decimal `json:"stock" swaggertype:"number"`
+	MinStock   decimal.Decimal `json:"min_stock" swaggertype:"number"`
+	ReorderQty decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	Packs      []ProductPack   `json:"packs"`
+
+	VariantAttributes []string                `json:"variant_attributes,omitempty"`
+	Variants          []ProductVariantSwagger `json:"variants,omitempty"`
+	Components        []ProductComponent      `json:"components,omitempty"`
+	ModifierGroups    []ModifierGroup         `json:"modifier_groups,omitempty"`
+}
+
+type ProductVariantSwagger struct {
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	SKU           string            `json:"sku"`
+	Barcodes      []string          `json:"barcodes"`
+	Price         int               `json:"price"`
+	InheritsPrice bool              `json:"inherits_price,omitempty"`
+	Unit          string            `json:"unit"`
+	Stock         decimal.Decimal   `json:"stock" swaggertype:"number"`
+	ParentID      int               `json:"parent_id"`
+	Attributes    map[string]string `json:"attributes"`
+	Packs         []ProductPack     `json:"packs"`
 }
 
 type ProductResponseWithCategorySwagger struct {
-	ID           int    `json:"id"`
-	Name         string `json:"name"`
-	Price        int    `json:"price"`
-	Stock        int    `json:"stock"`
-	CategoryID   int    `json:"category_id,omitempty"`
-	CategoryName string `json:"category_name,omitempty"`
+	ID           int             `json:"id"`
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	SKU          string          `json:"sku"`
+	Barcodes     []string        `json:"barcodes"`
+	PLU          int             `json:"plu,omitempty"`
+	Price        int             `json:"price"`
+	Unit         string          `json:"unit"`
+	Stock        decimal.Decimal `json:"stock" swaggertype:"number"`
+	MinStock     decimal.Decimal `json:"min_stock" swaggertype:"number"`
+	ReorderQty   decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	CategoryID   int             `json:"category_id,omitempty"`
+	CategoryName string          `json:"category_name,omitempty"`
+	Packs        []ProductPack   `json:"packs"`
+
+	ParentID          int                     `json:"parent_id,omitempty"`
+	VariantAttributes []string                `json:"variant_attributes,omitempty"`
+	Attributes        map[string]string       `json:"attributes,omitempty"`
+	InheritsPrice     bool                    `json:"inherits_price,omitempty"`
+	Variants          []ProductVariantSwagger `json:"variants,omitempty"`
+	Components        []ProductComponent      `json:"components,omitempty"`
+	ModifierGroups    []ModifierGroup         `json:"modifier_groups,omitempty"`
 }
 
 type CreateProductRequestSwagger struct {
-	Name       string `json:"name"`
-	Price      int    `json:"price"`
-	Stock      int    `json:"stock"`
-	CategoryID int    `json:"category_id"`
+	Name       string          `json:"name"`
+	SKU        string          `json:"sku"`
+	Barcodes   []string        `json:"barcodes"`
+	PLU        int             `json:"plu"`
+	Price      int             `json:"price"`
+	Unit       string          `json:"unit"`
+	Stock      decimal.Decimal `json:"stock" swaggertype:"number"`
+	MinStock   decimal.Decimal `json:"min_stock" swaggertype:"number"`
+	ReorderQty decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	CategoryID int             `json:"category_id"`
+	Packs      []ProductPack   `json:"packs"`
+
+	// A variant names its parent and its value of each of the parent's
+	// variant attributes. Its price may be left at 0 to sell at the
+	// parent's price.
+	ParentID          int               `json:"parent_id"`
+	VariantAttributes []string          `json:"variant_attributes"`
+	Attributes        map[string]string `json:"attributes"`
+
+	Components []ProductComponent `json:"components"`
+
+	ModifierGroupIDs []int `json:"modifier_group_ids"`
+
+	// A gift card product sells a gift card worth its price per unit
+	Type         string `json:"type" example:"standard"`
+	ValidityDays int    `json:"validity_days"`
+}
+
+// UpdateProductRequest changes a product. Fields left out keep their
+// current value; lists that are sent replace the existing ones, an empty
+// list removes them. Stock is not changed here but through a stock
+// adjustment with a reason code, so it is refused when sent.
+type UpdateProductRequest struct {
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`
+	SKU        string           `json:"sku"`
+	Barcodes   []string         `json:"barcodes"`
+	PLU        int              `json:"plu"`
+	Price      *int             `json:"price"` // 0 sells a variant at the parent's price
+	Unit       string           `json:"unit"`
+	Stock      *decimal.Decimal `json:"stock,omitempty" swaggerignore:"true"`
+	MinStock   *decimal.Decimal `json:"min_stock" swaggertype:"number"` // 0 turns off low-stock alerts
+	ReorderQty *decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	CategoryID int              `json:"category_id"`
+	Packs      []ProductPack    `json:"packs"`
+
+	VariantAttributes []string          `json:"variant_attributes"`
+	Attributes        map[string]string `json:"attributes"`
+
+	Components       []ProductComponent `json:"components"`
+	ModifierGroupIDs []int              `json:"modifier_group_ids"`
+
+	ValidityDays *int `json:"validity_days"` // 0 removes the expiry
+}
+
+// LowStockProduct is a product whose stock in a store reached its reorder
+// point, which applies to each store on its own
+type LowStockProduct struct {
+	ProductID  int             `json:"product_id"`
+	Name       string          `json:"name"`
+	StoreID    int             `json:"store_id"`
+	StoreName  string          `json:"store_name"`
+	Stock      decimal.Decimal `json:"stock" swaggertype:"number"`
+	MinStock   decimal.Decimal `json:"min_stock" swaggertype:"number"`
+	ReorderQty decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	TenantID   int             `json:"-"` // for subscribers to the low stock event
+}
+
+// ReorderSuggestion estimates how long the stock of a product will last
+// based on its average daily sales
+type ReorderSuggestion struct {
+	ProductID         int             `json:"product_id"`
+	Name              string          `json:"name"`
+	Stock             decimal.Decimal `json:"stock" swaggertype:"number"`
+	MinStock          decimal.Decimal `json:"min_stock" swaggertype:"number"`
+	ReorderQty        decimal.Decimal `json:"reorder_qty" swaggertype:"number"`
+	AverageDailySales float64         `json:"average_daily_sales"`
+	DaysOfCover       *float64        `json:"days_of_cover"` // nil when the product didn't sell
+	SuggestedQuantity decimal.Decimal `json:"suggested_quantity" swaggertype:"number"`
 }
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Purchase order statuses
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderClosed            = "closed"
+)
+
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	StoreID      int                 `json:"store_id"` // store the goods are delivered to
+	Status       string              `json:"status"`
+	Notes        string              `json:"notes"`
+	CreatedAt    time.Time           `json:"created_at"`
+	SentAt       *time.Time          `json:"sent_at,omitempty"`
+	ClosedAt     *time.Time          `json:"closed_at,omitempty"`
+	Items        []PurchaseOrderItem `json:"items"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int             `json:"id,omitempty"`
+	PurchaseOrderID  int             `json:"purchase_order_id,omitempty"`
+	ProductID        int             `json:"product_id"`
+	ProductName      string          `json:"product_name,omitempty"`
+	QuantityOrdered  decimal.Decimal `json:"quantity_ordered" swaggertype:"number"`
+	QuantityReceived decimal.Decimal `json:"quantity_received" swaggertype:"number"`
+	UnitCost         int             `json:"unit_cost"` // per unit of the product
+}
+
+type CreatePurchaseOrderRequestSwagger struct {
+	SupplierID int                              `json:"supplier_id"`
+	StoreID    int                              `json:"store_id"`
+	Notes      string                           `json:"notes"`
+	Items      []CreatePurchaseOrderItemSwagger `json:"items"`
+}
+
+type CreatePurchaseOrderItemSwagger struct {
+	ProductID       int             `json:"product_id"`
+	QuantityOrdered decimal.Decimal `json:"quantity_ordered" swaggertype:"number"`
+	UnitCost        int             `json:"unit_cost"`
+}
+
+// GoodsReceipt is one delivery received against a purchase order
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	Notes           string             `json:"notes"`
+	CreatedBy       string             `json:"created_by,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Lines           []GoodsReceiptLine `json:"lines"`
+}
+
+// GoodsReceiptLine may receive more or less than was ordered, UnitCost
+// defaults to the cost on the purchase order when left at zero. A batch
+// number puts the received quantity into a new stock lot. With Pack the
+// quantity counts packs, e.g. cartons, and UnitCost is the cost of a pack.
+type GoodsReceiptLine struct {
+	ProductID   int             `json:"product_id"`
+	Pack        string          `json:"pack,omitempty"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	UnitCost    int             `json:"unit_cost"`
+	Notes       string          `json:"notes,omitempty"`
+	BatchNumber string          `json:"batch_number,omitempty"`
+	ExpiryDate  *Date           `json:"expiry_date,omitempty" swaggertype:"string" format:"date"`
+}
+
+type ReceivePurchaseOrderRequestSwagger struct {
+	Notes string             `json:"notes"`
+	Lines []GoodsReceiptLine `json:"lines"`
+}
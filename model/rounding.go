@@ -0,0 +1,42 @@
+package model
+
+// Rounding modes
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// RoundingRule rounds the amount due when it is settled with a tender type,
+// e.g. cash to the nearest 100
+type RoundingRule struct {
+	TenderType string `json:"tender_type"`
+	Mode       string `json:"mode" example:"nearest"`
+	Increment  int    `json:"increment" example:"100"`
+}
+
+// Round rounds a non-negative amount to a multiple of the increment.
+// Nearest rounds halves up.
+func (r RoundingRule) Round(amount int) int {
+	remainder := amount % r.Increment
+	if remainder == 0 {
+		return amount
+	}
+
+	switch r.Mode {
+	case RoundUp:
+		return amount - remainder + r.Increment
+	case RoundDown:
+		return amount - remainder
+	default:
+		if remainder*2 >= r.Increment {
+			return amount - remainder + r.Increment
+		}
+		return amount - remainder
+	}
+}
+
+type SaveRoundingRuleRequestSwagger struct {
+	Mode      string `json:"mode" example:"nearest"`
+	Increment int    `json:"increment" example:"100"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "testing"
+
+func TestRoundingRuleRound(t *testing.T) {
+	tests := []struct {
+		mode      string
+		increment int
+		amount    int
+		want      int
+	}{
+		{RoundNearest, 100, 1249, 1200},
+		{RoundNearest, 100, 1250, 1300}, // halves up
+		{RoundNearest, 100, 1251, 1300},
+		{RoundNearest, 100, 1200, 1200},
+		{RoundNearest, 100, 0, 0},
+		{RoundNearest, 100, 49, 0},
+		{RoundNearest, 50, 1225, 1250},
+		{RoundNearest, 500, 12249, 12000},
+		{RoundNearest, 500, 12250, 12500},
+		{"", 100, 1250, 1300}, // nearest is the default
+		{RoundUp, 100, 1201, 1300},
+		{RoundUp, 100, 1200, 1200},
+		{RoundUp, 100, 1, 100},
+		{RoundDown, 100, 1299, 1200},
+		{RoundDown, 100, 1200, 1200},
+		{RoundDown, 100, 99, 0},
+		{RoundNearest, 1, 1234, 1234},
+	}
+
+	for _, tc := range tests {
+		rule := RoundingRule{TenderType: "cash", Mode: tc.mode, Increment: tc.increment}
+		if got := rule.Round(tc.amount); got != tc.want {
+			t.Errorf("%s to %d: Round(%d) = %d, want %d", tc.mode, tc.increment, tc.amount, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Adjustment reason directions
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// AdjustmentReason is a configurable reason code for manual stock adjustments
+type AdjustmentReason struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Direction string `json:"direction"`
+}
+
+type StockAdjustmentLine struct {
+	ProductID int             `json:"product_id"`
+	Quantity  decimal.Decimal `json:"quantity" swaggertype:"number"` // always positive, the sign comes from the reason direction
+	LotID     int             `json:"lot_id,omitempty"`              // optional lot the quantity is taken from or returned to
+}
+
+type StockAdjustment struct {
+	ID         int                   `json:"id"`
+	StoreID    int                   `json:"store_id"`
+	ReasonCode string                `json:"reason_code"`
+	Notes      string                `json:"notes"`
+	CreatedBy  string                `json:"created_by,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	Lines      []StockAdjustmentLine `json:"lines"`
+	Movements  []StockMovement       `json:"movements,omitempty"`
+}
+
+type CreateStockAdjustmentRequestSwagger struct {
+	StoreID    int                   `json:"store_id"`
+	ReasonCode string                `json:"reason_code"`
+	Notes      string                `json:"notes"`
+	Lines      []StockAdjustmentLine `json:"lines"`
+}
+
+// ShrinkageReportRow is the net adjustment per reason within one period.
+// Quantity and Value are signed like stock movements, losses are negative.
+type ShrinkageReportRow struct {
+	Period     time.Time       `json:"period"`
+	ReasonCode string          `json:"reason_code"`
+	ReasonName string          `json:"reason_name"`
+	Quantity   decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Value      int             `json:"value"`
+}
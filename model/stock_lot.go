@@ -0,0 +1,48 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// StockLot is a quantity of a product received with the same batch number
+// and expiry date
+type StockLot struct {
+	ID             int             `json:"id"`
+	ProductID      int             `json:"product_id"`
+	ProductName    string          `json:"product_name,omitempty"`
+	StoreID        int             `json:"store_id"`
+	BatchNumber    string          `json:"batch_number"`
+	ExpiryDate     *Date           `json:"expiry_date" swaggertype:"string" format:"date"`
+	Quantity       decimal.Decimal `json:"quantity" swaggertype:"number"`
+	GoodsReceiptID *int            `json:"goods_receipt_id,omitempty"`
+	ReceivedAt     time.Time       `json:"received_at"`
+}
+
+type CreateStockLotRequestSwagger struct {
+	ProductID   int             `json:"product_id"`
+	StoreID     int             `json:"store_id"`
+	BatchNumber string          `json:"batch_number"`
+	ExpiryDate  string          `json:"expiry_date" example:"2026-12-31"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+}
+
+// ExpiringLot is a lot with stock left that expires within the report window
+type ExpiringLot struct {
+	LotID       int             `json:"lot_id"`
+	ProductID   int             `json:"product_id"`
+	ProductName string          `json:"product_name"`
+	StoreID     int             `json:"store_id"`
+	BatchNumber string          `json:"batch_number"`
+	ExpiryDate  Date            `json:"expiry_date" swaggertype:"string" format:"date"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+	DaysLeft    int             `json:"days_left"` // negative when already expired
+}
+
+// TransactionDetailLot is the quantity a sold line took from one lot
+type TransactionDetailLot struct {
+	LotID       int             `json:"lot_id"`
+	BatchNumber string          `json:"batch_number"`
+	ExpiryDate  *Date           `json:"expiry_date" swaggertype:"string" format:"date"`
+	Quantity    decimal.Decimal `json:"quantity" swaggertype:"number"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Stock movement types
+const (
+	MovementSale       = "sale"
+	MovementRefund     = "refund"
+	MovementReceipt    = "receipt"
+	MovementAdjustment = "adjustment"
+	MovementTransfer   = "transfer"
+	MovementStockTake  = "stock_take"
+)
+
+// StockMovement is one entry of the stock ledger. Quantity is signed:
+// positive adds stock, negative removes it. BalanceAfter is the stock of
+// the product in the movement's store.
+type StockMovement struct {
+	ID            int             `json:"id"`
+	ProductID     int             `json:"product_id"`
+	StoreID       int             `json:"store_id"`
+	Type          string          `json:"type"`
+	Quantity      decimal.Decimal `json:"quantity" swaggertype:"number"`
+	BalanceAfter  decimal.Decimal `json:"balance_after" swaggertype:"number"`
+	Reason        string          `json:"reason,omitempty"`
+	ReferenceType string          `json:"reference_type,omitempty"`
+	ReferenceID   *int            `json:"reference_id,omitempty"`
+	CreatedBy     string          `json:"created_by,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
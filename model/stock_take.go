@@ -0,0 +1,60 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Stock take statuses
+const (
+	StockTakeOpen      = "open"
+	StockTakePosted    = "posted"
+	StockTakeCancelled = "cancelled"
+)
+
+type StockTake struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	StoreID     int             `json:"store_id"`
+	Status      string          `json:"status"`
+	Notes       string          `json:"notes"`
+	CategoryIDs []int           `json:"category_ids"`
+	CreatedBy   string          `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	PostedAt    *time.Time      `json:"posted_at,omitempty"`
+	Items       []StockTakeItem `json:"items,omitempty"`
+}
+
+// StockTakeItem compares the counted quantity of a product with what the
+// system expected at the time it was counted. ExpectedQty is the snapshot
+// plus every stock movement between opening the session and the last count,
+// so sales during the count don't show up as variance.
+type StockTakeItem struct {
+	ProductID     int              `json:"product_id"`
+	ProductName   string           `json:"product_name"`
+	SnapshotQty   decimal.Decimal  `json:"snapshot_qty" swaggertype:"number"`
+	ExpectedQty   decimal.Decimal  `json:"expected_qty" swaggertype:"number"`
+	CountedQty    *decimal.Decimal `json:"counted_qty" swaggertype:"number"`
+	Variance      *decimal.Decimal `json:"variance" swaggertype:"number"`
+	LastCountedAt *time.Time       `json:"last_counted_at,omitempty"`
+}
+
+// StockTakeCountBatch is a batch of counted quantities sent by one scanner.
+// Counts for the same product add up across batches.
+type StockTakeCountBatch struct {
+	Device    string               `json:"device"`
+	CountedBy string               `json:"-"`
+	Lines     []StockTakeCountLine `json:"lines"`
+}
+
+type StockTakeCountLine struct {
+	ProductID int             `json:"product_id"`
+	Quantity  decimal.Decimal `json:"quantity" swaggertype:"number"`
+}
+
+type CreateStockTakeRequestSwagger struct {
+	Name        string `json:"name"`
+	StoreID     int    `json:"store_id"`
+	Notes       string `json:"notes"`
+	CategoryIDs []int  `json:"category_ids"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"go-cashier-api/pkg/decimal"
+	"time"
+)
+
+// Stock transfer statuses
+const (
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+)
+
+type StockTransfer struct {
+	ID          int                 `json:"id"`
+	FromStoreID int                 `json:"from_store_id"`
+	ToStoreID   int                 `json:"to_store_id"`
+	Status      string              `json:"status"`
+	Notes       string              `json:"notes"`
+	SentBy      string              `json:"sent_by,omitempty"`
+	SentAt      time.Time           `json:"sent_at"`
+	ReceivedBy  string              `json:"received_by,omitempty"`
+	ReceivedAt  *time.Time          `json:"received_at,omitempty"`
+	Items       []StockTransferItem `json:"items"`
+}
+
+// StockTransferItem is one product of a transfer. QuantityReceived stays
+// nil while the transfer is in transit.
+type StockTransferItem struct {
+	ProductID        int              `json:"product_id"`
+	ProductName      string           `json:"product_name,omitempty"`
+	QuantitySent     decimal.Decimal  `json:"quantity_sent" swaggertype:"number"`
+	QuantityReceived *decimal.Decimal `json:"quantity_received" swaggertype:"number"`
+}
+
+// StockTransferLine is a product and quantity sent or received
+type StockTransferLine struct {
+	ProductID int             `json:"product_id"`
+	Quantity  decimal.Decimal `json:"quantity" swaggertype:"number"`
+}
+
+type SendStockTransferRequest struct {
+	FromStoreID int                 `json:"from_store_id"`
+	ToStoreID   int                 `json:"to_store_id"`
+	Notes       string              `json:"notes"`
+	Lines       []StockTransferLine `json:"lines"`
+}
+
+// ReceiveStockTransferRequest lists the quantities that arrived. Products
+// left out are received in full.
+type ReceiveStockTransferRequest struct {
+	Lines []StockTransferLine `json:"lines"`
+}
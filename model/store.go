@@ -0,0 +1,34 @@
+package model
+
+import "go-cashier-api/pkg/decimal"
+
+type Store struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+
+	// Currency is the base currency the store prices, sells and reports in
+	Currency string `json:"currency"`
+
+	// Timezone is the IANA timezone report dates are days in
+	Timezone string `json:"timezone"`
+}
+
+// DefaultTimezone is the timezone of a store unless set otherwise, WIB
+const DefaultTimezone = "Asia/Jakarta"
+
+type CreateStoreRequestSwagger struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Currency string `json:"currency" example:"IDR"`
+	Timezone string `json:"timezone" example:"Asia/Jakarta"`
+}
+
+// ProductStock is the stock of a product in one store. InTransit is the
+// quantity sent to this store by transfers that weren't received yet.
+type ProductStock struct {
+	StoreID   int             `json:"store_id"`
+	StoreName string          `json:"store_name"`
+	Stock     decimal.Decimal `json:"stock" swaggertype:"number"`
+	InTransit decimal.Decimal `json:"in_transit" swaggertype:"number"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "go-cashier-api/pkg/decimal"
+
+type Supplier struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	Address string `json:"address"`
+}
+
+type CreateSupplierRequestSwagger struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	Address string `json:"address"`
+}
+
+// OutstandingPurchaseOrders summarises what a supplier still has to deliver
+type OutstandingPurchaseOrders struct {
+	SupplierID          int             `json:"supplier_id"`
+	SupplierName        string          `json:"supplier_name"`
+	OpenOrders          int             `json:"open_orders"`
+	OutstandingQuantity decimal.Decimal `json:"outstanding_quantity" swaggertype:"number"`
+	OutstandingValue    int             `json:"outstanding_value"`
+}
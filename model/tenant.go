@@ -0,0 +1,10 @@
+package model
+
+// Tenant is an independent business hosted by this API. Requests are
+// resolved to a tenant by their API key or host name.
+type Tenant struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Host   string `json:"host"`
+	APIKey string `json:"-"`
+}
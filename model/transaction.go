@@ -1,23 +1,55 @@
 package model
 
 import (
+	"go-cashier-api/pkg/decimal"
 	"time"
 )
 
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID                int                 `json:"id"`
+	StoreID           int                 `json:"store_id"`
+	TotalAmount       int                 `json:"total_amount"` // net of DiscountAmount
+	DiscountAmount    int                 `json:"discount_amount,omitempty"`
+	CouponCode        string              `json:"coupon_code,omitempty"`
+	RoundingAmount    int                 `json:"rounding_amount"` // paid on top of TotalAmount
+	Currency          string              `json:"currency"`        // base currency of the store
+	ExpiredOverrideBy string              `json:"expired_override_by,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	Details           []TransactionDetail `json:"details"`
+	Payments          []Payment           `json:"payments,omitempty"`
+	Change            int                 `json:"change,omitempty"`
+
+	// KitchenItems are the lines the sale routed to preparation stations
+	KitchenItems []KitchenItem `json:"-"`
 }
 
 type TransactionDetail struct {
-	ID            int    `json:"id,omitempty"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID            int             `json:"id,omitempty"`
+	TransactionID int             `json:"transaction_id"`
+	ProductID     int             `json:"product_id"`
+	ProductName   string          `json:"product_name,omitempty"`
+	ParentID      *int            `json:"parent_id,omitempty"` // of a variant, for rolling up to the parent
+	Quantity      decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Unit          string          `json:"unit,omitempty"`
+	Subtotal      int             `json:"subtotal"`
+	Discount      int             `json:"discount,omitempty"` // share of the coupon discount, not taken off Subtotal
+
+	// Pack and PackQuantity are set when the line was sold in packs;
+	// Quantity is always in the product's unit
+	Pack         string           `json:"pack,omitempty"`
+	PackQuantity *decimal.Decimal `json:"pack_quantity,omitempty" swaggertype:"number"`
+
+	Lots []TransactionDetailLot `json:"lots,omitempty"`
+
+	// Components is what a composite product line took from the stock of
+	// its components, the line itself takes none
+	Components []ProductComponent `json:"components,omitempty"`
+
+	// Modifiers picked for the line, their price deltas are in Subtotal
+	Modifiers []TransactionDetailModifier `json:"modifiers,omitempty"`
+
+	// GiftCards issued by a gift card line, one per unit
+	GiftCards []GiftCard `json:"gift_cards,omitempty"`
 }
 
 type TransactionResponse struct {
@@ -32,20 +64,101 @@ type TransactionsResponse struct {
 	Data               []Transaction       `json:"data"`
 	TotalTransactions  int                 `json:"total_transactions"`
 	TotalRevenue       int                 `json:"total_revenue"`
+	TotalRounding      int                 `json:"total_rounding"` // collected on top of TotalRevenue
 	BestSellingProduct *BestSellingProduct `json:"best_selling_product"`
 }
 
+// CheckoutItem names the product by ID or by one of its barcodes. A scale
+// or other in-store barcode also sets the quantity, and for price labels
+// the amount of the line. With Pack the quantity counts packs; a pack
+// barcode sets it.
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int             `json:"product_id,omitempty"`
+	Barcode   string          `json:"barcode,omitempty"`
+	Pack      string          `json:"pack,omitempty"`
+	Quantity  decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Amount    *int            `json:"-"` // the label price, used instead of price × quantity
+
+	// ModifierIDs picks modifiers from the product's modifier groups,
+	// Modifiers holds them once checked against the group rules
+	ModifierIDs []int                       `json:"modifier_ids,omitempty"`
+	Modifiers   []TransactionDetailModifier `json:"-"`
 }
 
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	StoreID int            `json:"store_id,omitempty"` // defaults to the first store
+	Items   []CheckoutItem `json:"items"`
+
+	// OverrideExpiredBy names the manager who allows selling from expired
+	// lots. Checkout is rejected when it would consume an expired lot and
+	// this is empty.
+	OverrideExpiredBy string `json:"override_expired_by,omitempty"`
+
+	// Tenders pay the sale. They are optional, a sale without them is
+	// recorded as before with no payments.
+	Tenders []Tender `json:"tenders,omitempty"`
+
+	// CouponCode discounts the sale. Customer identifies the buyer, e.g.
+	// by phone or member number, for coupons limited per customer.
+	CouponCode string `json:"coupon_code,omitempty"`
+	Customer   string `json:"customer,omitempty"`
+
+	// Rounding holds the rounding rules by tender type, loaded by the service
+	Rounding map[string]RoundingRule `json:"-"`
+
+	// OrderID is set when an open order is checked out, its lines become the
+	// items and it is marked paid in the same database transaction. Shares
+	// sells the order now and leaves it to be paid in that many equal
+	// shares.
+	OrderID int `json:"-"`
+	Shares  int `json:"-"`
 }
 
 type BestSellingProduct struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	TotalSold int    `json:"total_sold"`
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	TotalSold decimal.Decimal `json:"total_sold" swaggertype:"number"`
+}
+
+// Sales report groupings
+const (
+	SalesByHour     = "hour"
+	SalesByDay      = "day"
+	SalesByWeek     = "week"
+	SalesByMonth    = "month"
+	SalesByCategory = "category"
+	SalesByProduct  = "product"
+)
+
+// SalesReportQuery selects the sales of a date range and how they are
+// grouped. Top or Bottom keep only that many products or categories with
+// the highest or lowest net sales.
+type SalesReportQuery struct {
+	StartDate time.Time
+	EndDate   time.Time
+	GroupBy   string
+	StoreID   int
+	Top       int
+	Bottom    int
+	RollUp    bool   // report variants as their parent product
+	Timezone  string // periods are bucketed in this timezone
+}
+
+// SalesReportRow is the sales of one group. Gross is before coupon
+// discounts and Net after them; rounding is booked per sale and left out.
+type SalesReportRow struct {
+	Period       *time.Time      `json:"period,omitempty"`
+	ID           *int            `json:"id,omitempty"` // category or product, none for uncategorized
+	Name         string          `json:"name,omitempty"`
+	Quantity     decimal.Decimal `json:"quantity" swaggertype:"number"`
+	Transactions int             `json:"transactions"`
+	Gross        int             `json:"gross"`
+	Discounts    int             `json:"discounts"`
+	Net          int             `json:"net"`
+}
+
+type SalesReport struct {
+	GroupBy string           `json:"group_by"`
+	Rows    []SalesReportRow `json:"rows"`
+	Totals  SalesReportRow   `json:"totals"` // of the whole range, also when rows are limited
 }
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"fmt"
+
+	"go-cashier-api/pkg/decimal"
+)
+
+// Units of measure a product is stocked and sold in
+const (
+	UnitPiece    = "pcs"
+	UnitKilogram = "kg"
+	UnitGram     = "g"
+	UnitLitre    = "l"
+	UnitMetre    = "m"
+)
+
+// unitPrecision is the number of decimal places a quantity of each unit
+// may have
+var unitPrecision = map[string]int{
+	UnitPiece:    0,
+	UnitKilogram: 3,
+	UnitGram:     0,
+	UnitLitre:    3,
+	UnitMetre:    2,
+}
+
+// UnitPrecision returns the decimal places allowed for quantities of unit
+// and whether the unit is known
+func UnitPrecision(unit string) (int, bool) {
+	places, ok := unitPrecision[unit]
+	return places, ok
+}
+
+// CheckQuantity returns an error when quantity has more decimal places than
+// unit allows, e.g. 1.5 pcs
+func CheckQuantity(unit string, quantity decimal.Decimal) error {
+	places, ok := unitPrecision[unit]
+	if !ok {
+		return fmt.Errorf("unknown unit %q", unit)
+	}
+	if quantity.Round(places) != quantity {
+		if places == 0 {
+			return fmt.Errorf("quantity %s must be a whole number of %s", quantity, unit)
+		}
+		return fmt.Errorf("quantity %s of %s can have at most %d decimal places", quantity, unit, places)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package barcode
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Normalize validates an EAN-13 or UPC-A barcode and returns it as 13
+// digits. A UPC-A code is the EAN-13 code with a leading zero, so both
+// forms of the same product scan to the same value.
+func Normalize(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", errors.New("barcode must only contain digits")
+		}
+	}
+
+	switch len(code) {
+	case 12: // UPC-A
+		code = "0" + code
+	case 13: // EAN-13
+	default:
+		return "", errors.New("barcode must be an EAN-13 or UPC-A code")
+	}
+
+	if CheckDigit(code[:12]) != code[12] {
+		return "", errors.New("barcode check digit is invalid")
+	}
+	return code, nil
+}
+
+// CheckDigit computes the GTIN check digit of the given digits. Counting
+// from the right, digits are weighted 3, 1, 3, ...
+func CheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// Embedded value types of an in-store barcode
+const (
+	ValueWeight = "weight"
+	ValuePrice  = "price"
+)
+
+// Format describes an in-store EAN-13 barcode, such as a scale label, that
+// embeds an item code and a weight or price. The 12 digits before the check
+// digit are laid out as prefix, item code, any unused digits (often a price
+// check digit) and the value.
+type Format struct {
+	Prefix        string // e.g. "21"; in-store codes start with 2
+	CodeLength    int    // digits of the item code (PLU)
+	ValueType     string // ValueWeight or ValuePrice
+	ValueLength   int    // digits of the value
+	ValueDecimals int    // implied decimal places of a weight, e.g. 3 for grams of a kilogram
+}
+
+// Embedded is what a barcode of a Format carries
+type Embedded struct {
+	Code  int   // item code (PLU)
+	Value int64 // raw value, see Format.ValueDecimals
+}
+
+// Validate checks that the parts of the format fit in an EAN-13 code
+func (f Format) Validate() error {
+	for _, c := range f.Prefix {
+		if c < '0' || c > '9' {
+			return errors.New("barcode format prefix must only contain digits")
+		}
+	}
+	if f.Prefix == "" || f.CodeLength <= 0 || f.ValueLength <= 0 {
+		return errors.New("barcode format needs a prefix, code length and value length")
+	}
+	if len(f.Prefix)+f.CodeLength+f.ValueLength > 12 {
+		return errors.New("barcode format parts are longer than an EAN-13 code")
+	}
+	if f.ValueType != ValueWeight && f.ValueType != ValuePrice {
+		return errors.New("barcode format value type must be weight or price")
+	}
+	if f.ValueDecimals < 0 || f.ValueDecimals > f.ValueLength {
+		return errors.New("barcode format value decimals is invalid")
+	}
+	if f.ValueType == ValuePrice && f.ValueDecimals != 0 {
+		return errors.New("price barcodes carry the amount in the unit of product prices, value decimals must be 0")
+	}
+	return nil
+}
+
+// Parse reads a normalized 13-digit code. It reports false when the code
+// doesn't start with the format's prefix.
+func (f Format) Parse(code string) (Embedded, bool) {
+	if len(code) != 13 || !strings.HasPrefix(code, f.Prefix) {
+		return Embedded{}, false
+	}
+
+	codeStart := len(f.Prefix)
+	valueStart := 12 - f.ValueLength
+
+	var e Embedded
+	item, err := strconv.Atoi(code[codeStart : codeStart+f.CodeLength])
+	if err != nil {
+		return Embedded{}, false
+	}
+	e.Code = item
+	e.Value, err = strconv.ParseInt(code[valueStart:12], 10, 64)
+	if err != nil {
+		return Embedded{}, false
+	}
+	return e, true
+}
@@ -0,0 +1,220 @@
+package decimal
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Places is the number of decimal places a Decimal keeps, enough for grams
+// of a kilogram or millilitres of a litre
+const Places = 3
+
+const one = 1000 // 10^Places
+
+// Decimal is a fixed-point number stored in thousandths. Values can be
+// compared, added and subtracted with the usual operators; Mul multiplies
+// and Amount prices a quantity.
+// It is encoded as a JSON number and maps to a NUMERIC column.
+type Decimal int64
+
+// New returns the decimal value of n
+func New(n int) Decimal {
+	return Decimal(n) * one
+}
+
+// Parse reads a decimal such as "2", "-0.75" or "1.250". More than Places
+// decimal places is an error rather than a silent rounding.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("invalid decimal %q", s)
+	}
+	if len(fraction) > Places {
+		if strings.Trim(fraction[Places:], "0") != "" {
+			return 0, fmt.Errorf("decimal %q has more than %d decimal places", s, Places)
+		}
+		fraction = fraction[:Places]
+	}
+
+	var d int64
+	if whole != "" {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid decimal %q", s)
+		}
+		d = n * one
+	}
+	if fraction != "" {
+		n, err := strconv.ParseInt(fraction+strings.Repeat("0", Places-len(fraction)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid decimal %q", s)
+		}
+		d += n
+	}
+
+	if negative {
+		d = -d
+	}
+	return Decimal(d), nil
+}
+
+// FromScaled returns the decimal of n with the given number of implied
+// decimal places, e.g. FromScaled(1250, 3) is 1.25
+func FromScaled(n int64, places int) Decimal {
+	for ; places < Places; places++ {
+		n *= 10
+	}
+	for ; places > Places; places-- {
+		n /= 10
+	}
+	return Decimal(n)
+}
+
+// FromFloat returns f rounded to Places, for estimates such as averages
+func FromFloat(f float64) Decimal {
+	return Decimal(math.Round(f * one))
+}
+
+// String formats d without trailing zeros, e.g. "2" or "0.75"
+func (d Decimal) String() string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	s := sign + strconv.FormatInt(int64(d/one), 10)
+	if fraction := int64(d % one); fraction != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*d", Places, fraction), "0")
+	}
+	return s
+}
+
+// Float64 returns d as a float, for estimates such as averages
+func (d Decimal) Float64() float64 {
+	return float64(d) / one
+}
+
+// IsInteger reports whether d has no fractional part
+func (d Decimal) IsInteger() bool {
+	return d%one == 0
+}
+
+// Round rounds d to the given number of decimal places. Halves are
+// rounded away from zero, as amounts are.
+func (d Decimal) Round(places int) Decimal {
+	step := int64(pow10(Places - places))
+	return Decimal(roundDiv(int64(d), step)) * Decimal(step)
+}
+
+// RoundUp rounds d away from zero to the given number of decimal places,
+// for quantities that must not fall short such as reorder suggestions
+func (d Decimal) RoundUp(places int) Decimal {
+	step := Decimal(pow10(Places - places))
+	if r := d % step; r > 0 {
+		d += step - r
+	} else if r < 0 {
+		d -= step + r
+	}
+	return d
+}
+
+// Amount returns unitPrice × d rounded to a whole amount. Halves are
+// rounded away from zero. Every line amount and subtotal goes through
+// here so a quantity is priced the same way everywhere.
+func (d Decimal) Amount(unitPrice int) int {
+	return roundDiv(int64(d)*int64(unitPrice), one)
+}
+
+// PerUnit returns amount ÷ d rounded to a whole amount, e.g. the cost of
+// one can from the cost of a carton
+func (d Decimal) PerUnit(amount int) int {
+	return roundDiv(int64(amount)*one, int64(d))
+}
+
+// Mul returns d × e rounded to Places
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal(roundDiv(int64(d)*int64(e), one))
+}
+
+// Div returns amount ÷ unitPrice as a decimal rounded to Places, the
+// quantity that costs amount at unitPrice
+func Div(amount, unitPrice int) Decimal {
+	return Decimal(roundDiv(int64(amount)*one, int64(unitPrice)))
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+func roundDiv(n, d int64) int {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	if n < 0 {
+		return -int((-n + d/2) / d)
+	}
+	return int((n + d/2) / d)
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	if s == "null" {
+		return nil
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (d *Decimal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		parsed, err := Parse(string(v))
+		*d = parsed
+		return err
+	case string:
+		parsed, err := Parse(v)
+		*d = parsed
+		return err
+	case int64:
+		*d = New(int(v))
+		return nil
+	case nil:
+		*d = 0
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Decimal", value)
+}
+
+// Value implements driver.Valuer
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
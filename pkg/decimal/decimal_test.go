@@ -0,0 +1,184 @@
+package decimal
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Decimal
+		wantErr bool
+	}{
+		{in: "2", want: 2000},
+		{in: "-0.75", want: -750},
+		{in: "1.250", want: 1250},
+		{in: "1.2500", want: 1250}, // trailing zeros past Places are fine
+		{in: ".5", want: 500},
+		{in: "5.", want: 5000},
+		{in: " 3 ", want: 3000},
+		{in: "0.001", want: 1},
+		{in: "1.2345", wantErr: true}, // no silent rounding
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "1.-5", wantErr: true},
+		{in: "+1", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := Parse(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %s, want an error", tc.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tc.in, err)
+			}
+			if got != tc.want {
+				t.Errorf("Parse(%q) = %d, want %d", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[Decimal]string{
+		2000:  "2",
+		-750:  "-0.75",
+		1250:  "1.25",
+		5:     "0.005",
+		-5:    "-0.005",
+		0:     "0",
+		12345: "12.345",
+	}
+	for d, want := range tests {
+		if got := d.String(); got != want {
+			t.Errorf("Decimal(%d).String() = %q, want %q", int64(d), got, want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		d      Decimal
+		places int
+		want   Decimal
+	}{
+		{1234, 2, 1230},
+		{1235, 2, 1240}, // halves away from zero
+		{-1235, 2, -1240},
+		{1499, 0, 1000},
+		{1500, 0, 2000},
+		{2500, 0, 3000}, // not to even
+		{-2500, 0, -3000},
+		{1234, 3, 1234},
+	}
+	for _, tc := range tests {
+		if got := tc.d.Round(tc.places); got != tc.want {
+			t.Errorf("%s.Round(%d) = %s, want %s", tc.d, tc.places, got, tc.want)
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := []struct {
+		d      Decimal
+		places int
+		want   Decimal
+	}{
+		{1001, 0, 2000},
+		{1000, 0, 1000},
+		{1, 0, 1000},
+		{-1001, 0, -2000}, // away from zero
+		{1234, 1, 1300},
+		{1234, 3, 1234},
+	}
+	for _, tc := range tests {
+		if got := tc.d.RoundUp(tc.places); got != tc.want {
+			t.Errorf("%s.RoundUp(%d) = %s, want %s", tc.d, tc.places, got, tc.want)
+		}
+	}
+}
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		quantity  Decimal
+		unitPrice int
+		want      int
+	}{
+		{New(3), 2500, 7500},
+		{1500, 999, 1499}, // 1498.5
+		{333, 3, 1},       // 0.999
+		{250, 2, 1},       // 0.5, away from zero
+		{-250, 2, -1},
+		{1, 499, 0}, // 0.499
+		{1250, 16000, 20000},
+	}
+	for _, tc := range tests {
+		if got := tc.quantity.Amount(tc.unitPrice); got != tc.want {
+			t.Errorf("%s.Amount(%d) = %d, want %d", tc.quantity, tc.unitPrice, got, tc.want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		d, e, want Decimal
+	}{
+		{1500, 1500, 2250},
+		{333, 500, 167}, // 0.1665
+		{-333, 500, -167},
+		{New(2), 125, 250},
+	}
+	for _, tc := range tests {
+		if got := tc.d.Mul(tc.e); got != tc.want {
+			t.Errorf("%s.Mul(%s) = %s, want %s", tc.d, tc.e, got, tc.want)
+		}
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		amount, unitPrice int
+		want              Decimal
+	}{
+		{1000, 3, 333333}, // 333.333
+		{2, 3, 667},       // 0.6667
+		{20000, 16000, 1250},
+		{-2, 3, -667},
+	}
+	for _, tc := range tests {
+		if got := Div(tc.amount, tc.unitPrice); got != tc.want {
+			t.Errorf("Div(%d, %d) = %s, want %s", tc.amount, tc.unitPrice, got, tc.want)
+		}
+	}
+}
+
+func TestPerUnit(t *testing.T) {
+	if got := New(12).PerUnit(10000); got != 833 {
+		t.Errorf("12.PerUnit(10000) = %d, want 833", got)
+	}
+	if got := Decimal(1500).PerUnit(1000); got != 667 {
+		t.Errorf("1.5.PerUnit(1000) = %d, want 667", got)
+	}
+}
+
+func TestFromScaled(t *testing.T) {
+	tests := []struct {
+		n      int64
+		places int
+		want   Decimal
+	}{
+		{1250, 3, 1250},
+		{125, 2, 1250},
+		{5, 0, 5000},
+		{12345, 4, 1234}, // places past Places are cut off
+	}
+	for _, tc := range tests {
+		if got := FromScaled(tc.n, tc.places); got != tc.want {
+			t.Errorf("FromScaled(%d, %d) = %s, want %s", tc.n, tc.places, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package decimal
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RatePlaces is the number of decimal places a Rate keeps, enough for the
+// rate of a weak currency in a strong one such as IDR in USD (0.0000625)
+const RatePlaces = 10
+
+const rateOne = 10_000_000_000 // 10^RatePlaces
+
+// Rate is a fixed-point exchange rate stored in units of 10^-RatePlaces.
+// Unlike Decimal, more places than it keeps are rounded half away from
+// zero, as rates are quoted with any number of digits; a rate that rounds
+// to 0 must be rejected by the caller.
+// It is encoded as a JSON number and maps to a NUMERIC column.
+type Rate int64
+
+// ParseRate reads a rate such as "16250" or "0.0000625"
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" || strings.ContainsAny(whole+fraction, "+-") {
+		return 0, fmt.Errorf("invalid rate %q", s)
+	}
+
+	roundUp := false
+	if len(fraction) > RatePlaces {
+		if strings.Trim(fraction[RatePlaces:], "0123456789") != "" {
+			return 0, fmt.Errorf("invalid rate %q", s)
+		}
+		roundUp = fraction[RatePlaces] >= '5'
+		fraction = fraction[:RatePlaces]
+	}
+
+	var r int64
+	if whole != "" {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || n > math.MaxInt64/rateOne-1 {
+			return 0, fmt.Errorf("invalid rate %q", s)
+		}
+		r = n * rateOne
+	}
+	if fraction != "" {
+		n, err := strconv.ParseInt(fraction+strings.Repeat("0", RatePlaces-len(fraction)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rate %q", s)
+		}
+		r += n
+	}
+	if roundUp {
+		r++
+	}
+
+	if negative {
+		r = -r
+	}
+	return Rate(r), nil
+}
+
+// String formats r without trailing zeros, e.g. "16250" or "0.0000625"
+func (r Rate) String() string {
+	sign := ""
+	if r < 0 {
+		sign = "-"
+		r = -r
+	}
+
+	s := sign + strconv.FormatInt(int64(r/rateOne), 10)
+	if fraction := int64(r % rateOne); fraction != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*d", RatePlaces, fraction), "0")
+	}
+	return s
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	if s == "null" {
+		return nil
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (r *Rate) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		parsed, err := ParseRate(string(v))
+		*r = parsed
+		return err
+	case string:
+		parsed, err := ParseRate(v)
+		*r = parsed
+		return err
+	case int64:
+		*r = Rate(v * rateOne)
+		return nil
+	case nil:
+		*r = 0
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Rate", value)
+}
+
+// Value implements driver.Valuer
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
@@ -0,0 +1,52 @@
+package decimal
+
+import "testing"
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "16250", want: "16250"},
+		{in: "0.0000625", want: "0.0000625"},
+		{in: "0.000063", want: "0.000063"},
+		{in: "1.2345678901", want: "1.2345678901"},
+		{in: "0.00000000004", want: "0"},            // rounds to 0, callers reject it
+		{in: "0.00000000005", want: "0.0000000001"}, // halves round up
+		{in: "0.99999999995", want: "1"},
+		{in: "-1.5", want: "-1.5"},
+		{in: "", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "0.00000000001x", wantErr: true},
+		{in: "1000000000000", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseRate(tc.in)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRate(%q) = %s, want an error", tc.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRate(%q): %v", tc.in, err)
+			}
+			if got.String() != tc.want {
+				t.Errorf("ParseRate(%q) = %s, want %s", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRateScan(t *testing.T) {
+	var r Rate
+	if err := r.Scan([]byte("0.0000630000")); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if r.String() != "0.000063" {
+		t.Errorf("Scan = %s, want 0.000063", r)
+	}
+}
@@ -0,0 +1,111 @@
+package event
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Names of the events published by the services
+const (
+	LowStock    = "inventory.low_stock"
+	KitchenItem = "kitchen.item" // a line was routed to a station or changed status
+)
+
+// Event is a notification published on the bus
+type Event struct {
+	Name       string
+	Payload    interface{}
+	OccurredAt time.Time
+}
+
+// Handler is called for every published event it subscribed to
+type Handler func(Event)
+
+// queueSize is how many events a subscriber can fall behind before new
+// ones are dropped
+const queueSize = 256
+
+// subscription delivers events to one handler from its own goroutine, one
+// at a time and in the order they were published
+type subscription struct {
+	id      int
+	handler Handler
+	queue   chan Event
+}
+
+func (sub *subscription) run(name string) {
+	for e := range sub.queue {
+		sub.deliver(name, e)
+	}
+}
+
+func (sub *subscription) deliver(name string, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("event handler for %s panicked: %v", name, r)
+		}
+	}()
+	sub.handler(e)
+}
+
+// Bus is a simple in-process publish/subscribe bus
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[string][]*subscription
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]*subscription)}
+}
+
+// Subscribe registers a handler for the named event. The returned function
+// removes it again, for subscribers that live as long as a request; events
+// already queued for it are still delivered.
+func (b *Bus) Subscribe(name string, handler Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	sub := &subscription{id: b.nextID, handler: handler, queue: make(chan Event, queueSize)}
+	b.handlers[name] = append(b.handlers[name], sub)
+	go sub.run(name)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		subs := b.handlers[name]
+		for i, s := range subs {
+			if s == sub {
+				b.handlers[name] = append(subs[:i:i], subs[i+1:]...)
+				close(sub.queue)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers an event to its subscribers. Each subscriber has its own
+// queue, so a slow one never delays the request that fired the event or the
+// other subscribers, and sees events in the order they were published. An
+// event is dropped for a subscriber whose queue is full.
+func (b *Bus) Publish(name string, payload interface{}) {
+	if b == nil {
+		return
+	}
+
+	e := Event{Name: name, Payload: payload, OccurredAt: time.Now()}
+
+	// The lock is held while queueing so an unsubscribe can't close a
+	// queue under us
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.handlers[name] {
+		select {
+		case sub.queue <- e:
+		default:
+			log.Printf("event subscriber %d for %s is behind, dropped an event", sub.id, name)
+		}
+	}
+}
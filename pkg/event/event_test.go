@@ -0,0 +1,78 @@
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPublishKeepsOrder(t *testing.T) {
+	bus := NewBus()
+	got := make(chan int, 100)
+	unsubscribe := bus.Subscribe("test", func(e Event) {
+		got <- e.Payload.(int)
+	})
+	defer unsubscribe()
+
+	for i := 0; i < 100; i++ {
+		bus.Publish("test", i)
+	}
+	for want := 0; want < 100; want++ {
+		select {
+		case n := <-got:
+			if n != want {
+				t.Fatalf("event %d arrived as number %d", n, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d never arrived", want)
+		}
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus := NewBus()
+	got := make(chan int, 10)
+	unsubscribe := bus.Subscribe("test", func(e Event) {
+		got <- e.Payload.(int)
+	})
+
+	bus.Publish("test", 1)
+	unsubscribe()
+	bus.Publish("test", 2)
+
+	select {
+	case n := <-got:
+		if n != 1 {
+			t.Fatalf("got event %d, want 1", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event published before unsubscribing never arrived")
+	}
+	select {
+	case n := <-got:
+		t.Fatalf("got event %d after unsubscribing", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPanickingHandler(t *testing.T) {
+	bus := NewBus()
+	got := make(chan int, 10)
+	defer bus.Subscribe("test", func(e Event) {
+		if e.Payload.(int) == 1 {
+			panic("boom")
+		}
+		got <- e.Payload.(int)
+	})()
+
+	bus.Publish("test", 1)
+	bus.Publish("test", 2)
+
+	select {
+	case n := <-got:
+		if n != 2 {
+			t.Fatalf("got event %d, want 2", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a panic stopped later events from being delivered")
+	}
+}
@@ -0,0 +1,238 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-cashier-api/pkg/decimal"
+)
+
+// Export formats
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// Writer writes a table one row at a time, so a large export never has to
+// be held in memory. Close must be called to finish the file.
+type Writer interface {
+	Write(row []interface{}) error
+	Close() error
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Check returns an error when the format or locale is not supported
+func Check(format, locale string) error {
+	if format != CSV && format != XLSX {
+		return fmt.Errorf("invalid format. Use csv or xlsx")
+	}
+	if _, ok := locales[locale]; !ok {
+		return fmt.Errorf("invalid locale. Use en or id, or leave it out for plain numbers")
+	}
+	return nil
+}
+
+// NewWriter returns a writer for a table with the given header. Numbers are
+// formatted for locale in CSV; XLSX stores them as numbers and leaves the
+// formatting to the spreadsheet. sheet names the XLSX worksheet.
+// Nothing is written to w before the first row or Close, so an export whose
+// query fails before it returns a row leaves w untouched.
+func NewWriter(w io.Writer, format, locale, sheet string, header []string) (Writer, error) {
+	if err := Check(format, locale); err != nil {
+		return nil, err
+	}
+	return &tableWriter{w: w, format: format, locale: locale, sheet: sheet, header: header}, nil
+}
+
+// tableWriter starts the file and writes the header with the first row
+type tableWriter struct {
+	w      io.Writer
+	format string
+	locale string
+	sheet  string
+	header []string
+	writer Writer // nil until the file is started
+}
+
+func (t *tableWriter) start() error {
+	if t.writer != nil {
+		return nil
+	}
+
+	var writer Writer
+	if t.format == XLSX {
+		x, err := newXLSXWriter(t.w, t.sheet)
+		if err != nil {
+			return err
+		}
+		writer = x
+	} else {
+		writer = newCSVWriter(t.w, locales[t.locale])
+	}
+
+	row := make([]interface{}, len(t.header))
+	for i, name := range t.header {
+		row[i] = name
+	}
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+	t.writer = writer
+	return nil
+}
+
+func (t *tableWriter) Write(row []interface{}) error {
+	if err := t.start(); err != nil {
+		return err
+	}
+	return t.writer.Write(row)
+}
+
+// Close finishes the file, which holds only the header when no row was
+// written
+func (t *tableWriter) Close() error {
+	if err := t.start(); err != nil {
+		return err
+	}
+	return t.writer.Close()
+}
+
+// numberFormat is how a locale writes numbers. The zero value writes them
+// plain, e.g. 1234.5, for other programs to read.
+type numberFormat struct {
+	decimal   string
+	thousands string
+}
+
+var locales = map[string]numberFormat{
+	"":   {},
+	"en": {decimal: ".", thousands: ","},
+	"id": {decimal: ",", thousands: "."},
+}
+
+// format writes a plain number such as -1234.5 in the locale
+func (f numberFormat) format(number string) string {
+	if f.decimal == "" {
+		return number
+	}
+
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(number, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(f.thousands)
+		}
+		b.WriteRune(c)
+	}
+	if hasFraction {
+		b.WriteString(f.decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// cell returns the text of a value and whether it is a number. Nil values
+// and nil pointers are empty cells.
+func cell(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, false
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case decimal.Decimal:
+		return v.String(), true
+	case float64:
+		return decimal.FromFloat(v).String(), true
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05"), false
+	case *int:
+		if v == nil {
+			return "", false
+		}
+		return cell(*v)
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return cell(*v)
+	case *decimal.Decimal:
+		if v == nil {
+			return "", false
+		}
+		return cell(*v)
+	case *time.Time:
+		if v == nil {
+			return "", false
+		}
+		return cell(*v)
+	case fmt.Stringer:
+		return v.String(), false
+	default:
+		return fmt.Sprint(v), false
+	}
+}
+
+// csvWriter writes RFC 4180 CSV. With a locale the file is meant for a
+// spreadsheet: it starts with a UTF-8 byte order mark so names keep their
+// accents, and locales with a decimal comma separate fields with
+// semicolons, as spreadsheets in those locales expect.
+type csvWriter struct {
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	numbers numberFormat
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, numbers numberFormat) *csvWriter {
+	buf := bufio.NewWriter(w)
+	if numbers.decimal != "" {
+		buf.WriteString("\uFEFF")
+	}
+	c := csv.NewWriter(buf)
+	if numbers.decimal == "," {
+		c.Comma = ';'
+	}
+	return &csvWriter{buf: buf, csv: c, numbers: numbers}
+}
+
+func (w *csvWriter) Write(row []interface{}) error {
+	w.record = w.record[:0]
+	for _, value := range row {
+		text, number := cell(value)
+		if number {
+			text = w.numbers.format(text)
+		}
+		w.record = append(w.record, text)
+	}
+	return w.csv.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
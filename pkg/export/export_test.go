@@ -0,0 +1,205 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go-cashier-api/pkg/decimal"
+)
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		locale string
+		number string
+		want   string
+	}{
+		{"", "1234567.5", "1234567.5"},
+		{"en", "1234567.5", "1,234,567.5"},
+		{"id", "1234567.5", "1.234.567,5"},
+		{"en", "-1234", "-1,234"},
+		{"id", "-123456", "-123.456"},
+		{"en", "123", "123"},
+		{"en", "1000", "1,000"},
+		{"id", "0.75", "0,75"},
+		{"id", "0", "0"},
+	}
+	for _, tc := range tests {
+		if got := locales[tc.locale].format(tc.number); got != tc.want {
+			t.Errorf("format %s in %q = %q, want %q", tc.number, tc.locale, got, tc.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, ok := range [][2]string{{CSV, ""}, {CSV, "en"}, {XLSX, "id"}} {
+		if err := Check(ok[0], ok[1]); err != nil {
+			t.Errorf("Check(%q, %q): %v", ok[0], ok[1], err)
+		}
+	}
+	for _, bad := range [][2]string{{"pdf", ""}, {"", ""}, {CSV, "fr"}} {
+		if err := Check(bad[0], bad[1]); err == nil {
+			t.Errorf("Check(%q, %q) = nil, want an error", bad[0], bad[1])
+		}
+	}
+}
+
+// testRow has a value of each kind a cell can hold
+func testRow() []interface{} {
+	var noID *int
+	return []interface{}{1234, decimal.Decimal(1234500), "Kopi, susu", nil, noID,
+		time.Date(2026, 5, 12, 9, 30, 0, 0, time.UTC), -0.25}
+}
+
+func writeTable(t *testing.T, format, locale string, rows ...[]interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, locale, "Sales", []string{"id", "amount", "name", "note", "parent_id", "created_at", "change"})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("NewWriter wrote %d bytes before the first row", buf.Len())
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"", "id,amount,name,note,parent_id,created_at,change\n" +
+			"1234,1234.5,\"Kopi, susu\",,,2026-05-12 09:30:00,-0.25\n"},
+		{"en", "\uFEFFid,amount,name,note,parent_id,created_at,change\n" +
+			"\"1,234\",\"1,234.5\",\"Kopi, susu\",,,2026-05-12 09:30:00,-0.25\n"},
+		{"id", "\uFEFFid;amount;name;note;parent_id;created_at;change\n" +
+			"1.234;1.234,5;Kopi, susu;;;2026-05-12 09:30:00;-0,25\n"},
+	}
+	for _, tc := range tests {
+		t.Run("locale "+tc.locale, func(t *testing.T) {
+			if got := string(writeTable(t, CSV, tc.locale, testRow())); got != tc.want {
+				t.Errorf("CSV =\n%q\nwant\n%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCSVWithoutRows(t *testing.T) {
+	if got, want := string(writeTable(t, CSV, "")), "id,amount,name,note,parent_id,created_at,change\n"; got != want {
+		t.Errorf("CSV = %q, want only the header %q", got, want)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	data := writeTable(t, XLSX, "en", testRow(), []interface{}{1, 0, "a < b & c"})
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("read workbook: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		parts[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook has no %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Sales"`) {
+		t.Errorf("workbook.xml does not name the sheet Sales: %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`,
+		// numbers are stored plain whatever the locale
+		`<c><v>1234</v></c><c><v>1234.5</v></c>`,
+		`<c t="inlineStr"><is><t xml:space="preserve">Kopi, susu</t></is></c><c/><c/>`,
+		`<t xml:space="preserve">2026-05-12 09:30:00</t>`,
+		`<c><v>-0.25</v></c></row>`,
+		`<t xml:space="preserve">a &lt; b &amp; c</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1.xml does not contain %s:\n%s", want, sheet)
+		}
+	}
+	if !strings.HasSuffix(sheet, "</sheetData></worksheet>") {
+		t.Errorf("sheet1.xml is not closed: %s", sheet)
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Transactions", "Transactions"},
+		{"bundle-sales", "bundle-sales"},
+		{"a/b:c", "a-b-c"},
+		{`[x]*?\`, "-x----"},
+		{"", "Sheet1"},
+		{"outstanding-purchase-orders-by-supplier", "outstanding-purchase-orders-by-"},
+		{"Penjualan – ringkasan bulan Oktober 2026", "Penjualan – ringkasan bulan Okt"}, // cut at 31 characters, not bytes
+	}
+	for _, tc := range tests {
+		got := sheetName(tc.in)
+		if got != tc.want {
+			t.Errorf("sheetName(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+		if n := len([]rune(got)); n > 31 {
+			t.Errorf("sheetName(%q) has %d characters, want at most 31", tc.in, n)
+		}
+	}
+}
+
+func TestXLSXEscapesSheetName(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, XLSX, "", "Sales & returns", []string{"id"})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("read workbook: %v", err)
+	}
+	for _, f := range archive.File {
+		if f.Name != "xl/workbook.xml" {
+			continue
+		}
+		r, _ := f.Open()
+		content, _ := io.ReadAll(r)
+		r.Close()
+		if !strings.Contains(string(content), `name="Sales &amp; returns"`) {
+			t.Errorf("workbook.xml = %s, want the sheet name escaped", content)
+		}
+		return
+	}
+	t.Error("workbook has no xl/workbook.xml")
+}
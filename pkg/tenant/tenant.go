@@ -0,0 +1,18 @@
+package tenant
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx that carries the tenant ID
+func WithID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID carried by ctx
+func FromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(contextKey{}).(int)
+	return id, ok && id > 0
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+
+	"go-cashier-api/model"
+)
+
+type BarcodeFormatRepository interface {
+	GetAll(ctx context.Context) ([]model.BarcodeFormat, error)
+	GetByID(ctx context.Context, id int) (*model.BarcodeFormat, error)
+	Create(ctx context.Context, format *model.BarcodeFormat) error
+	Update(ctx context.Context, format *model.BarcodeFormat) (int64, error) // Return rows affected
+	Delete(ctx context.Context, id int) (int64, error)                      // Return rows affected
+}
+
+type BarcodeFormatRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewBarcodeFormatRepository(db *sql.DB) BarcodeFormatRepository {
+	return &BarcodeFormatRepositoryImpl{db: db}
+}
+
+// Query functions
+// GetAll returns the formats with the longest prefix first, the order in
+// which a scanned code is matched against them
+func (repo *BarcodeFormatRepositoryImpl) GetAll(ctx context.Context) ([]model.BarcodeFormat, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, name, prefix, code_length, value_type, value_length, value_decimals
+		FROM barcode_formats
+		ORDER BY LENGTH(prefix) DESC, prefix
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	formats := make([]model.BarcodeFormat, 0)
+	for rows.Next() {
+		var f model.BarcodeFormat
+		err := rows.Scan(&f.ID, &f.Name, &f.Prefix, &f.CodeLength, &f.ValueType, &f.ValueLength, &f.ValueDecimals)
+		if err != nil {
+			return nil, err
+		}
+		formats = append(formats, f)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return formats, nil
+}
+
+func (repo *BarcodeFormatRepositoryImpl) GetByID(ctx context.Context, id int) (*model.BarcodeFormat, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var f model.BarcodeFormat
+	err = tx.QueryRow(`
+		SELECT id, name, prefix, code_length, value_type, value_length, value_decimals
+		FROM barcode_formats
+		WHERE id = $1
+	`, id).Scan(&f.ID, &f.Name, &f.Prefix, &f.CodeLength, &f.ValueType, &f.ValueLength, &f.ValueDecimals)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Command functions
+func (repo *BarcodeFormatRepositoryImpl) Create(ctx context.Context, f *model.BarcodeFormat) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO barcode_formats (name, prefix, code_length, value_type, value_length, value_decimals)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, f.Name, f.Prefix, f.CodeLength, f.ValueType, f.ValueLength, f.ValueDecimals).Scan(&f.ID)
+	if err != nil {
+		return barcodeFormatConstraintError(err)
+	}
+
+	return tx.Commit()
+}
+
+func (repo *BarcodeFormatRepositoryImpl) Update(ctx context.Context, f *model.BarcodeFormat) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE barcode_formats
+		SET name = $1, prefix = $2, code_length = $3, value_type = $4, value_length = $5, value_decimals = $6
+		WHERE id = $7
+	`, f.Name, f.Prefix, f.CodeLength, f.ValueType, f.ValueLength, f.ValueDecimals, f.ID)
+	if err != nil {
+		return 0, barcodeFormatConstraintError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (repo *BarcodeFormatRepositoryImpl) Delete(ctx context.Context, id int) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM barcode_formats WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// barcodeFormatConstraintError turns a duplicate prefix into a readable error
+func barcodeFormatConstraintError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "barcode_formats_prefix_key" {
+		return errors.New("barcode format prefix already exists")
+	}
+	return err
+}
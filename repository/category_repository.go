@@ -1,17 +1,18 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"go-cashier-api/model"
 )
 
 type CategoryRepository interface {
-	GetAll() ([]model.Category, error)
-	GetByID(id int) (*model.Category, error)
-	Create(category *model.Category) error
-	Update(category *model.Category) (int64, error) // Return rows affected
-	Delete(id int) (int64, error)                   // Return rows affected
+	GetAll(ctx context.Context) ([]model.Category, error)
+	GetByID(ctx context.Context, id int) (*model.Category, error)
+	Create(ctx context.Context, category *model.Category) error
+	Update(ctx context.Context, category *model.Category) (int64, error) // Return rows affected
+	Delete(ctx context.Context, id int) (int64, error)                   // Return rows affected
 }
 
 type CategoryRepositoryImpl struct {
@@ -23,9 +24,15 @@ func NewCategoryRepository(db *sql.DB) CategoryRepository {
 }
 
 // Query functions
-func (repo *CategoryRepositoryImpl) GetAll() ([]model.Category, error) {
-	query := "SELECT id, name, description FROM categories"
-	rows, err := repo.db.Query(query)
+func (repo *CategoryRepositoryImpl) GetAll(ctx context.Context) ([]model.Category, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, name, description, station FROM categories"
+	rows, err := tx.Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +41,7 @@ func (repo *CategoryRepositoryImpl) GetAll() ([]model.Category, error) {
 	categories := make([]model.Category, 0)
 	for rows.Next() {
 		var c model.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.Description)
+		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.Station)
 		if err != nil {
 			return nil, err
 		}
@@ -47,11 +54,17 @@ func (repo *CategoryRepositoryImpl) GetAll() ([]model.Category, error) {
 }
 
 // GetCategoryByID returns a category by its ID
-func (repo *CategoryRepositoryImpl) GetByID(id int) (*model.Category, error) {
-	query := "SELECT id, name, description FROM categories WHERE id = $1"
+func (repo *CategoryRepositoryImpl) GetByID(ctx context.Context, id int) (*model.Category, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, name, description, station FROM categories WHERE id = $1"
 
 	var c model.Category
-	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description)
+	err = tx.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description, &c.Station)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -63,27 +76,57 @@ func (repo *CategoryRepositoryImpl) GetByID(id int) (*model.Category, error) {
 }
 
 // Command functions
-func (repo *CategoryRepositoryImpl) Create(c *model.Category) error {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	return repo.db.QueryRow(query, c.Name, c.Description).Scan(&c.ID)
+func (repo *CategoryRepositoryImpl) Create(ctx context.Context, c *model.Category) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO categories (name, description, station) VALUES ($1, $2, $3) RETURNING id"
+	if err := tx.QueryRow(query, c.Name, c.Description, c.Station).Scan(&c.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (repo *CategoryRepositoryImpl) Update(category *model.Category) (int64, error) {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	result, err := repo.db.Exec(query, category.Name, category.Description, category.ID)
+func (repo *CategoryRepositoryImpl) Update(ctx context.Context, category *model.Category) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
 	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
+
+	query := "UPDATE categories SET name = $1, description = $2, station = $3 WHERE id = $4"
+	result, err := tx.Exec(query, category.Name, category.Description, category.Station, category.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
 
 	return result.RowsAffected()
 }
 
-func (repo *CategoryRepositoryImpl) Delete(id int) (int64, error) {
+func (repo *CategoryRepositoryImpl) Delete(ctx context.Context, id int) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
 	query := "DELETE FROM categories WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+	result, err := tx.Exec(query, id)
 	if err != nil {
 		return 0, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return result.RowsAffected()
 }
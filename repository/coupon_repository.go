@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"go-cashier-api/model"
+)
+
+type CouponRepository interface {
+	GetAll(ctx context.Context) ([]model.Coupon, error)
+	GetByID(ctx context.Context, id int) (*model.Coupon, error)
+	Create(ctx context.Context, coupon *model.Coupon) error
+	Update(ctx context.Context, coupon *model.Coupon) (int64, error) // Return rows affected
+	Delete(ctx context.Context, id int) (int64, error)               // Return rows affected
+}
+
+type CouponRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCouponRepository(db *sql.DB) CouponRepository {
+	return &CouponRepositoryImpl{db: db}
+}
+
+const couponColumns = `id, code, description, discount_type, discount_value, min_spend, starts_at, ends_at,
+	usage_limit, per_customer_limit, times_used, created_at`
+
+// Query functions
+func (repo *CouponRepositoryImpl) GetAll(ctx context.Context) ([]model.Coupon, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return getCoupons(tx, "SELECT "+couponColumns+" FROM coupons ORDER BY code")
+}
+
+func (repo *CouponRepositoryImpl) GetByID(ctx context.Context, id int) (*model.Coupon, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	coupons, err := getCoupons(tx, "SELECT "+couponColumns+" FROM coupons WHERE id = $1", id)
+	if err != nil || len(coupons) == 0 {
+		return nil, err
+	}
+	return &coupons[0], nil
+}
+
+// getCoupons runs a query for coupons and attaches their eligible products
+// and categories
+func getCoupons(tx *sql.Tx, query string, args ...interface{}) ([]model.Coupon, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coupons: %w", err)
+	}
+	defer rows.Close()
+
+	coupons := make([]model.Coupon, 0)
+	for rows.Next() {
+		var c model.Coupon
+		err := rows.Scan(&c.ID, &c.Code, &c.Description, &c.DiscountType, &c.DiscountValue, &c.MinSpend,
+			&c.StartsAt, &c.EndsAt, &c.UsageLimit, &c.PerCustomerLimit, &c.TimesUsed, &c.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan coupon: %w", err)
+		}
+		coupons = append(coupons, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range coupons {
+		coupons[i].ProductIDs, err = getCouponIDs(tx, "SELECT product_id FROM coupon_products WHERE coupon_id = $1 ORDER BY product_id", coupons[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		coupons[i].CategoryIDs, err = getCouponIDs(tx, "SELECT category_id FROM coupon_categories WHERE coupon_id = $1 ORDER BY category_id", coupons[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return coupons, nil
+}
+
+func getCouponIDs(tx *sql.Tx, query string, couponID int) ([]int, error) {
+	rows, err := tx.Query(query, couponID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Command functions
+func (repo *CouponRepositoryImpl) Create(ctx context.Context, c *model.Coupon) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO coupons (code, description, discount_type, discount_value, min_spend, starts_at, ends_at,
+			usage_limit, per_customer_limit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, times_used, created_at
+	`, c.Code, c.Description, c.DiscountType, c.DiscountValue, c.MinSpend, c.StartsAt, c.EndsAt,
+		c.UsageLimit, c.PerCustomerLimit).Scan(&c.ID, &c.TimesUsed, &c.CreatedAt)
+	if err != nil {
+		return couponConstraintError(err)
+	}
+
+	if err := insertCouponScope(tx, c); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update replaces the coupon and its eligible products and categories.
+// The usage count is kept.
+func (repo *CouponRepositoryImpl) Update(ctx context.Context, c *model.Coupon) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE coupons SET code = $1, description = $2, discount_type = $3, discount_value = $4, min_spend = $5,
+			starts_at = $6, ends_at = $7, usage_limit = $8, per_customer_limit = $9
+		WHERE id = $10
+	`, c.Code, c.Description, c.DiscountType, c.DiscountValue, c.MinSpend, c.StartsAt, c.EndsAt,
+		c.UsageLimit, c.PerCustomerLimit, c.ID)
+	if err != nil {
+		return 0, couponConstraintError(err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM coupon_products WHERE coupon_id = $1", c.ID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM coupon_categories WHERE coupon_id = $1", c.ID); err != nil {
+		return 0, err
+	}
+	if err := insertCouponScope(tx, c); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Delete removes a coupon that was never redeemed. A redeemed coupon stays
+// for the sales that used it.
+func (repo *CouponRepositoryImpl) Delete(ctx context.Context, id int) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var used bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM coupon_redemptions WHERE coupon_id = $1)", id).Scan(&used)
+	if err != nil {
+		return 0, err
+	}
+	if used {
+		return 0, errors.New("coupon has been redeemed and cannot be deleted")
+	}
+
+	result, err := tx.Exec("DELETE FROM coupons WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func insertCouponScope(tx *sql.Tx, c *model.Coupon) error {
+	for _, productID := range c.ProductIDs {
+		_, err := tx.Exec("INSERT INTO coupon_products (coupon_id, product_id) VALUES ($1, $2)", c.ID, productID)
+		if err != nil {
+			return couponConstraintError(err)
+		}
+	}
+	for _, categoryID := range c.CategoryIDs {
+		_, err := tx.Exec("INSERT INTO coupon_categories (coupon_id, category_id) VALUES ($1, $2)", c.ID, categoryID)
+		if err != nil {
+			return couponConstraintError(err)
+		}
+	}
+	return nil
+}
+
+// couponConstraintError turns a duplicate code or an unknown product or
+// category into a readable error
+func couponConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch {
+	case pqErr.Code == "23505" && pqErr.Constraint == "coupons_code_key":
+		return errors.New("coupon code already exists")
+	case pqErr.Code == "23505":
+		return errors.New("coupon lists the same product or category twice")
+	case pqErr.Code == "23503" && pqErr.Table == "coupon_products":
+		return errors.New("coupon lists an unknown product")
+	case pqErr.Code == "23503" && pqErr.Table == "coupon_categories":
+		return errors.New("coupon lists an unknown category")
+	default:
+		return err
+	}
+}
+
+// redeemCoupon applies a coupon to the priced lines of a sale. The coupon
+// row is locked so the usage limits hold under concurrent checkouts, and
+// the redemption is counted in the same transaction as the sale. It sets
+// the discount of every eligible line and returns the total discount.
+func redeemCoupon(tx *sql.Tx, code, customer string, transactionID int, details []model.TransactionDetail) (int, error) {
+	var c model.Coupon
+	var now time.Time
+	err := tx.QueryRow(`
+		SELECT id, code, discount_type, discount_value, min_spend, starts_at, ends_at,
+			usage_limit, per_customer_limit, times_used, NOW()::timestamp
+		FROM coupons
+		WHERE code = $1
+		FOR UPDATE
+	`, code).Scan(&c.ID, &c.Code, &c.DiscountType, &c.DiscountValue, &c.MinSpend, &c.StartsAt, &c.EndsAt,
+		&c.UsageLimit, &c.PerCustomerLimit, &c.TimesUsed, &now)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("coupon %s does not exist", code)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return 0, fmt.Errorf("coupon %s is not valid until %s", c.Code, c.StartsAt.Format("2006-01-02 15:04"))
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return 0, fmt.Errorf("coupon %s expired on %s", c.Code, c.EndsAt.Format("2006-01-02 15:04"))
+	}
+	if c.UsageLimit > 0 && c.TimesUsed >= c.UsageLimit {
+		return 0, fmt.Errorf("coupon %s has reached its usage limit", c.Code)
+	}
+	if c.PerCustomerLimit > 0 {
+		if customer == "" {
+			return 0, fmt.Errorf("coupon %s is limited per customer, the sale needs a customer", c.Code)
+		}
+		var used int
+		err := tx.QueryRow("SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND customer = $2", c.ID, customer).Scan(&used)
+		if err != nil {
+			return 0, err
+		}
+		if used >= c.PerCustomerLimit {
+			return 0, fmt.Errorf("coupon %s was already used %d times by this customer", c.Code, used)
+		}
+	}
+
+	eligible, err := couponEligibleProducts(tx, c.ID, details)
+	if err != nil {
+		return 0, err
+	}
+	spend := 0
+	for _, detail := range details {
+		if eligible[detail.ProductID] {
+			spend += detail.Subtotal
+		}
+	}
+	if spend == 0 {
+		return 0, fmt.Errorf("coupon %s does not apply to any item in this sale", c.Code)
+	}
+	if spend < c.MinSpend {
+		return 0, fmt.Errorf("coupon %s needs a minimum spend of %d on eligible items, this sale has %d", c.Code, c.MinSpend, spend)
+	}
+
+	discount := c.DiscountValue
+	if c.DiscountType == model.DiscountPercent {
+		discount = (spend*c.DiscountValue + 50) / 100
+	}
+	if discount > spend {
+		discount = spend
+	}
+	allocateDiscount(details, eligible, spend, discount)
+
+	_, err = tx.Exec("UPDATE coupons SET times_used = times_used + 1 WHERE id = $1", c.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count coupon use: %w", err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO coupon_redemptions (coupon_id, transaction_id, customer, discount)
+		VALUES ($1, $2, $3, $4)
+	`, c.ID, transactionID, customer, discount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record coupon redemption: %w", err)
+	}
+
+	return discount, nil
+}
+
+// couponEligibleProducts returns which products of the sale a coupon
+// applies to. A product is eligible when it, its parent or its category is
+// listed, or when the coupon lists neither. Gift cards never are.
+func couponEligibleProducts(tx *sql.Tx, couponID int, details []model.TransactionDetail) (map[int]bool, error) {
+	productIDs := make([]int, len(details))
+	for i, detail := range details {
+		productIDs[i] = detail.ProductID
+	}
+
+	rows, err := tx.Query(`
+		SELECT p.id
+		FROM products p
+		LEFT JOIN products pp ON pp.id = p.parent_id
+		WHERE p.id = ANY($2) AND p.type <> $3
+			AND (
+				NOT EXISTS (SELECT 1 FROM coupon_products WHERE coupon_id = $1)
+					AND NOT EXISTS (SELECT 1 FROM coupon_categories WHERE coupon_id = $1)
+				OR EXISTS (
+					SELECT 1 FROM coupon_products cp
+					WHERE cp.coupon_id = $1 AND cp.product_id IN (p.id, p.parent_id)
+				)
+				OR EXISTS (
+					SELECT 1 FROM coupon_categories cc
+					WHERE cc.coupon_id = $1 AND cc.category_id IN (p.category_id, pp.category_id)
+				)
+			)
+	`, couponID, pq.Array(int64s(productIDs)), model.ProductGiftCard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check coupon eligibility: %w", err)
+	}
+	defer rows.Close()
+
+	eligible := make(map[int]bool)
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			return nil, err
+		}
+		eligible[productID] = true
+	}
+	return eligible, rows.Err()
+}
+
+// allocateDiscount spreads a discount over the eligible lines in proportion
+// to their subtotals. What is left after rounding down goes one by one to
+// the lines with the largest remainders, earlier lines first on a tie, so
+// the line discounts always add up to the discount.
+func allocateDiscount(details []model.TransactionDetail, eligible map[int]bool, spend, discount int) {
+	remainders := make([]int, len(details))
+	left := discount
+	for i := range details {
+		if !eligible[details[i].ProductID] {
+			continue
+		}
+		share := details[i].Subtotal * discount
+		details[i].Discount = share / spend
+		remainders[i] = share % spend
+		left -= details[i].Discount
+	}
+
+	for ; left > 0; left-- {
+		best := -1
+		for i := range details {
+			if eligible[details[i].ProductID] && details[i].Discount < details[i].Subtotal &&
+				(best < 0 || remainders[i] > remainders[best]) {
+				best = i
+			}
+		}
+		details[best].Discount++
+		remainders[best] = -1
+	}
+}
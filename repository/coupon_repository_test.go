@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go-cashier-api/model"
+)
+
+func TestAllocateDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		subtotals []int
+		eligible  []int // product IDs, line i sells product i+1
+		discount  int
+		want      []int
+	}{
+		{
+			name:      "even split, leftover to the first line",
+			subtotals: []int{100, 100, 100}, eligible: []int{1, 2, 3}, discount: 100,
+			want: []int{34, 33, 33},
+		},
+		{
+			name:      "proportional to the subtotals",
+			subtotals: []int{1000, 3000}, eligible: []int{1, 2}, discount: 1000,
+			want: []int{250, 750},
+		},
+		{
+			name:      "lines that aren't eligible get nothing",
+			subtotals: []int{1000, 500, 3000}, eligible: []int{1, 3}, discount: 1000,
+			want: []int{250, 0, 750},
+		},
+		{
+			name:      "leftover to the largest remainders",
+			subtotals: []int{10, 20, 40}, eligible: []int{1, 2, 3}, discount: 10,
+			want: []int{1, 3, 6},
+		},
+		{
+			name:      "whole spend off",
+			subtotals: []int{5, 7}, eligible: []int{1, 2}, discount: 12,
+			want: []int{5, 7},
+		},
+		{
+			name:      "discount smaller than the number of lines",
+			subtotals: []int{1, 1, 1}, eligible: []int{1, 2, 3}, discount: 2,
+			want: []int{1, 1, 0},
+		},
+		{
+			name:      "no discount",
+			subtotals: []int{300, 700}, eligible: []int{1, 2}, discount: 0,
+			want: []int{0, 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			details := make([]model.TransactionDetail, len(tc.subtotals))
+			for i, subtotal := range tc.subtotals {
+				details[i] = model.TransactionDetail{ProductID: i + 1, Subtotal: subtotal}
+			}
+			eligible := make(map[int]bool)
+			spend := 0
+			for _, id := range tc.eligible {
+				eligible[id] = true
+				spend += tc.subtotals[id-1]
+			}
+
+			allocateDiscount(details, eligible, spend, tc.discount)
+
+			got := make([]int, len(details))
+			total := 0
+			for i, d := range details {
+				got[i] = d.Discount
+				total += d.Discount
+				if d.Discount > d.Subtotal {
+					t.Errorf("line %d discount %d is more than its subtotal %d", i, d.Discount, d.Subtotal)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("discounts = %v, want %v", got, tc.want)
+			}
+			if total != tc.discount {
+				t.Errorf("discounts add up to %d, want %d", total, tc.discount)
+			}
+		})
+	}
+}
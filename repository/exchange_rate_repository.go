@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+)
+
+type ExchangeRateRepository interface {
+	GetAll(ctx context.Context, currency string) ([]model.ExchangeRate, error)
+	Create(ctx context.Context, rate *model.ExchangeRate) error
+}
+
+type ExchangeRateRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) ExchangeRateRepository {
+	return &ExchangeRateRepositoryImpl{db: db}
+}
+
+// Query functions
+// GetAll returns the rates of a currency, or of all when currency is
+// empty, latest first
+func (repo *ExchangeRateRepositoryImpl) GetAll(ctx context.Context, currency string) ([]model.ExchangeRate, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, currency, base_currency, rate, effective_from, created_by, created_at
+		FROM exchange_rates
+		WHERE $1 = '' OR currency = $1
+		ORDER BY currency, base_currency, effective_from DESC
+	`, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make([]model.ExchangeRate, 0)
+	for rows.Next() {
+		var r model.ExchangeRate
+		err := rows.Scan(&r.ID, &r.Currency, &r.BaseCurrency, &r.Rate, &r.EffectiveFrom, &r.CreatedBy, &r.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, r)
+	}
+	return rates, rows.Err()
+}
+
+// Command functions
+func (repo *ExchangeRateRepositoryImpl) Create(ctx context.Context, r *model.ExchangeRate) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO exchange_rates (currency, base_currency, rate, effective_from, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, r.Currency, r.BaseCurrency, r.Rate, r.EffectiveFrom, r.CreatedBy).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "exchange_rates_effective_key" {
+			return errors.New("a rate for this currency already exists from that time")
+		}
+		return fmt.Errorf("failed to create exchange rate: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// convertTender returns the base currency value of a foreign tender at the
+// rate in force now, with that rate. Minor units of both currencies are
+// scaled and rounded half away from zero in SQL so no precision is lost.
+// A tender too small to be worth a minor unit of the base currency is
+// refused.
+func convertTender(tx *sql.Tx, tender model.Tender, baseCurrency string) (int, decimal.Rate, error) {
+	foreignPlaces, ok := model.MinorUnits(tender.Currency)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown currency %q", tender.Currency)
+	}
+	basePlaces, ok := model.MinorUnits(baseCurrency)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown currency %q", baseCurrency)
+	}
+
+	var amount int
+	var rate decimal.Rate
+	err := tx.QueryRow(`
+		SELECT ROUND($1::numeric * rate * POWER(10::numeric, $4::int) / POWER(10::numeric, $5::int))::int, rate
+		FROM exchange_rates
+		WHERE currency = $2 AND base_currency = $3 AND effective_from <= NOW()
+		ORDER BY effective_from DESC
+		LIMIT 1
+	`, tender.Amount, tender.Currency, baseCurrency, basePlaces, foreignPlaces).Scan(&amount, &rate)
+	if err == sql.ErrNoRows {
+		return 0, 0, fmt.Errorf("no exchange rate from %s to %s is in force", tender.Currency, baseCurrency)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to convert %s tender: %w", tender.Currency, err)
+	}
+	if amount <= 0 {
+		return 0, 0, fmt.Errorf("%s tender is worth less than the smallest unit of %s", tender.Currency, baseCurrency)
+	}
+	return amount, rate, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-cashier-api/model"
+)
+
+type GiftCardRepository interface {
+	GetAll(ctx context.Context) ([]model.GiftCard, error)
+	GetByCode(ctx context.Context, code string) (*model.GiftCard, error)
+	Create(ctx context.Context, card *model.GiftCard) error
+}
+
+type GiftCardRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewGiftCardRepository(db *sql.DB) GiftCardRepository {
+	return &GiftCardRepositoryImpl{db: db}
+}
+
+const giftCardColumns = `id, code, initial_balance, balance, expires_at, transaction_detail_id, issued_by, created_at`
+
+func scanGiftCard(scan func(dest ...interface{}) error, card *model.GiftCard) error {
+	return scan(&card.ID, &card.Code, &card.InitialBalance, &card.Balance, &card.ExpiresAt, &card.SoldIn,
+		&card.IssuedBy, &card.CreatedAt)
+}
+
+// Query functions
+func (repo *GiftCardRepositoryImpl) GetAll(ctx context.Context) ([]model.GiftCard, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT " + giftCardColumns + " FROM gift_cards ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gift cards: %w", err)
+	}
+	defer rows.Close()
+
+	cards := make([]model.GiftCard, 0)
+	for rows.Next() {
+		var card model.GiftCard
+		if err := scanGiftCard(rows.Scan, &card); err != nil {
+			return nil, fmt.Errorf("failed to scan gift card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+	return cards, rows.Err()
+}
+
+// GetByCode returns a gift card with its balance ledger
+func (repo *GiftCardRepositoryImpl) GetByCode(ctx context.Context, code string) (*model.GiftCard, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var card model.GiftCard
+	err = scanGiftCard(tx.QueryRow("SELECT "+giftCardColumns+" FROM gift_cards WHERE code = $1", code).Scan, &card)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, type, amount, balance_after, transaction_id, payment_id, created_at
+		FROM gift_card_entries
+		WHERE gift_card_id = $1
+		ORDER BY id
+	`, card.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gift card entries: %w", err)
+	}
+	defer rows.Close()
+
+	card.Entries = make([]model.GiftCardEntry, 0)
+	for rows.Next() {
+		var entry model.GiftCardEntry
+		err := rows.Scan(&entry.ID, &entry.Type, &entry.Amount, &entry.BalanceAfter, &entry.TransactionID,
+			&entry.PaymentID, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan gift card entry: %w", err)
+		}
+		card.Entries = append(card.Entries, entry)
+	}
+	return &card, rows.Err()
+}
+
+// Command functions
+// Create issues a store-credit voucher outside of a sale
+func (repo *GiftCardRepositoryImpl) Create(ctx context.Context, card *model.GiftCard) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := issueGiftCard(tx, card, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// giftCardAlphabet leaves out characters that are easily misread, such as
+// 0 and O or 1 and I
+const giftCardAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// newGiftCardCode returns a random 16 character code
+func newGiftCardCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = giftCardAlphabet[int(b[i])%len(giftCardAlphabet)]
+	}
+	return string(b), nil
+}
+
+// issueGiftCard creates a gift card with a new code and books its balance
+// in the ledger. transactionID is set for cards sold through checkout.
+func issueGiftCard(tx *sql.Tx, card *model.GiftCard, transactionID *int) error {
+	code, err := newGiftCardCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate gift card code: %w", err)
+	}
+	card.Code = code
+	card.Balance = card.InitialBalance
+	if card.ValidityDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, card.ValidityDays)
+		card.ExpiresAt = &expiresAt
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO gift_cards (code, initial_balance, balance, expires_at, transaction_detail_id, issued_by)
+		VALUES ($1, $2, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, card.Code, card.InitialBalance, card.ExpiresAt, card.SoldIn, card.IssuedBy).Scan(&card.ID, &card.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to issue gift card: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO gift_card_entries (gift_card_id, type, amount, balance_after, transaction_id)
+		VALUES ($1, $2, $3, $3, $4)
+	`, card.ID, model.GiftCardIssue, card.InitialBalance, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to record gift card issue: %w", err)
+	}
+	return nil
+}
+
+// redeemGiftCard takes an amount off the balance of a gift card for a
+// payment. The card row is locked first, so two tills redeeming the same
+// card are serialized and the second sees the balance the first left.
+func redeemGiftCard(tx *sql.Tx, code string, amount, transactionID, paymentID int) error {
+	var cardID, balance int
+	var expiresAt *time.Time
+	err := tx.QueryRow("SELECT id, balance, expires_at FROM gift_cards WHERE code = $1 FOR UPDATE", code).
+		Scan(&cardID, &balance, &expiresAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("gift card %s not found", code)
+	}
+	if err != nil {
+		return err
+	}
+	if expiresAt != nil && !time.Now().Before(*expiresAt) {
+		return fmt.Errorf("gift card %s expired on %s", code, expiresAt.Format("2006-01-02"))
+	}
+	if balance < amount {
+		return fmt.Errorf("gift card %s has a balance of %d, less than %d", code, balance, amount)
+	}
+
+	balance -= amount
+	if _, err := tx.Exec("UPDATE gift_cards SET balance = $1 WHERE id = $2", balance, cardID); err != nil {
+		return fmt.Errorf("failed to redeem gift card: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO gift_card_entries (gift_card_id, type, amount, balance_after, transaction_id, payment_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, cardID, model.GiftCardRedeem, -amount, balance, transactionID, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to record gift card redemption: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"go-cashier-api/model"
+)
+
+type KitchenRepository interface {
+	GetQueue(ctx context.Context, station, status string) ([]model.KitchenItem, error)
+	GetByID(ctx context.Context, id int) (*model.KitchenItem, error)
+	UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error)
+}
+
+type KitchenRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewKitchenRepository(db *sql.DB) KitchenRepository {
+	return &KitchenRepositoryImpl{db: db}
+}
+
+const kitchenItemColumns = `id, station, status, order_id, transaction_id, table_name, product_name, quantity, unit,
+	COALESCE(pack_name, ''), modifiers, notes, created_at, updated_at`
+
+func scanKitchenItem(scan func(dest ...interface{}) error, item *model.KitchenItem) error {
+	return scan(&item.ID, &item.Station, &item.Status, &item.OrderID, &item.TransactionID, &item.TableName,
+		&item.ProductName, &item.Quantity, &item.Unit, &item.Pack, pq.Array(&item.Modifiers), &item.Notes,
+		&item.CreatedAt, &item.UpdatedAt)
+}
+
+func scanKitchenItems(rows *sql.Rows) ([]model.KitchenItem, error) {
+	defer rows.Close()
+
+	items := make([]model.KitchenItem, 0)
+	for rows.Next() {
+		var item model.KitchenItem
+		if err := scanKitchenItem(rows.Scan, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan kitchen item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Query functions
+// GetQueue returns the items of a station oldest first, by default those
+// not yet served
+func (repo *KitchenRepositoryImpl) GetQueue(ctx context.Context, station, status string) ([]model.KitchenItem, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT `+kitchenItemColumns+`
+		FROM kitchen_items
+		WHERE station = $1 AND (status = $2 OR $2 = '' AND status <> $3)
+		ORDER BY created_at, id
+	`, station, status, model.KitchenServed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kitchen queue: %w", err)
+	}
+	return scanKitchenItems(rows)
+}
+
+func (repo *KitchenRepositoryImpl) GetByID(ctx context.Context, id int) (*model.KitchenItem, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var item model.KitchenItem
+	err = scanKitchenItem(tx.QueryRow("SELECT "+kitchenItemColumns+" FROM kitchen_items WHERE id = $1", id).Scan, &item)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Command functions
+// UpdateStatus moves an item from one status to another. It only succeeds
+// when the item is still in fromStatus.
+func (repo *KitchenRepositoryImpl) UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE kitchen_items SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`, toStatus, id, fromStatus)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// sendOrderLines routes the lines of an order that were not sent yet to
+// the station of their category and marks them sent. transactionID is set
+// when the lines are sent at checkout.
+func sendOrderLines(tx *sql.Tx, orderID int, transactionID *int) ([]model.KitchenItem, error) {
+	rows, err := tx.Query(`
+		INSERT INTO kitchen_items (station, order_id, transaction_id, table_name, product_name, quantity, unit,
+			pack_name, modifiers, notes)
+		SELECT c.station, o.id, $2, o.table_name, p.name, ol.quantity, p.unit, ol.pack_name,
+			ARRAY(SELECT m.name FROM modifiers m WHERE m.id = ANY(ol.modifier_ids) ORDER BY m.name), ol.notes
+		FROM order_lines ol
+		JOIN orders o ON o.id = ol.order_id
+		JOIN products p ON p.id = ol.product_id
+		JOIN categories c ON c.id = p.category_id
+		WHERE ol.order_id = $1 AND ol.sent_at IS NULL AND c.station <> ''
+		ORDER BY ol.id
+		RETURNING `+kitchenItemColumns, orderID, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route order lines: %w", err)
+	}
+	items, err := scanKitchenItems(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("UPDATE order_lines SET sent_at = NOW() WHERE order_id = $1 AND sent_at IS NULL", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark order lines sent: %w", err)
+	}
+	return items, nil
+}
+
+// routeTransaction routes the lines of a sale that did not come from an
+// order to the station of their category
+func routeTransaction(tx *sql.Tx, transactionID int) ([]model.KitchenItem, error) {
+	rows, err := tx.Query(`
+		INSERT INTO kitchen_items (station, transaction_id, product_name, quantity, unit, pack_name, modifiers)
+		SELECT c.station, td.transaction_id, p.name, COALESCE(td.pack_quantity, td.quantity), p.unit, td.pack_name,
+			ARRAY(SELECT tdm.name FROM transaction_detail_modifiers tdm WHERE tdm.transaction_detail_id = td.id ORDER BY tdm.name)
+		FROM transaction_details td
+		JOIN products p ON p.id = td.product_id
+		JOIN categories c ON c.id = p.category_id
+		WHERE td.transaction_id = $1 AND c.station <> ''
+		ORDER BY td.id
+		RETURNING `+kitchenItemColumns, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route sale lines: %w", err)
+	}
+	return scanKitchenItems(rows)
+}
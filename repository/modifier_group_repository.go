@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"go-cashier-api/model"
+)
+
+type ModifierGroupRepository interface {
+	GetAll(ctx context.Context) ([]model.ModifierGroup, error)
+	GetByID(ctx context.Context, id int) (*model.ModifierGroup, error)
+	GetByProduct(ctx context.Context, productID int) ([]model.ModifierGroup, error)
+	Create(ctx context.Context, group *model.ModifierGroup) error
+	Update(ctx context.Context, group *model.ModifierGroup) (int64, error) // Return rows affected
+	Delete(ctx context.Context, id int) (int64, error)                     // Return rows affected
+}
+
+type ModifierGroupRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewModifierGroupRepository(db *sql.DB) ModifierGroupRepository {
+	return &ModifierGroupRepositoryImpl{db: db}
+}
+
+// Query functions
+func (repo *ModifierGroupRepositoryImpl) GetAll(ctx context.Context) ([]model.ModifierGroup, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return getModifierGroups(tx, "SELECT id, name, min_select, max_select FROM modifier_groups ORDER BY name")
+}
+
+func (repo *ModifierGroupRepositoryImpl) GetByID(ctx context.Context, id int) (*model.ModifierGroup, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	groups, err := getModifierGroups(tx, "SELECT id, name, min_select, max_select FROM modifier_groups WHERE id = $1", id)
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// GetByProduct returns the modifier groups of a product in the order they
+// were attached
+func (repo *ModifierGroupRepositoryImpl) GetByProduct(ctx context.Context, productID int) ([]model.ModifierGroup, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return getProductModifierGroups(tx, productID)
+}
+
+func getProductModifierGroups(tx *sql.Tx, productID int) ([]model.ModifierGroup, error) {
+	return getModifierGroups(tx, `
+		SELECT mg.id, mg.name, mg.min_select, mg.max_select
+		FROM product_modifier_groups pmg
+		JOIN modifier_groups mg ON mg.id = pmg.group_id
+		WHERE pmg.product_id = $1
+		ORDER BY pmg.position, mg.id
+	`, productID)
+}
+
+// getModifierGroups runs a query for groups and attaches their modifiers
+func getModifierGroups(tx *sql.Tx, query string, args ...interface{}) ([]model.ModifierGroup, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modifier groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make([]model.ModifierGroup, 0)
+	for rows.Next() {
+		var g model.ModifierGroup
+		if err := rows.Scan(&g.ID, &g.Name, &g.MinSelect, &g.MaxSelect); err != nil {
+			return nil, fmt.Errorf("failed to scan modifier group: %w", err)
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range groups {
+		groups[i].Modifiers, err = getModifiers(tx, groups[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+func getModifiers(tx *sql.Tx, groupID int) ([]model.Modifier, error) {
+	rows, err := tx.Query("SELECT id, name, price_delta FROM modifiers WHERE group_id = $1 ORDER BY id", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modifiers: %w", err)
+	}
+	defer rows.Close()
+
+	modifiers := make([]model.Modifier, 0)
+	for rows.Next() {
+		var m model.Modifier
+		if err := rows.Scan(&m.ID, &m.Name, &m.PriceDelta); err != nil {
+			return nil, fmt.Errorf("failed to scan modifier: %w", err)
+		}
+		modifiers = append(modifiers, m)
+	}
+	return modifiers, rows.Err()
+}
+
+// Command functions
+func (repo *ModifierGroupRepositoryImpl) Create(ctx context.Context, g *model.ModifierGroup) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO modifier_groups (name, min_select, max_select)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, g.Name, g.MinSelect, g.MaxSelect).Scan(&g.ID)
+	if err != nil {
+		return modifierGroupConstraintError(err)
+	}
+
+	for i := range g.Modifiers {
+		if err := insertModifier(tx, g.ID, &g.Modifiers[i]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Update replaces the group and its modifiers. Modifiers sent with an ID
+// are updated in place so their IDs stay valid for tills that cached them,
+// modifiers left out are removed.
+func (repo *ModifierGroupRepositoryImpl) Update(ctx context.Context, g *model.ModifierGroup) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE modifier_groups SET name = $1, min_select = $2, max_select = $3
+		WHERE id = $4
+	`, g.Name, g.MinSelect, g.MaxSelect, g.ID)
+	if err != nil {
+		return 0, modifierGroupConstraintError(err)
+	}
+
+	kept := make([]int64, 0, len(g.Modifiers))
+	for _, m := range g.Modifiers {
+		if m.ID != 0 {
+			kept = append(kept, int64(m.ID))
+		}
+	}
+	_, err = tx.Exec("DELETE FROM modifiers WHERE group_id = $1 AND NOT (id = ANY($2))", g.ID, pq.Array(kept))
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range g.Modifiers {
+		m := &g.Modifiers[i]
+		if m.ID == 0 {
+			if err := insertModifier(tx, g.ID, m); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		updated, err := tx.Exec(`
+			UPDATE modifiers SET name = $1, price_delta = $2
+			WHERE id = $3 AND group_id = $4
+		`, m.Name, m.PriceDelta, m.ID, g.ID)
+		if err != nil {
+			return 0, modifierGroupConstraintError(err)
+		}
+		if rowsAffected, _ := updated.RowsAffected(); rowsAffected == 0 {
+			return 0, fmt.Errorf("modifier id %d is not part of this group", m.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (repo *ModifierGroupRepositoryImpl) Delete(ctx context.Context, id int) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM modifier_groups WHERE id = $1", id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func insertModifier(tx *sql.Tx, groupID int, m *model.Modifier) error {
+	err := tx.QueryRow(`
+		INSERT INTO modifiers (group_id, name, price_delta)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, groupID, m.Name, m.PriceDelta).Scan(&m.ID)
+	if err != nil {
+		return modifierGroupConstraintError(err)
+	}
+	return nil
+}
+
+// modifierGroupConstraintError turns a duplicate group or modifier name into
+// a readable error
+func modifierGroupConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "23505" {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "modifier_groups_name_key":
+		return errors.New("modifier group name already exists")
+	case "modifiers_group_id_name_key":
+		return errors.New("modifier name already exists in this group")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,658 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"go-cashier-api/model"
+)
+
+type OrderRepository interface {
+	GetAll(ctx context.Context, status string, storeID int) ([]model.Order, error)
+	GetByID(ctx context.Context, id int) (*model.Order, error)
+	Create(ctx context.Context, order *model.Order) error
+	AddLine(ctx context.Context, orderID int, line *model.OrderLine) error
+	UpdateLine(ctx context.Context, orderID int, line *model.OrderLine) error
+	DeleteLine(ctx context.Context, orderID, lineID int) error
+	SplitLines(ctx context.Context, orderID int, lineIDs []int, createdBy string) (int, error)
+	PayShare(ctx context.Context, orderID, number int, tenders []model.Tender, rounding map[string]model.RoundingRule) error
+	Send(ctx context.Context, orderID int) ([]model.KitchenItem, error)
+	UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error)
+}
+
+type OrderRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) OrderRepository {
+	return &OrderRepositoryImpl{db: db}
+}
+
+const orderColumns = `id, store_id, status, table_name, notes, created_by, created_at, updated_at, transaction_id, parent_id`
+
+func scanOrder(scan func(dest ...interface{}) error, o *model.Order) error {
+	return scan(&o.ID, &o.StoreID, &o.Status, &o.TableName, &o.Notes, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt,
+		&o.TransactionID, &o.ParentID)
+}
+
+// Query functions
+func (repo *OrderRepositoryImpl) GetAll(ctx context.Context, status string, storeID int) ([]model.Order, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT `+orderColumns+`
+		FROM orders
+		WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR store_id = $2)
+		ORDER BY updated_at DESC
+	`, status, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]model.Order, 0)
+	for rows.Next() {
+		var o model.Order
+		if err := scanOrder(rows.Scan, &o); err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		if err := loadOrderChildren(tx, &orders[i]); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+// GetByID returns an order with its lines priced at the current prices
+func (repo *OrderRepositoryImpl) GetByID(ctx context.Context, id int) (*model.Order, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var o model.Order
+	err = scanOrder(tx.QueryRow("SELECT "+orderColumns+" FROM orders WHERE id = $1", id).Scan, &o)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadOrderChildren(tx, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// loadOrderChildren loads the lines of an order, the orders split off it
+// and its shares with their payments
+func loadOrderChildren(tx *sql.Tx, o *model.Order) error {
+	if err := loadOrderLines(tx, o); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT id FROM orders WHERE parent_id = $1 ORDER BY id", o.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get split orders: %w", err)
+	}
+	o.ChildIDs = make([]int, 0)
+	for rows.Next() {
+		var childID int
+		if err := rows.Scan(&childID); err != nil {
+			rows.Close()
+			return err
+		}
+		o.ChildIDs = append(o.ChildIDs, childID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = tx.Query("SELECT id, number, amount, paid_at FROM order_shares WHERE order_id = $1 ORDER BY number", o.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order shares: %w", err)
+	}
+	o.Shares = make([]model.OrderShare, 0)
+	for rows.Next() {
+		var share model.OrderShare
+		if err := rows.Scan(&share.ID, &share.Number, &share.Amount, &share.PaidAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan order share: %w", err)
+		}
+		o.Shares = append(o.Shares, share)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range o.Shares {
+		o.Shares[i].Payments, _, err = getPayments(tx, *o.TransactionID, &o.Shares[i].ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadOrderLines reads the lines of an order and prices each the way
+// checkout would. A line that no longer prices, e.g. because its pack was
+// removed, is an error.
+func loadOrderLines(tx *sql.Tx, o *model.Order) error {
+	rows, err := tx.Query(`
+		SELECT ol.id, ol.product_id, p.name, COALESCE(p.price, pp.price, 0), p.unit,
+			COALESCE(ol.pack_name, ''), ol.quantity, ol.amount, ol.modifier_ids, ol.notes, ol.sent_at, ol.created_at
+		FROM order_lines ol
+		JOIN products p ON p.id = ol.product_id
+		LEFT JOIN products pp ON pp.id = p.parent_id
+		WHERE ol.order_id = $1
+		ORDER BY ol.id
+	`, o.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order lines: %w", err)
+	}
+
+	lines := make([]model.OrderLine, 0)
+	prices := make([]int, 0)
+	for rows.Next() {
+		var line model.OrderLine
+		var price int
+		var modifierIDs []int64
+		err := rows.Scan(&line.ID, &line.ProductID, &line.ProductName, &price, &line.Unit,
+			&line.Pack, &line.Quantity, &line.Amount, pq.Array(&modifierIDs), &line.Notes, &line.SentAt, &line.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan order line: %w", err)
+		}
+		line.ModifierIDs = make([]int, len(modifierIDs))
+		for i, id := range modifierIDs {
+			line.ModifierIDs[i] = int(id)
+		}
+		lines = append(lines, line)
+		prices = append(prices, price)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	o.Total = 0
+	for i := range lines {
+		line := &lines[i]
+		line.Modifiers, err = getOrderLineModifiers(tx, line.ModifierIDs)
+		if err != nil {
+			return err
+		}
+
+		_, _, line.Subtotal, err = priceLine(tx, orderLineItem(*line), line.ProductName, prices[i])
+		if err != nil {
+			return fmt.Errorf("order line %d: %w", line.ID, err)
+		}
+		o.Total += line.Subtotal
+	}
+	o.Lines = lines
+	return nil
+}
+
+// getOrderLineModifiers looks up the modifiers picked for a line at their
+// current price. A modifier removed from the menu since is an error, the
+// line has to be changed before the order can be paid.
+func getOrderLineModifiers(tx *sql.Tx, modifierIDs []int) ([]model.TransactionDetailModifier, error) {
+	modifiers := make([]model.TransactionDetailModifier, 0, len(modifierIDs))
+	if len(modifierIDs) == 0 {
+		return modifiers, nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT m.id, g.name, m.name, m.price_delta
+		FROM modifiers m
+		JOIN modifier_groups g ON g.id = m.group_id
+		WHERE m.id = ANY($1)
+		ORDER BY g.name, m.name
+	`, pq.Array(int64s(modifierIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order line modifiers: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[int]bool, len(modifierIDs))
+	for rows.Next() {
+		var m model.TransactionDetailModifier
+		if err := rows.Scan(&m.ModifierID, &m.GroupName, &m.Name, &m.PriceDelta); err != nil {
+			return nil, err
+		}
+		found[m.ModifierID] = true
+		modifiers = append(modifiers, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range modifierIDs {
+		if !found[id] {
+			return nil, fmt.Errorf("modifier id %d no longer exists", id)
+		}
+	}
+	return modifiers, nil
+}
+
+func int64s(ids []int) []int64 {
+	out := make([]int64, len(ids))
+	for i, id := range ids {
+		out[i] = int64(id)
+	}
+	return out
+}
+
+// orderLineItem returns the checkout item an order line stands for
+func orderLineItem(line model.OrderLine) model.CheckoutItem {
+	return model.CheckoutItem{
+		ProductID:   line.ProductID,
+		Pack:        line.Pack,
+		Quantity:    line.Quantity,
+		Amount:      line.Amount,
+		ModifierIDs: line.ModifierIDs,
+		Modifiers:   line.Modifiers,
+	}
+}
+
+// orderItems reads the lines of a locked order as checkout items, so a
+// sale sells the lines the order holds when it is paid. The modifiers of
+// each line are checked again against its product's modifier groups, which
+// may have changed since the line was added.
+func orderItems(tx *sql.Tx, orderID int) ([]model.CheckoutItem, error) {
+	order := model.Order{ID: orderID}
+	if err := loadOrderLines(tx, &order); err != nil {
+		return nil, err
+	}
+	if len(order.Lines) == 0 {
+		return nil, fmt.Errorf("order has no lines")
+	}
+
+	items := make([]model.CheckoutItem, 0, len(order.Lines))
+	for _, line := range order.Lines {
+		groups, err := getProductModifierGroups(tx, line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		item := orderLineItem(line)
+		item.Modifiers, err = model.SelectModifiers(line.ProductName, groups, line.ModifierIDs)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Command functions
+func (repo *OrderRepositoryImpl) Create(ctx context.Context, o *model.Order) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	o.StoreID, err = resolveStoreID(tx, o.StoreID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO orders (store_id, status, table_name, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, o.StoreID, model.OrderOpen, o.TableName, o.Notes, o.CreatedBy).Scan(&o.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// lockOpenOrder locks an order whose lines are about to change. Lines can
+// only change while the order is open, a held cart is recalled first.
+func lockOpenOrder(tx *sql.Tx, orderID int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("order not found")
+	}
+	if err != nil {
+		return err
+	}
+	if status != model.OrderOpen {
+		return fmt.Errorf("order with status %s cannot be changed", status)
+	}
+
+	_, err = tx.Exec("UPDATE orders SET updated_at = NOW() WHERE id = $1", orderID)
+	return err
+}
+
+func (repo *OrderRepositoryImpl) AddLine(ctx context.Context, orderID int, line *model.OrderLine) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOrder(tx, orderID); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO order_lines (order_id, product_id, pack_name, quantity, amount, modifier_ids, notes)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+		RETURNING id
+	`, orderID, line.ProductID, line.Pack, line.Quantity, line.Amount, pq.Array(int64s(line.ModifierIDs)), line.Notes).Scan(&line.ID)
+	if err != nil {
+		return fmt.Errorf("failed to add order line: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// UpdateLine replaces the quantity, modifiers and notes of a line
+func (repo *OrderRepositoryImpl) UpdateLine(ctx context.Context, orderID int, line *model.OrderLine) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOrder(tx, orderID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE order_lines SET quantity = $1, modifier_ids = $2, notes = $3
+		WHERE id = $4 AND order_id = $5
+	`, line.Quantity, pq.Array(int64s(line.ModifierIDs)), line.Notes, line.ID, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to update order line: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("order line not found")
+	}
+
+	return tx.Commit()
+}
+
+func (repo *OrderRepositoryImpl) DeleteLine(ctx context.Context, orderID, lineID int) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOrder(tx, orderID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM order_lines WHERE id = $1 AND order_id = $2", lineID, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to remove order line: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("order line not found")
+	}
+
+	return tx.Commit()
+}
+
+// SplitLines moves lines of an open order into a new order for the same
+// table and returns its ID. An order left without lines only waits for the
+// orders split off it to be paid.
+func (repo *OrderRepositoryImpl) SplitLines(ctx context.Context, orderID int, lineIDs []int, createdBy string) (int, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOrder(tx, orderID); err != nil {
+		return 0, err
+	}
+
+	var childID int
+	err = tx.QueryRow(`
+		INSERT INTO orders (store_id, status, table_name, created_by, parent_id)
+		SELECT store_id, $2, table_name, $3, id FROM orders WHERE id = $1
+		RETURNING id
+	`, orderID, model.OrderOpen, createdBy).Scan(&childID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create split order: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		UPDATE order_lines SET order_id = $1
+		WHERE order_id = $2 AND id = ANY($3)
+	`, childID, orderID, pq.Array(int64s(lineIDs)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to move order lines: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected != int64(len(lineIDs)) {
+		return 0, fmt.Errorf("order line not found")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE orders SET status = $1
+		WHERE id = $2 AND NOT EXISTS (SELECT 1 FROM order_lines WHERE order_id = $2)
+	`, model.OrderSplit, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to split order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return childID, nil
+}
+
+// Send routes the lines of an open or held order that were not sent yet to
+// their preparation stations
+func (repo *OrderRepositoryImpl) Send(ctx context.Context, orderID int) ([]model.KitchenItem, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != model.OrderOpen && status != model.OrderHeld {
+		return nil, fmt.Errorf("order with status %s cannot be sent", status)
+	}
+
+	items, err := sendOrderLines(tx, orderID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// PayShare pays one share of an order split into equal shares
+func (repo *OrderRepositoryImpl) PayShare(ctx context.Context, orderID, number int, tenders []model.Tender,
+	rounding map[string]model.RoundingRule) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var transactionID *int
+	err = tx.QueryRow("SELECT status, transaction_id FROM orders WHERE id = $1 FOR UPDATE", orderID).
+		Scan(&status, &transactionID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("order not found")
+	}
+	if err != nil {
+		return err
+	}
+	if status != model.OrderSplit || transactionID == nil {
+		return fmt.Errorf("order with status %s cannot be paid by share", status)
+	}
+
+	var shareID, amount int
+	var paid bool
+	err = tx.QueryRow(`
+		SELECT id, amount, paid_at IS NOT NULL FROM order_shares
+		WHERE order_id = $1 AND number = $2
+		FOR UPDATE
+	`, orderID, number).Scan(&shareID, &amount, &paid)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("order share not found")
+	}
+	if err != nil {
+		return err
+	}
+	if paid {
+		return fmt.Errorf("share %d cannot be paid twice", number)
+	}
+
+	if _, _, _, err := insertPayments(tx, *transactionID, &shareID, amount, tenders, rounding); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE order_shares SET paid_at = NOW() WHERE id = $1", shareID); err != nil {
+		return fmt.Errorf("failed to pay share: %w", err)
+	}
+
+	if err := settleOrder(tx, orderID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// closeOrder records the sale of an order's lines. Split into shares the
+// order waits for them to be paid; the remainder of the division goes one
+// each to the first shares so the shares always add up to the total.
+func closeOrder(tx *sql.Tx, orderID, transactionID, total, shares int) error {
+	_, err := tx.Exec(`
+		UPDATE orders SET status = $1, transaction_id = $2, updated_at = NOW()
+		WHERE id = $3
+	`, model.OrderSplit, transactionID, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to close order: %w", err)
+	}
+
+	for number := 1; number <= shares; number++ {
+		amount := total / shares
+		if number <= total%shares {
+			amount++
+		}
+		_, err := tx.Exec("INSERT INTO order_shares (order_id, number, amount) VALUES ($1, $2, $3)", orderID, number, amount)
+		if err != nil {
+			return fmt.Errorf("failed to create order share: %w", err)
+		}
+	}
+
+	return settleOrder(tx, orderID)
+}
+
+// settleOrder marks a split order paid once all its shares are paid and
+// the orders split off it are paid or cancelled, and then settles its own
+// parent the same way
+func settleOrder(tx *sql.Tx, orderID int) error {
+	var status string
+	var parentID *int
+	err := tx.QueryRow("SELECT status, parent_id FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&status, &parentID)
+	if err != nil {
+		return err
+	}
+	if status != model.OrderSplit {
+		return nil
+	}
+
+	var pending bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM order_shares WHERE order_id = $1 AND paid_at IS NULL)
+			OR EXISTS (SELECT 1 FROM orders WHERE parent_id = $1 AND status NOT IN ($2, $3))
+	`, orderID, model.OrderPaid, model.OrderCancelled).Scan(&pending)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return nil
+	}
+
+	_, err = tx.Exec("UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2", model.OrderPaid, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to settle order: %w", err)
+	}
+
+	if parentID != nil {
+		return settleOrder(tx, *parentID)
+	}
+	return nil
+}
+
+// UpdateStatus moves an order from one status to another. It only succeeds
+// when the order is still in fromStatus.
+func (repo *OrderRepositoryImpl) UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE orders SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`, toStatus, id, fromStatus)
+	if err != nil {
+		return 0, err
+	}
+
+	// A cancelled split order may be the last one its parent waits for
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rowsAffected > 0 && toStatus == model.OrderCancelled {
+		var parentID *int
+		if err := tx.QueryRow("SELECT parent_id FROM orders WHERE id = $1", id).Scan(&parentID); err != nil {
+			return 0, err
+		}
+		if parentID != nil {
+			if err := settleOrder(tx, *parentID); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+)
+
+// insertPayments records the tenders that pay an amount due. Only cash may
+// be tendered above it; the change comes off the last cash tenders so each
+// payment's amount is what it actually paid.
+// Foreign cash is converted to the base currency of the sale first, the
+// change is given in base currency.
+// The last tender settles the sale, so the balance it pays is rounded by
+// the rule of its type. It returns the payments, the change and the
+// rounding adjustment added to the amount due.
+func insertPayments(tx *sql.Tx, transactionID int, shareID *int, due int, tenders []model.Tender,
+	rounding map[string]model.RoundingRule) ([]model.Payment, int, int, error) {
+	if len(tenders) == 0 {
+		return nil, 0, 0, nil
+	}
+
+	var baseCurrency string
+	err := tx.QueryRow("SELECT currency FROM transactions WHERE id = $1", transactionID).Scan(&baseCurrency)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	payments := make([]model.Payment, len(tenders))
+	for i, tender := range tenders {
+		payments[i] = model.Payment{
+			TransactionID: transactionID,
+			ShareID:       shareID,
+			Type:          tender.Type,
+			Amount:        tender.Amount,
+			Tendered:      tender.Amount,
+			Currency:      baseCurrency,
+			Reference:     tender.Reference,
+		}
+		if tender.Currency == "" || tender.Currency == baseCurrency {
+			continue
+		}
+		if tender.Type != model.TenderCash {
+			return nil, 0, 0, fmt.Errorf("only cash can be tendered in %s, the sale is in %s", tender.Currency, baseCurrency)
+		}
+
+		amount, rate, err := convertTender(tx, tender, baseCurrency)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		foreign := tender.Amount
+		payments[i].Amount, payments[i].Tendered = amount, amount
+		payments[i].Currency, payments[i].ForeignTendered, payments[i].Rate = tender.Currency, &foreign, &rate
+	}
+
+	adjustment := 0
+	if rule, ok := rounding[tenders[len(tenders)-1].Type]; ok {
+		balance := due
+		for _, payment := range payments[:len(payments)-1] {
+			balance -= payment.Amount
+		}
+		if balance > 0 {
+			adjustment = rule.Round(balance) - balance
+			due += adjustment
+		}
+	}
+
+	tendered, nonCash := 0, 0
+	for _, payment := range payments {
+		tendered += payment.Amount
+		if payment.Type != model.TenderCash {
+			nonCash += payment.Amount
+		}
+	}
+	if tendered < due {
+		return nil, 0, 0, fmt.Errorf("tenders of %d do not cover the amount due of %d", tendered, due)
+	}
+	if nonCash > due {
+		return nil, 0, 0, fmt.Errorf("non-cash tenders of %d exceed the amount due of %d", nonCash, due)
+	}
+
+	change := tendered - due
+	for i := len(payments) - 1; i >= 0 && change > 0; i-- {
+		if payments[i].Type != model.TenderCash {
+			continue
+		}
+		given := min(change, payments[i].Amount)
+		payments[i].Amount -= given
+		change -= given
+	}
+
+	for i := range payments {
+		p := &payments[i]
+		err = tx.QueryRow(`
+			INSERT INTO payments (transaction_id, share_id, type, amount, tendered, currency, foreign_tendered, rate, reference)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id, created_at
+		`, p.TransactionID, p.ShareID, p.Type, p.Amount, p.Tendered, p.Currency, p.ForeignTendered, p.Rate,
+			p.Reference).Scan(&p.ID, &p.CreatedAt)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to record payment: %w", err)
+		}
+
+		if p.Type == model.TenderGiftCard {
+			if err := redeemGiftCard(tx, p.Reference, p.Amount, transactionID, p.ID); err != nil {
+				return nil, 0, 0, err
+			}
+		}
+	}
+
+	if adjustment != 0 {
+		_, err := tx.Exec("UPDATE transactions SET rounding_amount = rounding_amount + $1 WHERE id = $2", adjustment, transactionID)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to record rounding: %w", err)
+		}
+	}
+	return payments, tendered - due, adjustment, nil
+}
+
+// getPayments returns the payments of a sale, or of one share of it when
+// shareID is set, with the change they gave
+func getPayments(tx *sql.Tx, transactionID int, shareID *int) ([]model.Payment, int, error) {
+	rows, err := tx.Query(`
+		SELECT id, transaction_id, share_id, type, amount, tendered, currency, foreign_tendered, rate, reference, created_at
+		FROM payments
+		WHERE transaction_id = $1 AND ($2::int IS NULL OR share_id = $2)
+		ORDER BY id
+	`, transactionID, shareID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := make([]model.Payment, 0)
+	change := 0
+	for rows.Next() {
+		var p model.Payment
+		err := rows.Scan(&p.ID, &p.TransactionID, &p.ShareID, &p.Type, &p.Amount, &p.Tendered, &p.Currency,
+			&p.ForeignTendered, &p.Rate, &p.Reference, &p.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		change += p.Tendered - p.Amount
+		payments = append(payments, p)
+	}
+	return payments, change, rows.Err()
+}
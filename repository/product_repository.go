@@ -1,17 +1,33 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 
 	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
 )
 
 type ProductRepository interface {
-	GetAll(nameFilter string) ([]model.Product, error)
-	GetByID(id int) (*model.Product, error)
-	Create(product *model.Product) error
-	Update(product *model.Product) (int64, error)
-	Delete(id int) (int64, error)
+	GetAll(ctx context.Context, nameFilter string) ([]model.Product, error)
+	GetByID(ctx context.Context, id int) (*model.Product, error)
+	GetByBarcode(ctx context.Context, barcode string) (*model.Product, error)
+	GetBySKU(ctx context.Context, sku string) (*model.Product, error)
+	GetByPLU(ctx context.Context, plu int) (*model.Product, error)
+	IsComponent(ctx context.Context, id int) (bool, error)
+	Create(ctx context.Context, product *model.Product) error
+	Update(ctx context.Context, product *model.Product) (int64, error)
+	Delete(ctx context.Context, id int) (int64, error)
+	GetLowStock(ctx context.Context, storeID int) ([]model.LowStockProduct, error)
+	GetStoreStock(ctx context.Context, storeID int) (map[int]decimal.Decimal, error)
+	GetSalesSince(ctx context.Context, since time.Time, storeID int) (map[int]decimal.Decimal, error)
+	GetBundleSales(ctx context.Context, startDate, endDate time.Time, storeID int) ([]model.BundleSalesRow, error)
 }
 
 // implementation of repository pattern for product entity
@@ -25,47 +41,113 @@ func NewProductRepository(db *sql.DB) ProductRepository {
 	return &ProductRepositoryImpl{db: db}
 }
 
+// productBarcodes selects the barcodes of product p as an array
+const productBarcodes = "ARRAY(SELECT pb.barcode FROM product_barcodes pb WHERE pb.product_id = p.id ORDER BY pb.id)"
+
+// productColumns selects product p, scanned by scanProduct. A variant
+// without a price of its own takes the price of its parent pp.
+const productColumns = "p.id, p.name, p.type, p.validity_days, COALESCE(p.sku, ''), " + productBarcodes + ", COALESCE(p.plu, 0), COALESCE(p.price, pp.price), p.price IS NULL, p.unit, p.stock, p.min_stock, p.reorder_qty, p.parent_id, p.variant_attributes, p.attributes"
+
+// productTables joins the parent of product p for productColumns
+const productTables = "products p LEFT JOIN products pp ON pp.id = p.parent_id"
+
 // Query functions
-// GetAllProducts returns all products
-func (repo *ProductRepositoryImpl) GetAll(nameFilter string) ([]model.Product, error) {
+// GetAllProducts returns all products, variants nested under their parent.
+// A name filter also matches parents through the name of a variant.
+func (repo *ProductRepositoryImpl) GetAll(ctx context.Context, nameFilter string) ([]model.Product, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	// query all products from database
-	query := "SELECT id, name, price, stock FROM products"
+	query := "SELECT " + productColumns + " FROM " + productTables + " WHERE p.parent_id IS NULL AND p.archived_at IS NULL"
 	args := []interface{}{}
 	if nameFilter != "" {
-		query += " WHERE name ILIKE $1"
+		query += " AND (p.name ILIKE $1 OR EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id AND v.archived_at IS NULL AND v.name ILIKE $1))"
 		args = append(args, "%"+nameFilter+"%")
 	}
+	query += " ORDER BY p.id"
 
-	rows, err := repo.db.Query(query, args...)
+	rows, err := tx.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// iterate over rows and scan into products slice
-	products := make([]model.Product, 0)
-	for rows.Next() {
-		var p model.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock)
-		if err != nil {
+	products, err := scanProducts(rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range products {
+		if err := loadProductChildren(tx, &products[i]); err != nil {
 			return nil, err
 		}
-		products = append(products, p)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
 	}
 	return products, nil
 }
 
 // GetProductByID returns a product by its ID
-func (repo *ProductRepositoryImpl) GetByID(id int) (*model.Product, error) {
-	// query product by ID from database
-	query := "SELECT p.id, p.name, p.price, p.stock, p.category_id, c.name AS category_name FROM products p JOIN categories c ON p.category_id = c.id WHERE p.id = $1"
+func (repo *ProductRepositoryImpl) GetByID(ctx context.Context, id int) (*model.Product, error) {
+	return repo.getOne(ctx, "p.id = $1", id)
+}
+
+// GetByBarcode returns the product with the given normalized barcode,
+// either its own or one of its packs'
+func (repo *ProductRepositoryImpl) GetByBarcode(ctx context.Context, barcode string) (*model.Product, error) {
+	return repo.getOne(ctx, `p.id = (
+		SELECT product_id FROM product_barcodes WHERE barcode = $1
+		UNION SELECT product_id FROM product_packs WHERE barcode = $1)`, barcode)
+}
+
+// GetBySKU returns the product with the given SKU
+func (repo *ProductRepositoryImpl) GetBySKU(ctx context.Context, sku string) (*model.Product, error) {
+	return repo.getOne(ctx, "p.sku = $1", sku)
+}
+
+// GetByPLU returns the product with the given in-store item code
+func (repo *ProductRepositoryImpl) GetByPLU(ctx context.Context, plu int) (*model.Product, error) {
+	return repo.getOne(ctx, "p.plu = $1", plu)
+}
+
+// IsComponent reports whether a product is a component of another product
+func (repo *ProductRepositoryImpl) IsComponent(ctx context.Context, id int) (bool, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var used bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM product_components pc
+			JOIN products p ON p.id = pc.product_id
+			WHERE pc.component_id = $1 AND p.archived_at IS NULL
+		)
+	`, id).Scan(&used)
+	if err != nil {
+		return false, fmt.Errorf("failed to check product components: %w", err)
+	}
+	return used, nil
+}
+
+func (repo *ProductRepositoryImpl) getOne(ctx context.Context, condition string, arg interface{}) (*model.Product, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// query product from database
+	query := "SELECT " + productColumns + ", p.category_id, c.name AS category_name FROM " + productTables + " JOIN categories c ON p.category_id = c.id WHERE p.archived_at IS NULL AND " + condition
 
 	// scan result into p
 	var p model.Product
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.Category.Name)
+	err = scanProduct(tx.QueryRow(query, arg).Scan, &p, &p.CategoryID, &p.Category.Name)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -73,37 +155,672 @@ func (repo *ProductRepositoryImpl) GetByID(id int) (*model.Product, error) {
 		return nil, err
 	}
 
+	if err := loadProductChildren(tx, &p); err != nil {
+		return nil, err
+	}
+
 	return &p, nil
 }
 
+// scanProduct scans the productColumns of a row, followed by extra columns
+func scanProduct(scan func(dest ...interface{}) error, p *model.Product, extra ...interface{}) error {
+	var attributes []byte
+	dest := []interface{}{&p.ID, &p.Name, &p.Type, &p.ValidityDays, &p.SKU, pq.Array(&p.Barcodes), &p.PLU, &p.Price, &p.InheritsPrice,
+		&p.Unit, &p.Stock, &p.MinStock, &p.ReorderQty, &p.ParentID, pq.Array(&p.VariantAttributes), &attributes}
+	if err := scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	// Only variants have attribute values and inherit a price
+	if p.ParentID == nil {
+		p.InheritsPrice = false
+		return nil
+	}
+	return json.Unmarshal(attributes, &p.Attributes)
+}
+
+func scanProducts(rows *sql.Rows) ([]model.Product, error) {
+	products := make([]model.Product, 0)
+	for rows.Next() {
+		var p model.Product
+		if err := scanProduct(rows.Scan, &p); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+// loadProductChildren attaches the packs and components of a product and,
+// for a parent, its variants
+func loadProductChildren(tx *sql.Tx, p *model.Product) error {
+	var err error
+	p.Packs, err = getProductPacks(tx, p.ID)
+	if err != nil {
+		return err
+	}
+	if err := loadProductComponents(tx, p); err != nil {
+		return err
+	}
+	if err := loadProductModifierGroups(tx, p); err != nil {
+		return err
+	}
+	if p.ParentID != nil {
+		return nil
+	}
+
+	rows, err := tx.Query("SELECT "+productColumns+" FROM "+productTables+" WHERE p.parent_id = $1 AND p.archived_at IS NULL ORDER BY p.id", p.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get product variants: %w", err)
+	}
+	defer rows.Close()
+
+	p.Variants, err = scanProducts(rows)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	for i := range p.Variants {
+		p.Variants[i].Packs, err = getProductPacks(tx, p.Variants[i].ID)
+		if err != nil {
+			return err
+		}
+		if err := loadProductComponents(tx, &p.Variants[i]); err != nil {
+			return err
+		}
+		if err := loadProductModifierGroups(tx, &p.Variants[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadProductModifierGroups(tx *sql.Tx, p *model.Product) error {
+	groups, err := getProductModifierGroups(tx, p.ID)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		p.ModifierGroupIDs = append(p.ModifierGroupIDs, group.ID)
+	}
+	p.ModifierGroups = groups
+	return nil
+}
+
+// compositeStoreStock selects the whole units of composite product $1 the
+// component stock of store s allows, NULL for a product without components.
+// A bundle is put together in one store, so each store counts on its own.
+const compositeStoreStock = `(
+	SELECT GREATEST(MIN(FLOOR(COALESCE(cs.stock, 0) / pc.quantity)), 0)
+	FROM product_components pc
+	LEFT JOIN product_stocks cs ON cs.product_id = pc.component_id AND cs.store_id = s.id
+	WHERE pc.product_id = $1)`
+
+// loadProductComponents attaches the components of a composite product and
+// sets its stock to the units they allow, added up over the stores
+func loadProductComponents(tx *sql.Tx, p *model.Product) error {
+	components, err := getProductComponents(tx, p.ID)
+	if err != nil || len(components) == 0 {
+		return err
+	}
+	p.Components = components
+
+	err = tx.QueryRow("SELECT COALESCE(SUM("+compositeStoreStock+"), 0) FROM stores s", p.ID).Scan(&p.Stock)
+	if err != nil {
+		return fmt.Errorf("failed to get composite product stock: %w", err)
+	}
+	return nil
+}
+
 // Command functions
 // CreateProduct adds a new product to the store
-func (repo *ProductRepositoryImpl) Create(p *model.Product) error {
-	// insert new product into database
-	query := "INSERT INTO products (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := repo.db.QueryRow(query, p.Name, p.Price, p.Stock, p.CategoryID).Scan(&p.ID)
-	return err
+func (repo *ProductRepositoryImpl) Create(ctx context.Context, p *model.Product) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// insert new product into database, stock starts at zero and the
+	// initial quantity is booked through the ledger below
+	attributes, err := variantAttributes(p)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO products (name, sku, plu, price, unit, stock, min_stock, reorder_qty, category_id, parent_id, variant_attributes, attributes,
+		type, validity_days)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, 0), $4, $5, 0, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+	err = tx.QueryRow(query, p.Name, p.SKU, p.PLU, productPrice(p), p.Unit, p.MinStock, p.ReorderQty, p.CategoryID,
+		p.ParentID, pq.Array(p.VariantAttributes), attributes, p.Type, p.ValidityDays).Scan(&p.ID)
+	if err != nil {
+		return productConstraintError(err)
+	}
+
+	if err := insertProductBarcodes(tx, p.ID, p.Barcodes); err != nil {
+		return err
+	}
+	if err := insertProductPacks(tx, p.ID, p.Packs); err != nil {
+		return err
+	}
+	if err := insertProductComponents(tx, p.ID, p.Components); err != nil {
+		return err
+	}
+	if err := insertProductModifierGroups(tx, p.ID, p.ModifierGroupIDs); err != nil {
+		return err
+	}
+
+	if p.Stock != 0 {
+		err := recordStockMovement(tx, &model.StockMovement{
+			ProductID: p.ID,
+			Type:      model.MovementAdjustment,
+			Quantity:  p.Stock,
+			Reason:    "opening balance",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-// UpdateProduct updates an existing product by its ID
-func (repo *ProductRepositoryImpl) Update(product *model.Product) (int64, error) {
-	query := "UPDATE products SET name = $1, price = $2, stock = $3, category_id = $4 WHERE id = $5"
-	result, err := repo.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, product.ID)
+// UpdateProduct updates an existing product by its ID. Stock is left
+// alone; it only changes through movements such as stock adjustments, which
+// keep the per-store balances and the ledger in step.
+func (repo *ProductRepositoryImpl) Update(ctx context.Context, product *model.Product) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	attributes, err := variantAttributes(product)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `UPDATE products SET name = $1, sku = NULLIF($2, ''), plu = NULLIF($3, 0), price = $4, unit = $5, min_stock = $6, reorder_qty = $7, category_id = $8,
+		variant_attributes = $9, attributes = $10, validity_days = $11 WHERE id = $12 AND archived_at IS NULL`
+	result, err := tx.Exec(query, product.Name, product.SKU, product.PLU, productPrice(product), product.Unit, product.MinStock, product.ReorderQty, product.CategoryID,
+		pq.Array(product.VariantAttributes), attributes, product.ValidityDays, product.ID)
 	if err != nil {
+		return 0, productConstraintError(err)
+	}
+
+	// Variants follow the category of their parent
+	_, err = tx.Exec("UPDATE products SET category_id = $1 WHERE parent_id = $2", product.CategoryID, product.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// The barcodes and packs sent replace the existing ones
+	if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id = $1", product.ID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM product_packs WHERE product_id = $1", product.ID); err != nil {
+		return 0, err
+	}
+	if err := insertProductBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return 0, err
+	}
+	if err := insertProductPacks(tx, product.ID, product.Packs); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM product_components WHERE product_id = $1", product.ID); err != nil {
+		return 0, err
+	}
+	if err := insertProductComponents(tx, product.ID, product.Components); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM product_modifier_groups WHERE product_id = $1", product.ID); err != nil {
+		return 0, err
+	}
+	if err := insertProductModifierGroups(tx, product.ID, product.ModifierGroupIDs); err != nil {
 		return 0, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return result.RowsAffected()
 
 }
 
 // DeleteProduct removes a product by its ID
-func (repo *ProductRepositoryImpl) Delete(id int) (int64, error) {
-	query := "DELETE FROM products WHERE id = $1"
-	result, err := repo.db.Exec(query, id)
+func (repo *ProductRepositoryImpl) Delete(ctx context.Context, id int) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
 	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
+
+	// A product something still refers to, such as its stock ledger or a
+	// sale, is archived instead: it keeps its history but gives up its SKU,
+	// PLU and barcodes so a new product can take them
+	if _, err := tx.Exec("SAVEPOINT delete_product"); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("DELETE FROM products WHERE id = $1 AND archived_at IS NULL", id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		if _, err := tx.Exec("ROLLBACK TO SAVEPOINT delete_product"); err != nil {
+			return 0, err
+		}
+		result, err = archiveProduct(tx, id)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return result.RowsAffected()
 
 }
+
+// archiveProduct archives a product with its variants
+func archiveProduct(tx *sql.Tx, id int) (sql.Result, error) {
+	family := "SELECT id FROM products WHERE id = $1 OR parent_id = $1"
+	if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id IN ("+family+")", id); err != nil {
+		return nil, fmt.Errorf("failed to delete product barcodes: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM product_packs WHERE product_id IN ("+family+")", id); err != nil {
+		return nil, fmt.Errorf("failed to delete product packs: %w", err)
+	}
+	_, err := tx.Exec(`
+		UPDATE products SET archived_at = NOW(), sku = NULL, plu = NULL
+		WHERE parent_id = $1 AND archived_at IS NULL
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive product variants: %w", err)
+	}
+	result, err := tx.Exec("UPDATE products SET archived_at = NOW(), sku = NULL, plu = NULL WHERE id = $1 AND archived_at IS NULL", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive product: %w", err)
+	}
+	return result, nil
+}
+
+// GetLowStock returns the products whose stock in a store is at or below
+// their reorder point, in every store when storeID is 0
+func (repo *ProductRepositoryImpl) GetLowStock(ctx context.Context, storeID int) ([]model.LowStockProduct, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT p.id, p.name, s.id, s.name, ps.stock, p.min_stock, p.reorder_qty
+		FROM product_stocks ps
+		JOIN products p ON p.id = ps.product_id
+		JOIN stores s ON s.id = ps.store_id
+		WHERE p.min_stock > 0 AND ps.stock <= p.min_stock AND p.archived_at IS NULL AND ($1 = 0 OR ps.store_id = $1)
+		ORDER BY ps.stock - p.min_stock, p.name, s.id
+	`, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]model.LowStockProduct, 0)
+	for rows.Next() {
+		var p model.LowStockProduct
+		if err := rows.Scan(&p.ProductID, &p.Name, &p.StoreID, &p.StoreName, &p.Stock, &p.MinStock, &p.ReorderQty); err != nil {
+			return nil, fmt.Errorf("failed to scan low stock product: %w", err)
+		}
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetStoreStock returns the stock per product ID in a store
+func (repo *ProductRepositoryImpl) GetStoreStock(ctx context.Context, storeID int) (map[int]decimal.Decimal, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT product_id, stock FROM product_stocks WHERE store_id = $1", storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store stock: %w", err)
+	}
+	defer rows.Close()
+
+	stock := make(map[int]decimal.Decimal)
+	for rows.Next() {
+		var productID int
+		var quantity decimal.Decimal
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan store stock: %w", err)
+		}
+		stock[productID] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// GetSalesSince returns the quantity sold per product ID since the given
+// time, including what composite products took from their components, in
+// one store or in every store when storeID is 0
+func (repo *ProductRepositoryImpl) GetSalesSince(ctx context.Context, since time.Time, storeID int) (map[int]decimal.Decimal, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT sold.product_id, COALESCE(SUM(sold.quantity), 0)
+		FROM (
+			SELECT td.product_id, td.quantity
+			FROM transaction_details td
+			JOIN transactions t ON t.id = td.transaction_id
+			WHERE t.created_at >= session_timestamp($1) AND ($2 = 0 OR t.store_id = $2)
+			UNION ALL
+			SELECT tdc.product_id, tdc.quantity
+			FROM transaction_detail_components tdc
+			JOIN transaction_details td ON td.id = tdc.transaction_detail_id
+			JOIN transactions t ON t.id = td.transaction_id
+			WHERE t.created_at >= session_timestamp($1) AND ($2 = 0 OR t.store_id = $2)
+		) sold
+		GROUP BY sold.product_id
+	`, since, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales: %w", err)
+	}
+	defer rows.Close()
+
+	sales := make(map[int]decimal.Decimal)
+	for rows.Next() {
+		var productID int
+		var quantity decimal.Decimal
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan sales: %w", err)
+		}
+		sales[productID] = quantity
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// GetBundleSales returns the composite products sold in a date range with
+// the quantity taken from each component. A storeID of 0 covers all stores.
+func (repo *ProductRepositoryImpl) GetBundleSales(ctx context.Context, startDate, endDate time.Time, storeID int) ([]model.BundleSalesRow, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT p.id, p.name, SUM(td.quantity), SUM(td.subtotal)
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		JOIN products p ON p.id = td.product_id
+		WHERE t.created_at >= session_timestamp($1) AND t.created_at < session_timestamp($2) AND ($3 = 0 OR t.store_id = $3)
+			AND EXISTS (SELECT 1 FROM transaction_detail_components tdc WHERE tdc.transaction_detail_id = td.id)
+		GROUP BY p.id, p.name
+		ORDER BY SUM(td.subtotal) DESC, p.name
+	`, startDate, endDate, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle sales: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]model.BundleSalesRow, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		row := model.BundleSalesRow{Components: make([]model.ProductComponent, 0)}
+		if err := rows.Scan(&row.ProductID, &row.Name, &row.QuantitySold, &row.Revenue); err != nil {
+			return nil, fmt.Errorf("failed to scan bundle sales row: %w", err)
+		}
+		index[row.ProductID] = len(report)
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	componentRows, err := tx.Query(`
+		SELECT td.product_id, c.id, c.name, c.unit, SUM(tdc.quantity)
+		FROM transaction_detail_components tdc
+		JOIN transaction_details td ON td.id = tdc.transaction_detail_id
+		JOIN transactions t ON t.id = td.transaction_id
+		JOIN products c ON c.id = tdc.product_id
+		WHERE t.created_at >= session_timestamp($1) AND t.created_at < session_timestamp($2) AND ($3 = 0 OR t.store_id = $3)
+		GROUP BY td.product_id, c.id, c.name, c.unit
+		ORDER BY c.name
+	`, startDate, endDate, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get component consumption: %w", err)
+	}
+	defer componentRows.Close()
+
+	for componentRows.Next() {
+		var bundleID int
+		var component model.ProductComponent
+		err := componentRows.Scan(&bundleID, &component.ProductID, &component.Name, &component.Unit, &component.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan component consumption: %w", err)
+		}
+		if i, ok := index[bundleID]; ok {
+			report[i].Components = append(report[i].Components, component)
+		}
+	}
+	if err := componentRows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func insertProductBarcodes(tx *sql.Tx, productID int, barcodes []string) error {
+	for _, barcode := range barcodes {
+		if err := checkBarcodeFree(tx, "product_packs", barcode); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec("INSERT INTO product_barcodes (product_id, barcode) VALUES ($1, $2)", productID, barcode)
+		if err != nil {
+			return productConstraintError(err)
+		}
+	}
+	return nil
+}
+
+// productPrice is the price column of a product, NULL for a variant that
+// sells at the parent's price
+func productPrice(p *model.Product) interface{} {
+	if p.ParentID != nil && p.InheritsPrice {
+		return nil
+	}
+	return p.Price
+}
+
+// variantAttributes encodes the attribute values of a variant as JSON
+func variantAttributes(p *model.Product) (string, error) {
+	if p.Attributes == nil {
+		return "{}", nil
+	}
+	attributes, err := json.Marshal(p.Attributes)
+	if err != nil {
+		return "", err
+	}
+	return string(attributes), nil
+}
+
+// getProductPacks returns the packs of a product
+func getProductPacks(tx *sql.Tx, productID int) ([]model.ProductPack, error) {
+	rows, err := tx.Query(`
+		SELECT id, name, factor, price, COALESCE(barcode, '')
+		FROM product_packs
+		WHERE product_id = $1
+		ORDER BY factor, id
+	`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product packs: %w", err)
+	}
+	defer rows.Close()
+
+	packs := make([]model.ProductPack, 0)
+	for rows.Next() {
+		var pack model.ProductPack
+		if err := rows.Scan(&pack.ID, &pack.Name, &pack.Factor, &pack.Price, &pack.Barcode); err != nil {
+			return nil, fmt.Errorf("failed to scan product pack: %w", err)
+		}
+		packs = append(packs, pack)
+	}
+	return packs, rows.Err()
+}
+
+// getProductPack returns the named pack of a product
+func getProductPack(tx *sql.Tx, productID int, name string) (*model.ProductPack, error) {
+	var pack model.ProductPack
+	err := tx.QueryRow(`
+		SELECT id, name, factor, price, COALESCE(barcode, '')
+		FROM product_packs
+		WHERE product_id = $1 AND name = $2
+	`, productID, name).Scan(&pack.ID, &pack.Name, &pack.Factor, &pack.Price, &pack.Barcode)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product id %d has no pack %s", productID, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+func insertProductPacks(tx *sql.Tx, productID int, packs []model.ProductPack) error {
+	for i := range packs {
+		pack := &packs[i]
+		if pack.Barcode != "" {
+			if err := checkBarcodeFree(tx, "product_barcodes", pack.Barcode); err != nil {
+				return err
+			}
+		}
+
+		err := tx.QueryRow(`
+			INSERT INTO product_packs (product_id, name, factor, price, barcode)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+			RETURNING id
+		`, productID, pack.Name, pack.Factor, pack.Price, pack.Barcode).Scan(&pack.ID)
+		if err != nil {
+			return productConstraintError(err)
+		}
+	}
+	return nil
+}
+
+// getProductComponents returns the components of a product and the
+// quantity of each in one unit of it
+func getProductComponents(tx *sql.Tx, productID int) ([]model.ProductComponent, error) {
+	rows, err := tx.Query(`
+		SELECT pc.component_id, c.name, pc.quantity, c.unit
+		FROM product_components pc
+		JOIN products c ON c.id = pc.component_id
+		WHERE pc.product_id = $1
+		ORDER BY pc.id
+	`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product components: %w", err)
+	}
+	defer rows.Close()
+
+	var components []model.ProductComponent
+	for rows.Next() {
+		var component model.ProductComponent
+		if err := rows.Scan(&component.ProductID, &component.Name, &component.Quantity, &component.Unit); err != nil {
+			return nil, fmt.Errorf("failed to scan product component: %w", err)
+		}
+		components = append(components, component)
+	}
+	return components, rows.Err()
+}
+
+func insertProductComponents(tx *sql.Tx, productID int, components []model.ProductComponent) error {
+	for _, component := range components {
+		_, err := tx.Exec(`
+			INSERT INTO product_components (product_id, component_id, quantity)
+			VALUES ($1, $2, $3)
+		`, productID, component.ProductID, component.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to add product component: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertProductModifierGroups(tx *sql.Tx, productID int, groupIDs []int) error {
+	for position, groupID := range groupIDs {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM modifier_groups WHERE id = $1)", groupID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("modifier group id %d not found", groupID)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO product_modifier_groups (product_id, group_id, position)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (product_id, group_id) DO NOTHING
+		`, productID, groupID, position)
+		if err != nil {
+			return fmt.Errorf("failed to attach modifier group: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkBarcodeFree rejects a barcode already used in the other barcode
+// table; product and pack barcodes share one namespace for scanning
+func checkBarcodeFree(tx *sql.Tx, table, barcode string) error {
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE barcode = $1)", barcode).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("barcode already exists")
+	}
+	return nil
+}
+
+// productConstraintError turns a duplicate SKU, PLU or barcode into a readable
+// error and passes other errors through
+func productConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "23505" {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "products_sku_key":
+		return errors.New("sku already exists")
+	case "products_plu_key":
+		return errors.New("plu already exists")
+	case "product_barcodes_barcode_key", "product_packs_barcode_key":
+		return errors.New("barcode already exists")
+	case "product_packs_product_id_name_key":
+		return errors.New("pack name already exists")
+	case "products_variant_key":
+		return errors.New("variant with these attributes already exists")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+)
+
+type PurchaseOrderRepository interface {
+	GetAll(ctx context.Context, status string, supplierID int) ([]model.PurchaseOrder, error)
+	GetByID(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	Create(ctx context.Context, order *model.PurchaseOrder) error
+	Update(ctx context.Context, order *model.PurchaseOrder) (int64, error)
+	UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error)
+	Receive(ctx context.Context, receipt *model.GoodsReceipt) (*model.PurchaseOrder, error)
+	GetOutstanding(ctx context.Context) ([]model.OutstandingPurchaseOrders, error)
+}
+
+type PurchaseOrderRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) PurchaseOrderRepository {
+	return &PurchaseOrderRepositoryImpl{db: db}
+}
+
+// Query functions
+// GetAll returns purchase orders, optionally filtered by status and supplier
+func (repo *PurchaseOrderRepositoryImpl) GetAll(ctx context.Context, status string, supplierID int) ([]model.PurchaseOrder, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT po.id, po.supplier_id, s.name, po.store_id, po.status, po.notes, po.created_at, po.sent_at, po.closed_at
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		WHERE ($1 = '' OR po.status = $1) AND ($2 = 0 OR po.supplier_id = $2)
+		ORDER BY po.created_at DESC
+	`
+	rows, err := tx.Query(query, status, supplierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]model.PurchaseOrder, 0)
+	for rows.Next() {
+		var po model.PurchaseOrder
+		err := rows.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.StoreID, &po.Status, &po.Notes,
+			&po.CreatedAt, &po.SentAt, &po.ClosedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order: %w", err)
+		}
+		orders = append(orders, po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// load items for each purchase order
+	for i := range orders {
+		orders[i].Items, err = getPurchaseOrderItems(tx, orders[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+// GetByID returns a purchase order with its items
+func (repo *PurchaseOrderRepositoryImpl) GetByID(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var po model.PurchaseOrder
+	err = tx.QueryRow(`
+		SELECT po.id, po.supplier_id, s.name, po.store_id, po.status, po.notes, po.created_at, po.sent_at, po.closed_at
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		WHERE po.id = $1
+	`, id).Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.StoreID, &po.Status, &po.Notes,
+		&po.CreatedAt, &po.SentAt, &po.ClosedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	po.Items, err = getPurchaseOrderItems(tx, po.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+// GetOutstanding summarises quantities still to be delivered per supplier
+func (repo *PurchaseOrderRepositoryImpl) GetOutstanding(ctx context.Context) ([]model.OutstandingPurchaseOrders, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT
+			s.id,
+			s.name,
+			COUNT(DISTINCT po.id) AS open_orders,
+			COALESCE(SUM(GREATEST(poi.quantity_ordered - poi.quantity_received, 0)), 0) AS outstanding_quantity,
+			COALESCE(ROUND(SUM(GREATEST(poi.quantity_ordered - poi.quantity_received, 0) * poi.unit_cost)), 0) AS outstanding_value
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		JOIN purchase_order_items poi ON poi.purchase_order_id = po.id
+		WHERE po.status IN ($1, $2)
+		GROUP BY s.id, s.name
+		ORDER BY outstanding_value DESC
+	`, model.PurchaseOrderSent, model.PurchaseOrderPartiallyReceived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outstanding purchase orders: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]model.OutstandingPurchaseOrders, 0)
+	for rows.Next() {
+		var row model.OutstandingPurchaseOrders
+		err := rows.Scan(&row.SupplierID, &row.SupplierName, &row.OpenOrders,
+			&row.OutstandingQuantity, &row.OutstandingValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outstanding purchase orders: %w", err)
+		}
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func getPurchaseOrderItems(tx *sql.Tx, orderID int) ([]model.PurchaseOrderItem, error) {
+	rows, err := tx.Query(`
+		SELECT poi.id, poi.purchase_order_id, poi.product_id, p.name,
+			poi.quantity_ordered, poi.quantity_received, poi.unit_cost
+		FROM purchase_order_items poi
+		JOIN products p ON p.id = poi.product_id
+		WHERE poi.purchase_order_id = $1
+		ORDER BY poi.id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]model.PurchaseOrderItem, 0)
+	for rows.Next() {
+		var item model.PurchaseOrderItem
+		err := rows.Scan(&item.ID, &item.PurchaseOrderID, &item.ProductID, &item.ProductName,
+			&item.QuantityOrdered, &item.QuantityReceived, &item.UnitCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Command functions
+// Create inserts a draft purchase order together with its items
+func (repo *PurchaseOrderRepositoryImpl) Create(ctx context.Context, order *model.PurchaseOrder) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	order.StoreID, err = resolveStoreID(tx, order.StoreID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO purchase_orders (supplier_id, store_id, status, notes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, order.SupplierID, order.StoreID, model.PurchaseOrderDraft, order.Notes).Scan(&order.ID, &order.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create purchase order: %w", err)
+	}
+	order.Status = model.PurchaseOrderDraft
+
+	if err := insertPurchaseOrderItems(tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Update replaces supplier, notes and items of a purchase order that is
+// still a draft
+func (repo *PurchaseOrderRepositoryImpl) Update(ctx context.Context, order *model.PurchaseOrder) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE purchase_orders SET supplier_id = $1, store_id = $2, notes = $3
+		WHERE id = $4 AND status = $5
+	`, order.SupplierID, order.StoreID, order.Notes, order.ID, model.PurchaseOrderDraft)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return rowsAffected, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM purchase_order_items WHERE purchase_order_id = $1", order.ID); err != nil {
+		return 0, err
+	}
+	if err := insertPurchaseOrderItems(tx, order); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return rowsAffected, nil
+}
+
+// UpdateStatus moves a purchase order from one status to another. It only
+// succeeds when the order is still in fromStatus.
+func (repo *PurchaseOrderRepositoryImpl) UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE purchase_orders
+		SET status = $1,
+			sent_at = CASE WHEN $1 = 'sent' THEN NOW() ELSE sent_at END,
+			closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE closed_at END
+		WHERE id = $2 AND status = $3
+	`, toStatus, id, fromStatus)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Receive books a delivery against a purchase order: it records the goods
+// receipt, increases stock through the ledger and moves the order to
+// partially_received or received, all in one transaction
+func (repo *PurchaseOrderRepositoryImpl) Receive(ctx context.Context, receipt *model.GoodsReceipt) (*model.PurchaseOrder, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the order so two deliveries can't be booked at the same time
+	var status string
+	var storeID int
+	err = tx.QueryRow("SELECT status, store_id FROM purchase_orders WHERE id = $1 FOR UPDATE", receipt.PurchaseOrderID).Scan(&status, &storeID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("purchase order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != model.PurchaseOrderSent && status != model.PurchaseOrderPartiallyReceived {
+		return nil, fmt.Errorf("purchase order with status %s cannot be received", status)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO goods_receipts (purchase_order_id, notes, created_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, receipt.PurchaseOrderID, receipt.Notes, receipt.CreatedBy).Scan(&receipt.ID, &receipt.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goods receipt: %w", err)
+	}
+
+	for i, line := range receipt.Lines {
+		var itemID, orderedCost int
+		err := tx.QueryRow(`
+			SELECT id, unit_cost FROM purchase_order_items
+			WHERE purchase_order_id = $1 AND product_id = $2
+		`, receipt.PurchaseOrderID, line.ProductID).Scan(&itemID, &orderedCost)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("product id %d is not on this purchase order", line.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Packs received are booked in the product's unit at the cost of one unit
+		quantity, unitCost := line.Quantity, line.UnitCost
+		var packQuantity *decimal.Decimal
+		if line.Pack != "" {
+			pack, err := getProductPack(tx, line.ProductID, line.Pack)
+			if err != nil {
+				return nil, err
+			}
+			if !line.Quantity.IsInteger() {
+				return nil, fmt.Errorf("quantity %s must be a whole number of %s", line.Quantity, pack.Name)
+			}
+			packQuantity = &receipt.Lines[i].Quantity
+			quantity = line.Quantity.Mul(pack.Factor)
+			unitCost = pack.Factor.PerUnit(line.UnitCost)
+		}
+
+		if unitCost == 0 {
+			unitCost = orderedCost
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO goods_receipt_items
+			(goods_receipt_id, purchase_order_item_id, product_id, quantity, unit_cost, notes, pack_name, pack_quantity)
+			VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		`, receipt.ID, itemID, line.ProductID, quantity, unitCost, line.Notes, line.Pack, packQuantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create goods receipt item: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			UPDATE purchase_order_items SET quantity_received = quantity_received + $1
+			WHERE id = $2
+		`, quantity, itemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update received quantity: %w", err)
+		}
+
+		if line.BatchNumber != "" {
+			err := insertStockLot(tx, &model.StockLot{
+				ProductID:      line.ProductID,
+				StoreID:        storeID,
+				BatchNumber:    line.BatchNumber,
+				ExpiryDate:     line.ExpiryDate,
+				Quantity:       quantity,
+				GoodsReceiptID: &receipt.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = recordStockMovement(tx, &model.StockMovement{
+			ProductID:     line.ProductID,
+			StoreID:       storeID,
+			Type:          model.MovementReceipt,
+			Quantity:      quantity,
+			Reason:        line.Notes,
+			ReferenceType: "goods_receipt",
+			ReferenceID:   &receipt.ID,
+			CreatedBy:     receipt.CreatedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The order is fully received once every item reached its ordered quantity
+	var pending int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM purchase_order_items
+		WHERE purchase_order_id = $1 AND quantity_received < quantity_ordered
+	`, receipt.PurchaseOrderID).Scan(&pending)
+	if err != nil {
+		return nil, err
+	}
+
+	newStatus := model.PurchaseOrderReceived
+	if pending > 0 {
+		newStatus = model.PurchaseOrderPartiallyReceived
+	}
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1 WHERE id = $2", newStatus, receipt.PurchaseOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return repo.GetByID(ctx, receipt.PurchaseOrderID)
+}
+
+func insertPurchaseOrderItems(tx *sql.Tx, order *model.PurchaseOrder) error {
+	for i := range order.Items {
+		order.Items[i].PurchaseOrderID = order.ID
+		if err := checkProductQuantity(tx, order.Items[i].ProductID, order.Items[i].QuantityOrdered); err != nil {
+			return err
+		}
+
+		err := tx.QueryRow(`
+			INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity_ordered, unit_cost)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, order.ID, order.Items[i].ProductID, order.Items[i].QuantityOrdered, order.Items[i].UnitCost).
+			Scan(&order.Items[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order item: %w", err)
+		}
+	}
+	return nil
+}
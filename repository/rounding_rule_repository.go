@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+)
+
+type RoundingRuleRepository interface {
+	GetAll(ctx context.Context) ([]model.RoundingRule, error)
+	Save(ctx context.Context, rule *model.RoundingRule) error
+	Delete(ctx context.Context, tenderType string) (int64, error) // Return rows affected
+}
+
+type RoundingRuleRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRoundingRuleRepository(db *sql.DB) RoundingRuleRepository {
+	return &RoundingRuleRepositoryImpl{db: db}
+}
+
+// Query functions
+func (repo *RoundingRuleRepositoryImpl) GetAll(ctx context.Context) ([]model.RoundingRule, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT tender_type, mode, increment FROM rounding_rules ORDER BY tender_type")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rounding rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]model.RoundingRule, 0)
+	for rows.Next() {
+		var rule model.RoundingRule
+		if err := rows.Scan(&rule.TenderType, &rule.Mode, &rule.Increment); err != nil {
+			return nil, fmt.Errorf("failed to scan rounding rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Command functions
+// Save sets the rule of a tender type, replacing any it had
+func (repo *RoundingRuleRepositoryImpl) Save(ctx context.Context, rule *model.RoundingRule) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO rounding_rules (tender_type, mode, increment)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, tender_type) DO UPDATE SET mode = EXCLUDED.mode, increment = EXCLUDED.increment
+	`, rule.TenderType, rule.Mode, rule.Increment)
+	if err != nil {
+		return fmt.Errorf("failed to save rounding rule: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (repo *RoundingRuleRepositoryImpl) Delete(ctx context.Context, tenderType string) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM rounding_rules WHERE tender_type = $1", tenderType)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-cashier-api/model"
+)
+
+type StockAdjustmentRepository interface {
+	GetReasons(ctx context.Context) ([]model.AdjustmentReason, error)
+	GetReasonByCode(ctx context.Context, code string) (*model.AdjustmentReason, error)
+	CreateReason(ctx context.Context, reason *model.AdjustmentReason) error
+	Create(ctx context.Context, adjustment *model.StockAdjustment, direction string) error
+	GetShrinkage(ctx context.Context, startDate, endDate time.Time, period, timezone string, storeID int) ([]model.ShrinkageReportRow, error)
+}
+
+type StockAdjustmentRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockAdjustmentRepository(db *sql.DB) StockAdjustmentRepository {
+	return &StockAdjustmentRepositoryImpl{db: db}
+}
+
+// Query functions
+func (repo *StockAdjustmentRepositoryImpl) GetReasons(ctx context.Context) ([]model.AdjustmentReason, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT code, name, direction FROM adjustment_reasons ORDER BY code")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reasons := make([]model.AdjustmentReason, 0)
+	for rows.Next() {
+		var r model.AdjustmentReason
+		if err := rows.Scan(&r.Code, &r.Name, &r.Direction); err != nil {
+			return nil, err
+		}
+		reasons = append(reasons, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reasons, nil
+}
+
+func (repo *StockAdjustmentRepositoryImpl) GetReasonByCode(ctx context.Context, code string) (*model.AdjustmentReason, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var r model.AdjustmentReason
+	err = tx.QueryRow("SELECT code, name, direction FROM adjustment_reasons WHERE code = $1", code).
+		Scan(&r.Code, &r.Name, &r.Direction)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetShrinkage sums adjustment movements per reason, bucketed by period
+// (day, week or month) in the given timezone. A storeID of 0 covers all
+// stores.
+func (repo *StockAdjustmentRepositoryImpl) GetShrinkage(ctx context.Context, startDate, endDate time.Time, period, timezone string, storeID int) ([]model.ShrinkageReportRow, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT
+			date_trunc($3, (sa.created_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE $5) AS period,
+			ar.code,
+			ar.name,
+			COALESCE(SUM(sm.quantity), 0) AS quantity,
+			COALESCE(ROUND(SUM(sm.quantity * COALESCE(p.price, pp.price))), 0) AS value
+		FROM stock_adjustments sa
+		JOIN adjustment_reasons ar ON ar.code = sa.reason_code
+		JOIN stock_movements sm ON sm.reference_type = 'adjustment' AND sm.reference_id = sa.id
+		JOIN products p ON p.id = sm.product_id
+		LEFT JOIN products pp ON pp.id = p.parent_id
+		WHERE sa.created_at >= session_timestamp($1) AND sa.created_at < session_timestamp($2) AND ($4 = 0 OR sa.store_id = $4)
+		GROUP BY period, ar.code, ar.name
+		ORDER BY period, ar.code
+	`, startDate, endDate, period, storeID, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shrinkage report: %w", err)
+	}
+	defer rows.Close()
+
+	report := make([]model.ShrinkageReportRow, 0)
+	for rows.Next() {
+		var row model.ShrinkageReportRow
+		err := rows.Scan(&row.Period, &row.ReasonCode, &row.ReasonName, &row.Quantity, &row.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan shrinkage row: %w", err)
+		}
+		report = append(report, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Command functions
+func (repo *StockAdjustmentRepositoryImpl) CreateReason(ctx context.Context, r *model.AdjustmentReason) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO adjustment_reasons (code, name, direction) VALUES ($1, $2, $3)",
+		r.Code, r.Name, r.Direction)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Create stores the adjustment header and applies every line as a stock
+// movement. Either all lines are applied or none of them.
+func (repo *StockAdjustmentRepositoryImpl) Create(ctx context.Context, adjustment *model.StockAdjustment, direction string) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	adjustment.StoreID, err = resolveStoreID(tx, adjustment.StoreID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_adjustments (store_id, reason_code, notes, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, adjustment.StoreID, adjustment.ReasonCode, adjustment.Notes, adjustment.CreatedBy).Scan(&adjustment.ID, &adjustment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stock adjustment: %w", err)
+	}
+
+	adjustment.Movements = make([]model.StockMovement, 0, len(adjustment.Lines))
+	for _, line := range adjustment.Lines {
+		quantity := line.Quantity
+		if direction == model.DirectionOut {
+			quantity = -quantity
+		}
+
+		if line.LotID != 0 {
+			if err := adjustStockLot(tx, line.LotID, line.ProductID, adjustment.StoreID, quantity); err != nil {
+				return err
+			}
+		} else if quantity < 0 {
+			if _, err := takeStockLots(tx, line.ProductID, adjustment.StoreID, -quantity); err != nil {
+				return err
+			}
+		}
+
+		movement := model.StockMovement{
+			ProductID:     line.ProductID,
+			StoreID:       adjustment.StoreID,
+			Type:          model.MovementAdjustment,
+			Quantity:      quantity,
+			Reason:        adjustment.ReasonCode,
+			ReferenceType: "adjustment",
+			ReferenceID:   &adjustment.ID,
+			CreatedBy:     adjustment.CreatedBy,
+		}
+		if err := recordStockMovement(tx, &movement); err != nil {
+			return err
+		}
+		adjustment.Movements = append(adjustment.Movements, movement)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
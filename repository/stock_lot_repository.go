@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+)
+
+type StockLotRepository interface {
+	GetByProductID(ctx context.Context, productID, storeID int) ([]model.StockLot, error)
+	Create(ctx context.Context, lot *model.StockLot, createdBy string) error
+	GetExpiring(ctx context.Context, today, before model.Date, storeID int) ([]model.ExpiringLot, error)
+}
+
+type StockLotRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockLotRepository(db *sql.DB) StockLotRepository {
+	return &StockLotRepositoryImpl{db: db}
+}
+
+// Query functions
+// GetByProductID returns the lots of a product that still hold stock, the
+// one that expires first first. A storeID of 0 returns all stores.
+func (repo *StockLotRepositoryImpl) GetByProductID(ctx context.Context, productID, storeID int) ([]model.StockLot, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT sl.id, sl.product_id, p.name, sl.store_id, sl.batch_number, sl.expiry_date, sl.quantity,
+			sl.goods_receipt_id, sl.received_at
+		FROM stock_lots sl
+		JOIN products p ON p.id = sl.product_id
+		WHERE sl.product_id = $1 AND sl.quantity > 0 AND ($2 = 0 OR sl.store_id = $2)
+		ORDER BY sl.expiry_date NULLS LAST, sl.received_at, sl.id
+	`, productID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock lots: %w", err)
+	}
+	defer rows.Close()
+
+	lots := make([]model.StockLot, 0)
+	for rows.Next() {
+		var lot model.StockLot
+		var goodsReceiptID sql.NullInt64
+		err := rows.Scan(&lot.ID, &lot.ProductID, &lot.ProductName, &lot.StoreID, &lot.BatchNumber, &lot.ExpiryDate,
+			&lot.Quantity, &goodsReceiptID, &lot.ReceivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock lot: %w", err)
+		}
+		if goodsReceiptID.Valid {
+			id := int(goodsReceiptID.Int64)
+			lot.GoodsReceiptID = &id
+		}
+		lots = append(lots, lot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// GetExpiring returns lots with stock left that expire before the given date,
+// including lots that already expired, with their days left from today. A
+// storeID of 0 returns all stores.
+func (repo *StockLotRepositoryImpl) GetExpiring(ctx context.Context, today, before model.Date, storeID int) ([]model.ExpiringLot, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT sl.id, sl.product_id, p.name, sl.store_id, sl.batch_number, sl.expiry_date, sl.quantity,
+			sl.expiry_date - $3::date AS days_left
+		FROM stock_lots sl
+		JOIN products p ON p.id = sl.product_id
+		WHERE sl.quantity > 0 AND sl.expiry_date IS NOT NULL AND sl.expiry_date < $1::date
+			AND ($2 = 0 OR sl.store_id = $2)
+		ORDER BY sl.expiry_date, p.name
+	`, before, storeID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring lots: %w", err)
+	}
+	defer rows.Close()
+
+	lots := make([]model.ExpiringLot, 0)
+	for rows.Next() {
+		var lot model.ExpiringLot
+		err := rows.Scan(&lot.LotID, &lot.ProductID, &lot.ProductName, &lot.StoreID, &lot.BatchNumber, &lot.ExpiryDate,
+			&lot.Quantity, &lot.DaysLeft)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expiring lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+
+// Command functions
+// Create receives a new lot into stock and records the receipt movement
+func (repo *StockLotRepositoryImpl) Create(ctx context.Context, lot *model.StockLot, createdBy string) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertStockLot(tx, lot); err != nil {
+		return err
+	}
+
+	err = recordStockMovement(tx, &model.StockMovement{
+		ProductID:     lot.ProductID,
+		StoreID:       lot.StoreID,
+		Type:          model.MovementReceipt,
+		Quantity:      lot.Quantity,
+		Reason:        "lot " + lot.BatchNumber,
+		ReferenceType: "stock_lot",
+		ReferenceID:   &lot.ID,
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertStockLot(tx *sql.Tx, lot *model.StockLot) error {
+	var err error
+	lot.StoreID, err = resolveStoreID(tx, lot.StoreID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_lots (product_id, store_id, batch_number, expiry_date, quantity, goods_receipt_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, received_at
+	`, lot.ProductID, lot.StoreID, lot.BatchNumber, lot.ExpiryDate, lot.Quantity, lot.GoodsReceiptID).Scan(&lot.ID, &lot.ReceivedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stock lot: %w", err)
+	}
+	return nil
+}
+
+// adjustStockLot changes the quantity of a lot by delta. The lot must belong
+// to the product and store and can't go below zero.
+func adjustStockLot(tx *sql.Tx, lotID, productID, storeID int, delta decimal.Decimal) error {
+	result, err := tx.Exec(`
+		UPDATE stock_lots SET quantity = quantity + $1
+		WHERE id = $2 AND product_id = $3 AND store_id = $4 AND quantity + $1 >= 0
+	`, delta, lotID, productID, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to update stock lot: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("lot id %d of product id %d not found or has insufficient quantity", lotID, productID)
+	}
+	return nil
+}
+
+// consumeStockLots takes quantity from the stock of a product in a store.
+// Lots that have not expired by today go first, the one that expires first
+// first (FEFO), then untracked stock, the part of stock no lot holds.
+// Expired lots are only sold when that is not enough and allowExpired;
+// otherwise the sale fails, as it needs a manager override.
+func consumeStockLots(tx *sql.Tx, productID, storeID int, stock, quantity decimal.Decimal, today model.Date,
+	allowExpired bool) ([]model.TransactionDetailLot, error) {
+	rows, err := tx.Query(`
+		SELECT id, batch_number, expiry_date, quantity, COALESCE(expiry_date < $3::date, false) AS expired
+		FROM stock_lots
+		WHERE product_id = $1 AND store_id = $2 AND quantity > 0
+		ORDER BY expiry_date NULLS LAST, received_at, id
+		FOR UPDATE
+	`, productID, storeID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock lots: %w", err)
+	}
+
+	var valid, expired []model.TransactionDetailLot
+	untracked := stock
+	for rows.Next() {
+		var lot model.TransactionDetailLot
+		var isExpired bool
+		if err := rows.Scan(&lot.LotID, &lot.BatchNumber, &lot.ExpiryDate, &lot.Quantity, &isExpired); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stock lot: %w", err)
+		}
+		if isExpired {
+			expired = append(expired, lot)
+		} else {
+			valid = append(valid, lot)
+		}
+		untracked -= lot.Quantity
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	consumed := make([]model.TransactionDetailLot, 0)
+	remaining := quantity
+	take := func(lot model.TransactionDetailLot) error {
+		taken := min(lot.Quantity, remaining)
+		if err := adjustStockLot(tx, lot.LotID, productID, storeID, -taken); err != nil {
+			return err
+		}
+		lot.Quantity = taken
+		consumed = append(consumed, lot)
+		remaining -= taken
+		return nil
+	}
+
+	for _, lot := range valid {
+		if remaining == 0 {
+			break
+		}
+		if err := take(lot); err != nil {
+			return nil, err
+		}
+	}
+	remaining -= min(max(untracked, 0), remaining)
+
+	for _, lot := range expired {
+		if remaining == 0 {
+			break
+		}
+		if !allowExpired {
+			return nil, fmt.Errorf("lot %s of product id %d expired on %s, manager override required",
+				lot.BatchNumber, productID, lot.ExpiryDate.Format(model.DateLayout))
+		}
+		if err := take(lot); err != nil {
+			return nil, err
+		}
+	}
+
+	return consumed, nil
+}
+
+// takeStockLots takes quantity out of the lots of a product in a store for
+// stock that leaves it other than by a sale, so lots never hold more than
+// the store has. Lots go FEFO, expired ones included as nothing is sold;
+// whatever the lots do not cover was untracked stock.
+func takeStockLots(tx *sql.Tx, productID, storeID int, quantity decimal.Decimal) ([]model.TransactionDetailLot, error) {
+	rows, err := tx.Query(`
+		SELECT id, batch_number, expiry_date, quantity
+		FROM stock_lots
+		WHERE product_id = $1 AND store_id = $2 AND quantity > 0
+		ORDER BY expiry_date NULLS LAST, received_at, id
+		FOR UPDATE
+	`, productID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock lots: %w", err)
+	}
+
+	var lots []model.TransactionDetailLot
+	for rows.Next() {
+		var lot model.TransactionDetailLot
+		if err := rows.Scan(&lot.LotID, &lot.BatchNumber, &lot.ExpiryDate, &lot.Quantity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stock lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taken := make([]model.TransactionDetailLot, 0)
+	remaining := quantity
+	for _, lot := range lots {
+		if remaining == 0 {
+			break
+		}
+		lot.Quantity = min(lot.Quantity, remaining)
+		if err := adjustStockLot(tx, lot.LotID, productID, storeID, -lot.Quantity); err != nil {
+			return nil, err
+		}
+		taken = append(taken, lot)
+		remaining -= lot.Quantity
+	}
+	return taken, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+)
+
+type StockMovementRepository interface {
+	GetByProductID(ctx context.Context, productID, storeID int) ([]model.StockMovement, error)
+}
+
+type StockMovementRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) StockMovementRepository {
+	return &StockMovementRepositoryImpl{db: db}
+}
+
+// GetByProductID returns the movement history of a product, newest first.
+// A storeID of 0 returns the movements of all stores.
+func (repo *StockMovementRepositoryImpl) GetByProductID(ctx context.Context, productID, storeID int) ([]model.StockMovement, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, product_id, store_id, movement_type, quantity, balance_after, reason,
+			reference_type, reference_id, created_by, created_at
+		FROM stock_movements
+		WHERE product_id = $1 AND ($2 = 0 OR store_id = $2)
+		ORDER BY created_at DESC, id DESC
+	`, productID, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := make([]model.StockMovement, 0)
+	for rows.Next() {
+		var m model.StockMovement
+		var referenceID sql.NullInt64
+		err := rows.Scan(&m.ID, &m.ProductID, &m.StoreID, &m.Type, &m.Quantity, &m.BalanceAfter, &m.Reason,
+			&m.ReferenceType, &referenceID, &m.CreatedBy, &m.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement: %w", err)
+		}
+		if referenceID.Valid {
+			id := int(referenceID.Int64)
+			m.ReferenceID = &id
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// recordStockMovement applies a movement to the cached stock balances of the
+// store and of the product, and appends it to the ledger. It must run inside
+// the caller's transaction so the balances and the ledger can never disagree.
+func recordStockMovement(tx *sql.Tx, m *model.StockMovement) error {
+	if m.StoreID == 0 {
+		storeID, err := resolveStoreID(tx, 0)
+		if err != nil {
+			return err
+		}
+		m.StoreID = storeID
+	}
+
+	if err := checkProductQuantity(tx, m.ProductID, m.Quantity); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO product_stocks (product_id, store_id, stock)
+		SELECT id, $2, 0 FROM products WHERE id = $1
+		ON CONFLICT (product_id, store_id) DO NOTHING
+	`, m.ProductID, m.StoreID)
+	if err != nil {
+		return fmt.Errorf("failed to update store stock: %w", err)
+	}
+
+	// Refuse movements that would take the store balance below zero
+	err = tx.QueryRow(`
+		UPDATE product_stocks
+		SET stock = stock + $1
+		WHERE product_id = $2 AND store_id = $3 AND stock + $1 >= 0
+		RETURNING stock
+	`, m.Quantity, m.ProductID, m.StoreID).Scan(&m.BalanceAfter)
+	if err == sql.ErrNoRows {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM products WHERE id = $1)", m.ProductID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("product id %d not found", m.ProductID)
+		}
+		return fmt.Errorf("insufficient stock for product id %d", m.ProductID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update store stock: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE products SET stock = stock + $1 WHERE id = $2", m.Quantity, m.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to update product stock: %w", err)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_movements
+		(product_id, store_id, movement_type, quantity, balance_after, reason, reference_type, reference_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, m.ProductID, m.StoreID, m.Type, m.Quantity, m.BalanceAfter, m.Reason, m.ReferenceType, m.ReferenceID, m.CreatedBy).
+		Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+	return nil
+}
+
+// checkProductQuantity verifies that a quantity fits the unit of measure of
+// the product, so there are no half pieces
+func checkProductQuantity(tx *sql.Tx, productID int, quantity decimal.Decimal) error {
+	var unit string
+	err := tx.QueryRow("SELECT unit FROM products WHERE id = $1", productID).Scan(&unit)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product id %d not found", productID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := model.CheckQuantity(unit, quantity); err != nil {
+		return fmt.Errorf("product id %d: %w", productID, err)
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+)
+
+type StockTakeRepository interface {
+	GetAll(ctx context.Context) ([]model.StockTake, error)
+	GetByID(ctx context.Context, id int) (*model.StockTake, error)
+	Create(ctx context.Context, stockTake *model.StockTake) error
+	AddCounts(ctx context.Context, id int, batch *model.StockTakeCountBatch) error
+	Post(ctx context.Context, id int, postedBy string) error
+	UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error)
+}
+
+type StockTakeRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockTakeRepository(db *sql.DB) StockTakeRepository {
+	return &StockTakeRepositoryImpl{db: db}
+}
+
+// stockTakeItemsQuery selects the items of a session with the expected
+// quantity moved forward by every stock movement between the snapshot and
+// the last count of the product
+const stockTakeItemsQuery = `
+	SELECT
+		sti.product_id,
+		p.name,
+		sti.snapshot_qty,
+		sti.snapshot_qty + COALESCE((
+			SELECT SUM(sm.quantity)
+			FROM stock_movements sm
+			WHERE sm.product_id = sti.product_id
+				AND sm.store_id = st.store_id
+				AND sm.created_at > st.created_at
+				AND sm.created_at <= COALESCE(sti.last_counted_at, NOW())
+		), 0) AS expected_qty,
+		sti.counted_qty,
+		sti.last_counted_at
+	FROM stock_take_items sti
+	JOIN stock_takes st ON st.id = sti.stock_take_id
+	JOIN products p ON p.id = sti.product_id
+	WHERE sti.stock_take_id = $1
+	ORDER BY p.name
+`
+
+// Query functions
+func (repo *StockTakeRepositoryImpl) GetAll(ctx context.Context) ([]model.StockTake, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, name, store_id, status, notes, created_by, created_at, posted_at
+		FROM stock_takes
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock takes: %w", err)
+	}
+	defer rows.Close()
+
+	stockTakes := make([]model.StockTake, 0)
+	for rows.Next() {
+		var st model.StockTake
+		err := rows.Scan(&st.ID, &st.Name, &st.StoreID, &st.Status, &st.Notes, &st.CreatedBy, &st.CreatedAt, &st.PostedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock take: %w", err)
+		}
+		stockTakes = append(stockTakes, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range stockTakes {
+		stockTakes[i].CategoryIDs, err = getStockTakeCategoryIDs(tx, stockTakes[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stockTakes, nil
+}
+
+// GetByID returns a stock take with its items and current variances
+func (repo *StockTakeRepositoryImpl) GetByID(ctx context.Context, id int) (*model.StockTake, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var st model.StockTake
+	err = tx.QueryRow(`
+		SELECT id, name, store_id, status, notes, created_by, created_at, posted_at
+		FROM stock_takes
+		WHERE id = $1
+	`, id).Scan(&st.ID, &st.Name, &st.StoreID, &st.Status, &st.Notes, &st.CreatedBy, &st.CreatedAt, &st.PostedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st.CategoryIDs, err = getStockTakeCategoryIDs(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(stockTakeItemsQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock take items: %w", err)
+	}
+	defer rows.Close()
+
+	st.Items, err = scanStockTakeItems(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func getStockTakeCategoryIDs(tx *sql.Tx, id int) ([]int, error) {
+	rows, err := tx.Query("SELECT category_id FROM stock_take_categories WHERE stock_take_id = $1 ORDER BY category_id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categoryIDs := make([]int, 0)
+	for rows.Next() {
+		var categoryID int
+		if err := rows.Scan(&categoryID); err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	return categoryIDs, rows.Err()
+}
+
+func scanStockTakeItems(rows *sql.Rows) ([]model.StockTakeItem, error) {
+	items := make([]model.StockTakeItem, 0)
+	for rows.Next() {
+		var item model.StockTakeItem
+		var counted *decimal.Decimal
+		err := rows.Scan(&item.ProductID, &item.ProductName, &item.SnapshotQty, &item.ExpectedQty,
+			&counted, &item.LastCountedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock take item: %w", err)
+		}
+		if counted != nil {
+			variance := *counted - item.ExpectedQty
+			item.CountedQty = counted
+			item.Variance = &variance
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Command functions
+// Create opens a session and snapshots the store stock of every product in
+// the selected categories
+func (repo *StockTakeRepositoryImpl) Create(ctx context.Context, st *model.StockTake) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	st.StoreID, err = resolveStoreID(tx, st.StoreID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_takes (name, store_id, status, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, st.Name, st.StoreID, model.StockTakeOpen, st.Notes, st.CreatedBy).Scan(&st.ID, &st.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stock take: %w", err)
+	}
+	st.Status = model.StockTakeOpen
+
+	for _, categoryID := range st.CategoryIDs {
+		_, err := tx.Exec("INSERT INTO stock_take_categories (stock_take_id, category_id) VALUES ($1, $2)", st.ID, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to add stock take category: %w", err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO stock_take_items (stock_take_id, product_id, snapshot_qty)
+			SELECT $1, p.id, COALESCE(ps.stock, 0)
+			FROM products p
+			LEFT JOIN product_stocks ps ON ps.product_id = p.id AND ps.store_id = $3
+			WHERE p.category_id = $2 AND p.archived_at IS NULL
+		`, st.ID, categoryID, st.StoreID)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot stock: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddCounts adds a batch of counted quantities to an open session
+func (repo *StockTakeRepositoryImpl) AddCounts(ctx context.Context, id int, batch *model.StockTakeCountBatch) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// FOR SHARE lets several scanners count at once while blocking posting
+	var status string
+	err = tx.QueryRow("SELECT status FROM stock_takes WHERE id = $1 FOR SHARE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("stock take not found")
+	}
+	if err != nil {
+		return err
+	}
+	if status != model.StockTakeOpen {
+		return fmt.Errorf("stock take with status %s cannot be counted", status)
+	}
+
+	for _, line := range batch.Lines {
+		if err := checkProductQuantity(tx, line.ProductID, line.Quantity); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(`
+			UPDATE stock_take_items
+			SET counted_qty = COALESCE(counted_qty, 0) + $1, last_counted_at = NOW()
+			WHERE stock_take_id = $2 AND product_id = $3
+		`, line.Quantity, id, line.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to update counted quantity: %w", err)
+		}
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+			return fmt.Errorf("product id %d is not part of this stock take", line.ProductID)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO stock_take_counts (stock_take_id, product_id, quantity, device, counted_by)
+			VALUES ($1, $2, $3, $4, $5)
+		`, id, line.ProductID, line.Quantity, batch.Device, batch.CountedBy)
+		if err != nil {
+			return fmt.Errorf("failed to record count: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Post writes the variance of every counted product as a stock take
+// movement and closes the session. Products that were never counted are
+// left untouched.
+func (repo *StockTakeRepositoryImpl) Post(ctx context.Context, id int, postedBy string) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var storeID int
+	err = tx.QueryRow("SELECT status, store_id FROM stock_takes WHERE id = $1 FOR UPDATE", id).Scan(&status, &storeID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("stock take not found")
+	}
+	if err != nil {
+		return err
+	}
+	if status != model.StockTakeOpen {
+		return fmt.Errorf("stock take with status %s cannot be posted", status)
+	}
+
+	rows, err := tx.Query(stockTakeItemsQuery, id)
+	if err != nil {
+		return fmt.Errorf("failed to get stock take items: %w", err)
+	}
+	items, err := scanStockTakeItems(rows)
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.Variance == nil || *item.Variance == 0 {
+			continue
+		}
+
+		err := recordStockMovement(tx, &model.StockMovement{
+			ProductID:     item.ProductID,
+			StoreID:       storeID,
+			Type:          model.MovementStockTake,
+			Quantity:      *item.Variance,
+			Reason:        "stock take variance",
+			ReferenceType: "stock_take",
+			ReferenceID:   &id,
+			CreatedBy:     postedBy,
+		})
+		if err != nil {
+			return err
+		}
+		if *item.Variance < 0 {
+			if _, err := takeStockLots(tx, item.ProductID, storeID, -*item.Variance); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1, posted_at = NOW() WHERE id = $2", model.StockTakePosted, id)
+	if err != nil {
+		return fmt.Errorf("failed to post stock take: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateStatus moves a stock take from one status to another. It only
+// succeeds when the session is still in fromStatus.
+func (repo *StockTakeRepositoryImpl) UpdateStatus(ctx context.Context, id int, fromStatus, toStatus string) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE stock_takes SET status = $1 WHERE id = $2 AND status = $3", toStatus, id, fromStatus)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
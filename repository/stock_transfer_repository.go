@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+)
+
+type StockTransferRepository interface {
+	GetAll(ctx context.Context, status string) ([]model.StockTransfer, error)
+	GetByID(ctx context.Context, id int) (*model.StockTransfer, error)
+	Send(ctx context.Context, transfer *model.StockTransfer) error
+	Receive(ctx context.Context, id int, received map[int]decimal.Decimal, receivedBy string) error
+}
+
+type StockTransferRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStockTransferRepository(db *sql.DB) StockTransferRepository {
+	return &StockTransferRepositoryImpl{db: db}
+}
+
+// Query functions
+// GetAll returns transfers without their items, newest first. An empty
+// status returns every transfer.
+func (repo *StockTransferRepositoryImpl) GetAll(ctx context.Context, status string) ([]model.StockTransfer, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, from_store_id, to_store_id, status, notes, sent_by, sent_at, received_by, received_at
+		FROM stock_transfers
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY sent_at DESC, id DESC
+	`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock transfers: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := make([]model.StockTransfer, 0)
+	for rows.Next() {
+		var t model.StockTransfer
+		err := rows.Scan(&t.ID, &t.FromStoreID, &t.ToStoreID, &t.Status, &t.Notes,
+			&t.SentBy, &t.SentAt, &t.ReceivedBy, &t.ReceivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock transfer: %w", err)
+		}
+		transfers = append(transfers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// GetByID returns a transfer with its items
+func (repo *StockTransferRepositoryImpl) GetByID(ctx context.Context, id int) (*model.StockTransfer, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var t model.StockTransfer
+	err = tx.QueryRow(`
+		SELECT id, from_store_id, to_store_id, status, notes, sent_by, sent_at, received_by, received_at
+		FROM stock_transfers
+		WHERE id = $1
+	`, id).Scan(&t.ID, &t.FromStoreID, &t.ToStoreID, &t.Status, &t.Notes,
+		&t.SentBy, &t.SentAt, &t.ReceivedBy, &t.ReceivedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+		SELECT sti.product_id, p.name, sti.quantity_sent, sti.quantity_received
+		FROM stock_transfer_items sti
+		JOIN products p ON p.id = sti.product_id
+		WHERE sti.stock_transfer_id = $1
+		ORDER BY sti.id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock transfer items: %w", err)
+	}
+	defer rows.Close()
+
+	t.Items = make([]model.StockTransferItem, 0)
+	for rows.Next() {
+		var item model.StockTransferItem
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &item.QuantitySent, &item.QuantityReceived); err != nil {
+			return nil, fmt.Errorf("failed to scan stock transfer item: %w", err)
+		}
+		t.Items = append(t.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Command functions
+// Send takes the items out of the sending store and leaves them in transit
+// until the receiving store books them in
+func (repo *StockTransferRepositoryImpl) Send(ctx context.Context, t *model.StockTransfer) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_transfers (from_store_id, to_store_id, status, notes, sent_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, sent_at
+	`, t.FromStoreID, t.ToStoreID, model.TransferInTransit, t.Notes, t.SentBy).Scan(&t.ID, &t.SentAt)
+	if err != nil {
+		return fmt.Errorf("failed to create stock transfer: %w", err)
+	}
+	t.Status = model.TransferInTransit
+
+	for _, item := range t.Items {
+		_, err := tx.Exec(`
+			INSERT INTO stock_transfer_items (stock_transfer_id, product_id, quantity_sent)
+			VALUES ($1, $2, $3)
+		`, t.ID, item.ProductID, item.QuantitySent)
+		if err != nil {
+			return fmt.Errorf("failed to add stock transfer item: %w", err)
+		}
+
+		err = recordStockMovement(tx, &model.StockMovement{
+			ProductID:     item.ProductID,
+			StoreID:       t.FromStoreID,
+			Type:          model.MovementTransfer,
+			Quantity:      -item.QuantitySent,
+			Reason:        fmt.Sprintf("transfer to store %d", t.ToStoreID),
+			ReferenceType: "stock_transfer",
+			ReferenceID:   &t.ID,
+			CreatedBy:     t.SentBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		lots, err := takeStockLots(tx, item.ProductID, t.FromStoreID, item.QuantitySent)
+		if err != nil {
+			return err
+		}
+		for _, lot := range lots {
+			_, err := tx.Exec(`
+				INSERT INTO stock_transfer_lots (stock_transfer_id, product_id, lot_id, batch_number, expiry_date, quantity)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, t.ID, item.ProductID, lot.LotID, lot.BatchNumber, lot.ExpiryDate, lot.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to add stock transfer lot: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Receive books an in-transit transfer into the receiving store. received
+// maps product IDs to the quantity that arrived; products not in the map
+// arrive in full. Whatever falls short stays written off as lost in transit.
+func (repo *StockTransferRepositoryImpl) Receive(ctx context.Context, id int, received map[int]decimal.Decimal, receivedBy string) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var fromStoreID, toStoreID int
+	err = tx.QueryRow("SELECT status, from_store_id, to_store_id FROM stock_transfers WHERE id = $1 FOR UPDATE", id).
+		Scan(&status, &fromStoreID, &toStoreID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("stock transfer not found")
+	}
+	if err != nil {
+		return err
+	}
+	if status != model.TransferInTransit {
+		return fmt.Errorf("stock transfer with status %s cannot be received", status)
+	}
+
+	rows, err := tx.Query("SELECT product_id, quantity_sent FROM stock_transfer_items WHERE stock_transfer_id = $1 ORDER BY id", id)
+	if err != nil {
+		return fmt.Errorf("failed to get stock transfer items: %w", err)
+	}
+	var productIDs []int
+	sent := make(map[int]decimal.Decimal)
+	for rows.Next() {
+		var productID int
+		var quantity decimal.Decimal
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		productIDs = append(productIDs, productID)
+		sent[productID] = quantity
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for productID := range received {
+		if _, ok := sent[productID]; !ok {
+			return fmt.Errorf("product id %d is not part of this transfer", productID)
+		}
+	}
+
+	for _, productID := range productIDs {
+		quantitySent := sent[productID]
+		quantity, ok := received[productID]
+		if !ok {
+			quantity = quantitySent
+		}
+		if quantity > quantitySent {
+			return fmt.Errorf("received quantity of product id %d exceeds the quantity sent", productID)
+		}
+
+		_, err := tx.Exec(`
+			UPDATE stock_transfer_items SET quantity_received = $1
+			WHERE stock_transfer_id = $2 AND product_id = $3
+		`, quantity, id, productID)
+		if err != nil {
+			return fmt.Errorf("failed to update stock transfer item: %w", err)
+		}
+
+		if quantity == 0 {
+			continue
+		}
+		err = recordStockMovement(tx, &model.StockMovement{
+			ProductID:     productID,
+			StoreID:       toStoreID,
+			Type:          model.MovementTransfer,
+			Quantity:      quantity,
+			Reason:        fmt.Sprintf("transfer from store %d", fromStoreID),
+			ReferenceType: "stock_transfer",
+			ReferenceID:   &id,
+			CreatedBy:     receivedBy,
+		})
+		if err != nil {
+			return err
+		}
+		if err := receiveTransferLots(tx, id, productID, toStoreID, quantity); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE stock_transfers SET status = $1, received_by = $2, received_at = NOW()
+		WHERE id = $3
+	`, model.TransferReceived, receivedBy, id)
+	if err != nil {
+		return fmt.Errorf("failed to receive stock transfer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// receiveTransferLots recreates the lots a product was sent in at the
+// receiving store, with the same batch number and expiry date. What arrived
+// fills the lots that expire first; anything beyond them was untracked.
+func receiveTransferLots(tx *sql.Tx, transferID, productID, storeID int, quantity decimal.Decimal) error {
+	rows, err := tx.Query(`
+		SELECT batch_number, expiry_date, quantity
+		FROM stock_transfer_lots
+		WHERE stock_transfer_id = $1 AND product_id = $2
+		ORDER BY expiry_date NULLS LAST, id
+	`, transferID, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock transfer lots: %w", err)
+	}
+	var lots []model.StockLot
+	for rows.Next() {
+		lot := model.StockLot{ProductID: productID, StoreID: storeID}
+		if err := rows.Scan(&lot.BatchNumber, &lot.ExpiryDate, &lot.Quantity); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan stock transfer lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	remaining := quantity
+	for i := range lots {
+		if remaining == 0 {
+			break
+		}
+		lots[i].Quantity = min(lots[i].Quantity, remaining)
+		if err := insertStockLot(tx, &lots[i]); err != nil {
+			return err
+		}
+		remaining -= lots[i].Quantity
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-cashier-api/model"
+)
+
+type StoreRepository interface {
+	GetAll(ctx context.Context) ([]model.Store, error)
+	GetByID(ctx context.Context, id int) (*model.Store, error)
+	Create(ctx context.Context, store *model.Store) error
+	Update(ctx context.Context, store *model.Store) (int64, error) // Return rows affected
+	GetProductStock(ctx context.Context, productID int) ([]model.ProductStock, error)
+}
+
+type StoreRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewStoreRepository(db *sql.DB) StoreRepository {
+	return &StoreRepositoryImpl{db: db}
+}
+
+// Query functions
+func (repo *StoreRepositoryImpl) GetAll(ctx context.Context) ([]model.Store, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, name, address, currency, timezone FROM stores ORDER BY id"
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stores := make([]model.Store, 0)
+	for rows.Next() {
+		var s model.Store
+		if err := rows.Scan(&s.ID, &s.Name, &s.Address, &s.Currency, &s.Timezone); err != nil {
+			return nil, err
+		}
+		stores = append(stores, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stores, nil
+}
+
+// GetByID returns a store by its ID
+func (repo *StoreRepositoryImpl) GetByID(ctx context.Context, id int) (*model.Store, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, name, address, currency, timezone FROM stores WHERE id = $1"
+
+	var s model.Store
+	err = tx.QueryRow(query, id).Scan(&s.ID, &s.Name, &s.Address, &s.Currency, &s.Timezone)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// GetProductStock returns the stock of a product in every store, together
+// with what is on its way to each store. The stock of a composite product
+// is what the components in that store allow.
+func (repo *StoreRepositoryImpl) GetProductStock(ctx context.Context, productID int) ([]model.ProductStock, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT
+			s.id,
+			s.name,
+			COALESCE(`+compositeStoreStock+`, ps.stock, 0),
+			COALESCE((
+				SELECT SUM(sti.quantity_sent)
+				FROM stock_transfer_items sti
+				JOIN stock_transfers st ON st.id = sti.stock_transfer_id
+				WHERE st.to_store_id = s.id AND st.status = $2 AND sti.product_id = $1
+			), 0) AS in_transit
+		FROM stores s
+		LEFT JOIN product_stocks ps ON ps.store_id = s.id AND ps.product_id = $1
+		ORDER BY s.id
+	`, productID, model.TransferInTransit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stocks := make([]model.ProductStock, 0)
+	for rows.Next() {
+		var ps model.ProductStock
+		if err := rows.Scan(&ps.StoreID, &ps.StoreName, &ps.Stock, &ps.InTransit); err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, ps)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// Command functions
+func (repo *StoreRepositoryImpl) Create(ctx context.Context, s *model.Store) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO stores (name, address, currency, timezone) VALUES ($1, $2, $3, $4) RETURNING id"
+	if err := tx.QueryRow(query, s.Name, s.Address, s.Currency, s.Timezone).Scan(&s.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *StoreRepositoryImpl) Update(ctx context.Context, store *model.Store) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE stores SET name = $1, address = $2, currency = $3, timezone = $4 WHERE id = $5"
+	result, err := tx.Exec(query, store.Name, store.Address, store.Currency, store.Timezone, store.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// resolveStoreID returns the store a request refers to. 0 means the first
+// store of the tenant. Stores of other tenants are not visible and are
+// reported as not found.
+// storeDate returns today's date at a store, in its timezone
+func storeDate(tx *sql.Tx, storeID int) (model.Date, error) {
+	var timezone string
+	if err := tx.QueryRow("SELECT timezone FROM stores WHERE id = $1", storeID).Scan(&timezone); err != nil {
+		return model.Date{}, fmt.Errorf("failed to get store timezone: %w", err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return model.Date{}, err
+	}
+
+	now := time.Now().In(loc)
+	return model.Date{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func resolveStoreID(tx *sql.Tx, storeID int) (int, error) {
+	var err error
+	if storeID == 0 {
+		err = tx.QueryRow("SELECT id FROM stores ORDER BY id LIMIT 1").Scan(&storeID)
+	} else {
+		err = tx.QueryRow("SELECT id FROM stores WHERE id = $1", storeID).Scan(&storeID)
+	}
+	if err == sql.ErrNoRows {
+		if storeID == 0 {
+			return 0, errors.New("no store configured")
+		}
+		return 0, fmt.Errorf("store id %d not found", storeID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return storeID, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"go-cashier-api/model"
+)
+
+type SupplierRepository interface {
+	GetAll(ctx context.Context) ([]model.Supplier, error)
+	GetByID(ctx context.Context, id int) (*model.Supplier, error)
+	Create(ctx context.Context, supplier *model.Supplier) error
+	Update(ctx context.Context, supplier *model.Supplier) (int64, error) // Return rows affected
+	Delete(ctx context.Context, id int) (int64, error)                   // Return rows affected
+}
+
+type SupplierRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) SupplierRepository {
+	return &SupplierRepositoryImpl{db: db}
+}
+
+// Query functions
+func (repo *SupplierRepositoryImpl) GetAll(ctx context.Context) ([]model.Supplier, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, name, phone, email, address FROM suppliers ORDER BY name"
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]model.Supplier, 0)
+	for rows.Next() {
+		var s model.Supplier
+		err := rows.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
+
+// GetByID returns a supplier by its ID
+func (repo *SupplierRepositoryImpl) GetByID(ctx context.Context, id int) (*model.Supplier, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, name, phone, email, address FROM suppliers WHERE id = $1"
+
+	var s model.Supplier
+	err = tx.QueryRow(query, id).Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Command functions
+func (repo *SupplierRepositoryImpl) Create(ctx context.Context, s *model.Supplier) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO suppliers (name, phone, email, address) VALUES ($1, $2, $3, $4) RETURNING id"
+	if err := tx.QueryRow(query, s.Name, s.Phone, s.Email, s.Address).Scan(&s.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *SupplierRepositoryImpl) Update(ctx context.Context, supplier *model.Supplier) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE suppliers SET name = $1, phone = $2, email = $3, address = $4 WHERE id = $5"
+	result, err := tx.Exec(query, supplier.Name, supplier.Phone, supplier.Email, supplier.Address, supplier.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (repo *SupplierRepositoryImpl) Delete(ctx context.Context, id int) (int64, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM suppliers WHERE id = $1"
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
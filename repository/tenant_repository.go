@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/tenant"
+)
+
+type TenantRepository interface {
+	GetByAPIKey(apiKey string) (*model.Tenant, error)
+	GetByHost(host string) (*model.Tenant, error)
+}
+
+type TenantRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewTenantRepository(db *sql.DB) TenantRepository {
+	return &TenantRepositoryImpl{db: db}
+}
+
+func (repo *TenantRepositoryImpl) GetByAPIKey(apiKey string) (*model.Tenant, error) {
+	return repo.getOne("SELECT id, name, COALESCE(host, '') FROM tenants WHERE api_key = $1", apiKey)
+}
+
+func (repo *TenantRepositoryImpl) GetByHost(host string) (*model.Tenant, error) {
+	return repo.getOne("SELECT id, name, COALESCE(host, '') FROM tenants WHERE host = $1", host)
+}
+
+func (repo *TenantRepositoryImpl) getOne(query string, arg string) (*model.Tenant, error) {
+	var t model.Tenant
+	err := repo.db.QueryRow(query, arg).Scan(&t.ID, &t.Name, &t.Host)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// beginTx starts a database transaction scoped to the tenant of ctx. The
+// row-level security policies only show rows of the tenant stored in
+// app.tenant_id, and the setting ends with the transaction so a pooled
+// connection never carries it over to another request. Every repository
+// query runs inside such a transaction.
+func beginTx(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("tenant is required")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	_, err = tx.Exec("SELECT set_config('app.tenant_id', $1, true)", strconv.Itoa(tenantID))
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to set tenant: %w", err)
+	}
+	return tx, nil
+}
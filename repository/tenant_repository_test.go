@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"go-cashier-api/internal/testutil"
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/tenant"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	db := testutil.DB(t)
+	repo := NewCategoryRepository(db)
+
+	a := testutil.CreateTenant(t, db, "tenant-a", "categories")
+	b := testutil.CreateTenant(t, db, "tenant-b", "categories")
+	ctxA := tenant.WithID(context.Background(), a.ID)
+	ctxB := tenant.WithID(context.Background(), b.ID)
+
+	catA := model.Category{Name: "Drinks of A"}
+	if err := repo.Create(ctxA, &catA); err != nil {
+		t.Fatalf("create category of A: %v", err)
+	}
+	catB := model.Category{Name: "Drinks of B"}
+	if err := repo.Create(ctxB, &catB); err != nil {
+		t.Fatalf("create category of B: %v", err)
+	}
+
+	t.Run("reads", func(t *testing.T) {
+		all, err := repo.GetAll(ctxA)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 1 || all[0].ID != catA.ID {
+			t.Errorf("GetAll as A = %+v, want only category %d", all, catA.ID)
+		}
+
+		got, err := repo.GetByID(ctxA, catB.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got != nil {
+			t.Errorf("GetByID as A of B's category = %+v, want nil", got)
+		}
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		rows, err := repo.Update(ctxA, &model.Category{ID: catB.ID, Name: "Taken by A"})
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if rows != 0 {
+			t.Errorf("Update as A of B's category affected %d rows, want 0", rows)
+		}
+
+		rows, err = repo.Delete(ctxA, catB.ID)
+		if err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if rows != 0 {
+			t.Errorf("Delete as A of B's category affected %d rows, want 0", rows)
+		}
+
+		got, err := repo.GetByID(ctxB, catB.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got == nil || got.Name != catB.Name {
+			t.Errorf("B's category after writes by A = %+v, want it unchanged", got)
+		}
+	})
+
+	t.Run("insert into another tenant", func(t *testing.T) {
+		tx, err := beginTx(ctxA, db)
+		if err != nil {
+			t.Fatalf("beginTx: %v", err)
+		}
+		defer tx.Rollback()
+
+		_, err = tx.Exec("INSERT INTO categories (name, description, station, tenant_id) VALUES ('Planted', '', '', $1)", b.ID)
+		if err == nil {
+			t.Error("insert as A of a row of B succeeded, want a row-level security error")
+		}
+	})
+
+	t.Run("no tenant", func(t *testing.T) {
+		if _, err := repo.GetAll(context.Background()); err == nil {
+			t.Error("GetAll without a tenant succeeded, want an error")
+		}
+
+		// Even a transaction that skips beginTx sees no rows
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM categories WHERE id IN ($1, $2)", catA.ID, catB.ID).Scan(&count); err != nil {
+			t.Fatalf("count without tenant: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("categories visible without a tenant = %d, want 0", count)
+		}
+	})
+}
+
+func TestProductTenantIsolation(t *testing.T) {
+	db := testutil.DB(t)
+	repo := NewProductRepository(db)
+
+	a := testutil.CreateTenant(t, db, "tenant-a", "products")
+	b := testutil.CreateTenant(t, db, "tenant-b", "products")
+	ctxA := tenant.WithID(context.Background(), a.ID)
+	ctxB := tenant.WithID(context.Background(), b.ID)
+
+	prodA := model.Product{Name: "Coffee of A", Type: model.ProductStandard, SKU: "SHARED-SKU", Price: 1000, Unit: "pcs"}
+	if err := repo.Create(ctxA, &prodA); err != nil {
+		t.Fatalf("create product of A: %v", err)
+	}
+	// Unique columns are unique per tenant, so B may reuse A's SKU
+	prodB := model.Product{Name: "Coffee of B", Type: model.ProductStandard, SKU: "SHARED-SKU", Price: 2000, Unit: "pcs"}
+	if err := repo.Create(ctxB, &prodB); err != nil {
+		t.Fatalf("create product of B: %v", err)
+	}
+
+	t.Run("reads", func(t *testing.T) {
+		all, err := repo.GetAll(ctxA, "")
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(all) != 1 || all[0].ID != prodA.ID {
+			t.Errorf("GetAll as A = %+v, want only product %d", all, prodA.ID)
+		}
+
+		got, err := repo.GetByID(ctxA, prodB.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got != nil {
+			t.Errorf("GetByID as A of B's product = %+v, want nil", got)
+		}
+
+		got, err = repo.GetBySKU(ctxA, "SHARED-SKU")
+		if err != nil {
+			t.Fatalf("GetBySKU: %v", err)
+		}
+		if got == nil || got.ID != prodA.ID {
+			t.Errorf("GetBySKU as A = %+v, want product %d", got, prodA.ID)
+		}
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		taken := prodB
+		taken.Name = "Taken by A"
+		rows, err := repo.Update(ctxA, &taken)
+		if err == nil && rows != 0 {
+			t.Errorf("Update as A of B's product affected %d rows, want 0", rows)
+		}
+
+		rows, err = repo.Delete(ctxA, prodB.ID)
+		if err == nil && rows != 0 {
+			t.Errorf("Delete as A of B's product affected %d rows, want 0", rows)
+		}
+
+		got, err := repo.GetByID(ctxB, prodB.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got == nil || got.Name != prodB.Name {
+			t.Errorf("B's product after writes by A = %+v, want it unchanged", got)
+		}
+	})
+}
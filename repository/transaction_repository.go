@@ -1,19 +1,23 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
 	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
 )
 
 // Interface defines what methods the repository must implement
 // This allows for dependency injection and easier testing
 type TransactionRepository interface {
-	CreateTransaction(items []model.CheckoutItem) (*model.Transaction, error)
-	GetTransactionsByDate(startDate, endDate time.Time) ([]model.Transaction, int, int, *model.BestSellingProduct, error)
-	getTransactionDetails(transactionId int) ([]model.TransactionDetail, error)
+	CreateTransaction(ctx context.Context, request model.CheckoutRequest) (*model.Transaction, error)
+	GetTransactionsByDate(ctx context.Context, startDate, endDate time.Time, storeID int, rollUp bool) ([]model.Transaction, int, int, int, *model.BestSellingProduct, error)
+	GetSalesReport(ctx context.Context, query model.SalesReportQuery) (*model.SalesReport, error)
+	StreamTransactions(ctx context.Context, startDate, endDate time.Time, storeID int, fn func(model.Transaction) error) error
+	StreamTransactionDetails(ctx context.Context, startDate, endDate time.Time, storeID int, fn func(model.Transaction, model.TransactionDetail) error) error
 }
 
 // Implementation of the interface
@@ -26,30 +30,91 @@ func NewTransactionRepository(db *sql.DB) TransactionRepository {
 	return &TransactionRepositoryImpl{db: db}
 }
 
-func (repo *TransactionRepositoryImpl) CreateTransaction(items []model.CheckoutItem) (*model.Transaction, error) {
+func (repo *TransactionRepositoryImpl) CreateTransaction(ctx context.Context, request model.CheckoutRequest) (*model.Transaction, error) {
 	// Start a database transaction - ensures all operations succeed or fail together
-	tx, err := repo.db.Begin()
+	tx, err := beginTx(ctx, repo.db)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
 	// Defer ensures rollback happens if we don't reach commit()
 	defer tx.Rollback()
 
+	// An order is locked so it can only be paid once, and sells the lines it
+	// holds under that lock from its store. Lines can't change meanwhile.
+	if request.OrderID != 0 {
+		var status string
+		err := tx.QueryRow("SELECT status, store_id FROM orders WHERE id = $1 FOR UPDATE", request.OrderID).
+			Scan(&status, &request.StoreID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("order not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if status != model.OrderOpen && status != model.OrderHeld {
+			return nil, fmt.Errorf("order with status %s cannot be checked out", status)
+		}
+
+		request.Items, err = orderItems(tx, request.OrderID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request.StoreID, err = resolveStoreID(tx, request.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lots expire by the date at the store
+	today, err := storeDate(tx, request.StoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactionID int
+	var createdAt time.Time
+	var currency string
+	// Insert main transaction record first so stock movements can reference it,
+	// the total is filled in once all items are priced
+	err = tx.QueryRow(`
+        INSERT INTO transactions (store_id, total_amount, expired_override_by, currency) 
+        VALUES ($1, 0, NULLIF($2, ''), (SELECT currency FROM stores WHERE id = $1)) 
+        RETURNING id, created_at, currency
+    `, request.StoreID, request.OverrideExpiredBy).Scan(&transactionID, &createdAt, &currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
 	totalAmount := 0 // Initialize total price counter
 	// Pre-allocate slice with capacity equal to number of items (for better performance)
-	details := make([]model.TransactionDetail, 0, len(items))
+	details := make([]model.TransactionDetail, 0, len(request.Items))
+	allowExpired := request.OverrideExpiredBy != ""
 
-	for _, item := range items {
+	for _, item := range request.Items {
 		// Validate quantity is positive
 		if item.Quantity <= 0 {
 			return nil, fmt.Errorf("invalid quantity for product id %d", item.ProductID)
 		}
 
-		var productPrice, stock int
-		var productName string
-
-		// Query product details with FOR UPDATE to lock row during transaction
-		err := tx.QueryRow("SELECT name, price, stock FROM products WHERE id = $1", item.ProductID).Scan(&productName, &productPrice, &stock)
+		var productPrice int
+		var stock decimal.Decimal
+		var productName, unit, productType string
+		var validityDays *int
+		var hasVariants bool
+
+		// Query product details and the stock of this store with FOR UPDATE
+		// to lock the stock row during transaction. A variant without a
+		// price of its own sells at its parent's.
+		err := tx.QueryRow(`
+            SELECT p.name, COALESCE(p.price, pp.price), p.unit, COALESCE(ps.stock, 0), p.type, p.validity_days,
+                EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id AND v.archived_at IS NULL)
+            FROM products p
+            LEFT JOIN products pp ON pp.id = p.parent_id
+            LEFT JOIN product_stocks ps ON ps.product_id = p.id AND ps.store_id = $2
+            WHERE p.id = $1 AND p.archived_at IS NULL
+            FOR UPDATE OF p
+        `, item.ProductID, request.StoreID).Scan(&productName, &productPrice, &unit, &stock, &productType, &validityDays, &hasVariants)
 
 		// Handle cases where product doesn't exist
 		if err == sql.ErrNoRows {
@@ -58,59 +123,183 @@ func (repo *TransactionRepositoryImpl) CreateTransaction(items []model.CheckoutI
 		if err != nil {
 			return nil, err
 		}
-
-		// Check if we have enough stock
-		if stock < item.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product %s. Available: %d, Requested: %d",
-				productName, stock, item.Quantity)
+		if hasVariants {
+			return nil, fmt.Errorf("product %s has variants, sell one of them instead", productName)
 		}
 
 		// Calculate subtotal for this item
-		subtotal := productPrice * item.Quantity
+		quantity, packQuantity, subtotal, err := priceLine(tx, item, productName, productPrice)
+		if err != nil {
+			return nil, err
+		}
 		totalAmount += subtotal // Add to running total
 
-		// Update product stock (decrease by purchased quantity)
-		_, err = tx.Exec(`
-            UPDATE products 
-            SET stock = stock - $1 
-            WHERE id = $2
-        `, item.Quantity, item.ProductID)
+		// A composite product takes its stock from its components
+		components, err := getProductComponents(tx, item.ProductID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update product stock: %w", err)
+			return nil, err
+		}
+
+		// A gift card line takes no stock, it issues a card per unit below
+		var giftCards []model.GiftCard
+		if productType == model.ProductGiftCard {
+			if !item.Quantity.IsInteger() {
+				return nil, fmt.Errorf("quantity of gift card %s must be a whole number", productName)
+			}
+			for n := decimal.New(0); n < item.Quantity; n += decimal.New(1) {
+				card := model.GiftCard{InitialBalance: productPrice}
+				if validityDays != nil {
+					card.ValidityDays = *validityDays
+				}
+				giftCards = append(giftCards, card)
+			}
+		}
+
+		var lots []model.TransactionDetailLot
+		if len(components) == 0 && productType != model.ProductGiftCard {
+			lots, err = takeSaleStock(tx, item.ProductID, request.StoreID, productName, stock, quantity, today, allowExpired, transactionID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i := range components {
+			component := &components[i]
+			component.Quantity = quantity.Mul(component.Quantity)
+
+			var componentStock decimal.Decimal
+			err := tx.QueryRow(`
+				SELECT COALESCE(ps.stock, 0)
+				FROM products p
+				LEFT JOIN product_stocks ps ON ps.product_id = p.id AND ps.store_id = $2
+				WHERE p.id = $1
+				FOR UPDATE OF p
+			`, component.ProductID, request.StoreID).Scan(&componentStock)
+			if err != nil {
+				return nil, err
+			}
+
+			componentLots, err := takeSaleStock(tx, component.ProductID, request.StoreID, component.Name,
+				componentStock, component.Quantity, today, allowExpired, transactionID)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", productName, err)
+			}
+			lots = append(lots, componentLots...)
 		}
 
 		// Create transaction detail object (without database ID yet)
 		details = append(details, model.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+			ProductID:    item.ProductID,
+			ProductName:  productName,
+			Quantity:     quantity,
+			Unit:         unit,
+			Subtotal:     subtotal,
+			Pack:         item.Pack,
+			PackQuantity: packQuantity,
+			Lots:         lots,
+			Components:   components,
+			Modifiers:    item.Modifiers,
+			GiftCards:    giftCards,
 		})
 	}
 
-	var transactionID int
-	var createdAt time.Time
-	// Insert main transaction record and get auto-generated ID and timestamp
-	err = tx.QueryRow(`
-        INSERT INTO transactions (total_amount) 
-        VALUES ($1) 
-        RETURNING id, created_at
-    `, totalAmount).Scan(&transactionID, &createdAt)
+	// A coupon discounts its eligible lines and is counted in this transaction
+	discountAmount := 0
+	if request.CouponCode != "" {
+		discountAmount, err = redeemCoupon(tx, request.CouponCode, request.Customer, transactionID, details)
+		if err != nil {
+			return nil, err
+		}
+		totalAmount -= discountAmount
+	}
+
+	// Store the final total on the transaction record
+	_, err = tx.Exec(`
+		UPDATE transactions SET total_amount = $1, discount_amount = $2, coupon_code = NULLIF($3, '')
+		WHERE id = $4
+	`, totalAmount, discountAmount, request.CouponCode, transactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+		return nil, fmt.Errorf("failed to update transaction total: %w", err)
+	}
+
+	// Record how the sale was paid
+	payments, change, roundingAmount, err := insertPayments(tx, transactionID, nil, totalAmount, request.Tenders, request.Rounding)
+	if err != nil {
+		return nil, err
 	}
 
 	// Insert each transaction detail into database
 	for i := range details {
 		details[i].TransactionID = transactionID // Set foreign key
-		_, err = tx.Exec(`
+		err = tx.QueryRow(`
             INSERT INTO transaction_details 
-            (transaction_id, product_id, quantity, subtotal) 
-            VALUES ($1, $2, $3, $4)
-        `, transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal)
+            (transaction_id, product_id, quantity, subtotal, pack_name, pack_quantity, discount) 
+            VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+            RETURNING id
+        `, transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal,
+			details[i].Pack, details[i].PackQuantity, details[i].Discount).Scan(&details[i].ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create transaction detail: %w", err)
 		}
+
+		// Remember what a composite line took from its components
+		for _, component := range details[i].Components {
+			_, err = tx.Exec(`
+                INSERT INTO transaction_detail_components (transaction_detail_id, product_id, quantity)
+                VALUES ($1, $2, $3)
+            `, details[i].ID, component.ProductID, component.Quantity)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record component consumption: %w", err)
+			}
+		}
+
+		// Keep the modifiers with the line for receipts and kitchen tickets
+		for _, modifier := range details[i].Modifiers {
+			_, err = tx.Exec(`
+                INSERT INTO transaction_detail_modifiers (transaction_detail_id, modifier_id, group_name, name, price_delta)
+                VALUES ($1, $2, $3, $4, $5)
+            `, details[i].ID, modifier.ModifierID, modifier.GroupName, modifier.Name, modifier.PriceDelta)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record modifier: %w", err)
+			}
+		}
+
+		// Issue the gift cards a gift card line sold
+		for j := range details[i].GiftCards {
+			card := &details[i].GiftCards[j]
+			card.SoldIn = &details[i].ID
+			if err := issueGiftCard(tx, card, &transactionID); err != nil {
+				return nil, err
+			}
+		}
+
+		// Remember which lots this line consumed
+		for _, lot := range details[i].Lots {
+			_, err = tx.Exec(`
+                INSERT INTO transaction_detail_lots (transaction_detail_id, lot_id, quantity)
+                VALUES ($1, $2, $3)
+            `, details[i].ID, lot.LotID, lot.Quantity)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record consumed lot: %w", err)
+			}
+		}
+	}
+
+	// Route the lines to preparation stations, an order only sends the
+	// lines it has not sent yet
+	var kitchenItems []model.KitchenItem
+	if request.OrderID != 0 {
+		kitchenItems, err = sendOrderLines(tx, request.OrderID, &transactionID)
+		if err != nil {
+			return nil, err
+		}
+		if err := closeOrder(tx, request.OrderID, transactionID, totalAmount, request.Shares); err != nil {
+			return nil, err
+		}
+	} else {
+		kitchenItems, err = routeTransaction(tx, transactionID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Commit all changes to database - if successful, transaction is permanent
@@ -120,98 +309,287 @@ func (repo *TransactionRepositoryImpl) CreateTransaction(items []model.CheckoutI
 
 	// Return complete transaction object
 	return &model.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		CreatedAt:   createdAt,
-		Details:     details,
+		ID:                transactionID,
+		StoreID:           request.StoreID,
+		TotalAmount:       totalAmount,
+		DiscountAmount:    discountAmount,
+		CouponCode:        request.CouponCode,
+		RoundingAmount:    roundingAmount,
+		Currency:          currency,
+		ExpiredOverrideBy: request.OverrideExpiredBy,
+		CreatedAt:         createdAt,
+		Details:           details,
+		Payments:          payments,
+		Change:            change,
+		KitchenItems:      kitchenItems,
 	}, nil
 }
 
-func (repo *TransactionRepositoryImpl) GetTransactionsByDate(startDate, endDate time.Time) ([]model.Transaction, int, int, *model.BestSellingProduct, error) {
-	rows, err := repo.db.Query(`
-		SELECT id, total_amount, created_at
+func (repo *TransactionRepositoryImpl) GetTransactionsByDate(ctx context.Context, startDate, endDate time.Time, storeID int, rollUp bool) ([]model.Transaction, int, int, int, *model.BestSellingProduct, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, 0, 0, 0, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, store_id, total_amount, discount_amount, COALESCE(coupon_code, ''), rounding_amount, currency,
+			COALESCE(expired_override_by, ''), created_at
 		FROM transactions
-		WHERE created_at BETWEEN $1 AND $2
+		WHERE created_at >= session_timestamp($1) AND created_at < session_timestamp($2) AND ($3 = 0 OR store_id = $3)
 		ORDER BY created_at DESC
-	`, startDate, endDate)
+	`, startDate, endDate, storeID)
 
 	if err != nil {
-		return nil, 0, 0, nil, fmt.Errorf("failed to get transactions by date: %w", err)
+		return nil, 0, 0, 0, nil, fmt.Errorf("failed to get transactions by date: %w", err)
 	}
 	defer rows.Close()
 
 	var transactions []model.Transaction
 	for rows.Next() {
 		var transaction model.Transaction
-		err := rows.Scan(&transaction.ID, &transaction.TotalAmount, &transaction.CreatedAt)
+		err := rows.Scan(&transaction.ID, &transaction.StoreID, &transaction.TotalAmount, &transaction.DiscountAmount,
+			&transaction.CouponCode, &transaction.RoundingAmount, &transaction.Currency, &transaction.ExpiredOverrideBy, &transaction.CreatedAt)
+		if err != nil {
+			return nil, 0, 0, 0, nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	rows.Close()
+
+	// 🔥 load details for each transaction
+	for i := range transactions {
+		transactions[i].Details, err = getTransactionDetails(tx, transactions[i].ID)
 		if err != nil {
-			return nil, 0, 0, nil, fmt.Errorf("failed to scan transaction: %w", err)
+			return nil, 0, 0, 0, nil, err
 		}
-		// 🔥 load details for each transaction
-		transaction.Details, err = repo.getTransactionDetails(transaction.ID)
+		transactions[i].Payments, transactions[i].Change, err = getPayments(tx, transactions[i].ID, nil)
 		if err != nil {
-			return nil, 0, 0, nil, err
+			return nil, 0, 0, 0, nil, err
 		}
-		transactions = append(transactions, transaction)
 	}
 
 	// Get total transactions count for date range
 	var totalTransactions int
-	err = repo.db.QueryRow(`
+	err = tx.QueryRow(`
 		SELECT COUNT(*) 
 		FROM transactions 
-		WHERE created_at BETWEEN $1 AND $2
-	`, startDate, endDate).Scan(&totalTransactions)
+		WHERE created_at >= session_timestamp($1) AND created_at < session_timestamp($2) AND ($3 = 0 OR store_id = $3)
+	`, startDate, endDate, storeID).Scan(&totalTransactions)
 
 	if err != nil {
-		return nil, 0, 0, nil, fmt.Errorf("failed to get total transactions count: %w", err)
+		return nil, 0, 0, 0, nil, fmt.Errorf("failed to get total transactions count: %w", err)
 	}
 
-	// Get total revenue sum for date range
-	var totalRevenue int
-	err = repo.db.QueryRow(`
-		SELECT COALESCE(SUM(total_amount), 0)
+	// Get total revenue sum for date range, with rounding booked apart
+	var totalRevenue, totalRounding int
+	err = tx.QueryRow(`
+		SELECT COALESCE(SUM(total_amount), 0), COALESCE(SUM(rounding_amount), 0)
 		FROM transactions 
-		WHERE created_at BETWEEN $1 AND $2
-	`, startDate, endDate).Scan(&totalRevenue)
+		WHERE created_at >= session_timestamp($1) AND created_at < session_timestamp($2) AND ($3 = 0 OR store_id = $3)
+	`, startDate, endDate, storeID).Scan(&totalRevenue, &totalRounding)
 
 	if err != nil {
-		return nil, 0, 0, nil, fmt.Errorf("failed to get total revenue count: %w", err)
+		return nil, 0, 0, 0, nil, fmt.Errorf("failed to get total revenue count: %w", err)
 	}
 
-	// Get best-selling product for date range
+	// Get best-selling product for date range, rolling variants up to
+	// their parent when asked
 	var bestSellingProduct model.BestSellingProduct
-	err = repo.db.QueryRow(`
+	err = tx.QueryRow(`
 		SELECT
-			p.id,
-			p.name,
+			CASE WHEN $4 THEN COALESCE(pp.id, p.id) ELSE p.id END AS id,
+			CASE WHEN $4 THEN COALESCE(pp.name, p.name) ELSE p.name END AS name,
 			COALESCE(SUM(td.quantity), 0) AS total_sold
 		FROM transaction_details td
 		JOIN products p ON p.id = td.product_id
+		LEFT JOIN products pp ON pp.id = p.parent_id
 		JOIN transactions t ON t.id = td.transaction_id
-		WHERE t.created_at BETWEEN $1 AND $2
-		GROUP BY p.id, p.name
+		WHERE t.created_at >= session_timestamp($1) AND t.created_at < session_timestamp($2) AND ($3 = 0 OR t.store_id = $3)
+		GROUP BY 1, 2
 		ORDER BY total_sold DESC
 		LIMIT 1
-	`, startDate, endDate).Scan(&bestSellingProduct.ID,
+	`, startDate, endDate, storeID, rollUp).Scan(&bestSellingProduct.ID,
 		&bestSellingProduct.Name,
 		&bestSellingProduct.TotalSold)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// No sales in date range → return nil instead of error
-			return transactions, totalTransactions, totalRevenue, nil, nil
+			return transactions, totalTransactions, totalRevenue, totalRounding, nil, nil
 		}
-		return nil, 0, 0, nil, fmt.Errorf("failed to get best selling product: %w", err)
+		return nil, 0, 0, 0, nil, fmt.Errorf("failed to get best selling product: %w", err)
 	}
 
-	return transactions, totalTransactions, totalRevenue, &bestSellingProduct, nil
+	return transactions, totalTransactions, totalRevenue, totalRounding, &bestSellingProduct, nil
 }
 
-func (repo *TransactionRepositoryImpl) getTransactionDetails(transactionID int) ([]model.TransactionDetail, error) {
+// salesReportTables joins the sold lines to their sale, product and the
+// category of the product or, for a variant without one, of its parent
+const salesReportTables = `
+	FROM transaction_details td
+	JOIN transactions t ON t.id = td.transaction_id
+	JOIN products p ON p.id = td.product_id
+	LEFT JOIN products pp ON pp.id = p.parent_id
+	LEFT JOIN categories c ON c.id = COALESCE(p.category_id, pp.category_id)
+	WHERE t.created_at >= session_timestamp($1) AND t.created_at < session_timestamp($2) AND ($3 = 0 OR t.store_id = $3)
+`
+
+const salesReportSums = `COALESCE(SUM(td.quantity), 0), COUNT(DISTINCT t.id),
+	COALESCE(SUM(td.subtotal), 0), COALESCE(SUM(td.discount), 0), COALESCE(SUM(td.subtotal - td.discount), 0)`
+
+// GetSalesReport sums the sold lines of a date range per period, category
+// or product. A storeID of 0 covers all stores.
+func (repo *TransactionRepositoryImpl) GetSalesReport(ctx context.Context, query model.SalesReportQuery) (*model.SalesReport, error) {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	args := []interface{}{query.StartDate, query.EndDate, query.StoreID}
+	var group, order string
+	switch query.GroupBy {
+	case model.SalesByCategory:
+		group, order = "NULL::timestamp, c.id, COALESCE(c.name, '')", "net DESC, 3"
+	case model.SalesByProduct:
+		group, order = "NULL::timestamp, p.id, p.name", "net DESC, 3"
+		if query.RollUp {
+			group = "NULL::timestamp, COALESCE(pp.id, p.id), COALESCE(pp.name, p.name)"
+		}
+	default:
+		args = append(args, query.GroupBy, query.Timezone)
+		group, order = "date_trunc($4, (t.created_at AT TIME ZONE current_setting('TimeZone')) AT TIME ZONE $5), NULL::int, ''", "1"
+	}
+
+	limit := ""
+	if query.Top > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", query.Top)
+	} else if query.Bottom > 0 {
+		order, limit = "net, 3", fmt.Sprintf(" LIMIT %d", query.Bottom)
+	}
+
+	rows, err := tx.Query(`
+		SELECT `+group+`, `+salesReportSums+` AS net
+		`+salesReportTables+`
+		GROUP BY 1, 2, 3
+		ORDER BY `+order+limit, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales report: %w", err)
+	}
+	defer rows.Close()
+
+	report := model.SalesReport{GroupBy: query.GroupBy, Rows: make([]model.SalesReportRow, 0)}
+	for rows.Next() {
+		var row model.SalesReportRow
+		err := rows.Scan(&row.Period, &row.ID, &row.Name, &row.Quantity, &row.Transactions,
+			&row.Gross, &row.Discounts, &row.Net)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sales report row: %w", err)
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	err = tx.QueryRow(`SELECT `+salesReportSums+salesReportTables, query.StartDate, query.EndDate, query.StoreID).
+		Scan(&report.Totals.Quantity, &report.Totals.Transactions, &report.Totals.Gross,
+			&report.Totals.Discounts, &report.Totals.Net)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales report totals: %w", err)
+	}
+
+	return &report, nil
+}
+
+// StreamTransactions calls fn for each sale of a date range in order, as
+// the rows are read from the database cursor. A storeID of 0 covers all
+// stores.
+func (repo *TransactionRepositoryImpl) StreamTransactions(ctx context.Context, startDate, endDate time.Time, storeID int,
+	fn func(model.Transaction) error) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT t.id, t.store_id, t.currency, t.total_amount, t.discount_amount, COALESCE(t.coupon_code, ''),
+			t.rounding_amount, COALESCE((SELECT SUM(p.tendered - p.amount) FROM payments p WHERE p.transaction_id = t.id), 0),
+			COALESCE(t.expired_override_by, ''), t.created_at
+		FROM transactions t
+		WHERE t.created_at >= session_timestamp($1) AND t.created_at < session_timestamp($2) AND ($3 = 0 OR t.store_id = $3)
+		ORDER BY t.created_at, t.id
+	`, startDate, endDate, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.Transaction
+		err := rows.Scan(&t.ID, &t.StoreID, &t.Currency, &t.TotalAmount, &t.DiscountAmount, &t.CouponCode,
+			&t.RoundingAmount, &t.Change, &t.ExpiredOverrideBy, &t.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// StreamTransactionDetails calls fn for each sold line of a date range
+// with the sale it belongs to, as the rows are read from the database
+// cursor. Only the ID, store, currency and time of the sale are set.
+func (repo *TransactionRepositoryImpl) StreamTransactionDetails(ctx context.Context, startDate, endDate time.Time, storeID int,
+	fn func(model.Transaction, model.TransactionDetail) error) error {
+	tx, err := beginTx(ctx, repo.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT t.id, t.store_id, t.currency, t.created_at,
+			td.id, td.product_id, p.name, p.parent_id, td.quantity, p.unit, td.subtotal, td.discount,
+			COALESCE(td.pack_name, ''), td.pack_quantity
+		FROM transaction_details td
+		JOIN transactions t ON t.id = td.transaction_id
+		JOIN products p ON p.id = td.product_id
+		WHERE t.created_at >= session_timestamp($1) AND t.created_at < session_timestamp($2) AND ($3 = 0 OR t.store_id = $3)
+		ORDER BY t.created_at, t.id, td.id
+	`, startDate, endDate, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction details: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.Transaction
+		var d model.TransactionDetail
+		err := rows.Scan(&t.ID, &t.StoreID, &t.Currency, &t.CreatedAt,
+			&d.ID, &d.ProductID, &d.ProductName, &d.ParentID, &d.Quantity, &d.Unit, &d.Subtotal, &d.Discount,
+			&d.Pack, &d.PackQuantity)
+		if err != nil {
+			return fmt.Errorf("failed to scan detail: %w", err)
+		}
+		d.TransactionID = t.ID
+		if err := fn(t, d); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func getTransactionDetails(tx *sql.Tx, transactionID int) ([]model.TransactionDetail, error) {
 	// Get transaction details
-	rows, err := repo.db.Query(`
-		SELECT td.id, td.product_id, p.name, td.quantity, td.subtotal
+	rows, err := tx.Query(`
+		SELECT td.id, td.product_id, p.name, p.parent_id, td.quantity, p.unit, td.subtotal, td.discount,
+			COALESCE(td.pack_name, ''), td.pack_quantity
 		FROM transaction_details td
 		JOIN products p ON td.product_id = p.id
 		WHERE td.transaction_id = $1
@@ -225,15 +603,196 @@ func (repo *TransactionRepositoryImpl) getTransactionDetails(transactionID int)
 	var details []model.TransactionDetail
 	for rows.Next() {
 		var detail model.TransactionDetail
-		err := rows.Scan(&detail.ID, &detail.ProductID, &detail.ProductName,
-			&detail.Quantity, &detail.Subtotal)
+		err := rows.Scan(&detail.ID, &detail.ProductID, &detail.ProductName, &detail.ParentID,
+			&detail.Quantity, &detail.Unit, &detail.Subtotal, &detail.Discount, &detail.Pack, &detail.PackQuantity)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan detail: %w", err)
 		}
 		detail.TransactionID = transactionID
 		details = append(details, detail)
 	}
+	rows.Close()
+
+	// Attach the lots each line consumed
+	lotRows, err := tx.Query(`
+		SELECT tdl.transaction_detail_id, tdl.lot_id, sl.batch_number, sl.expiry_date, tdl.quantity
+		FROM transaction_detail_lots tdl
+		JOIN stock_lots sl ON sl.id = tdl.lot_id
+		JOIN transaction_details td ON td.id = tdl.transaction_detail_id
+		WHERE td.transaction_id = $1
+		ORDER BY tdl.id
+	`, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction detail lots: %w", err)
+	}
+	defer lotRows.Close()
+
+	for lotRows.Next() {
+		var detailID int
+		var lot model.TransactionDetailLot
+		if err := lotRows.Scan(&detailID, &lot.LotID, &lot.BatchNumber, &lot.ExpiryDate, &lot.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan detail lot: %w", err)
+		}
+		for i := range details {
+			if details[i].ID == detailID {
+				details[i].Lots = append(details[i].Lots, lot)
+			}
+		}
+	}
+	lotRows.Close()
+
+	// Attach what composite lines took from their components
+	componentRows, err := tx.Query(`
+		SELECT tdc.transaction_detail_id, tdc.product_id, p.name, tdc.quantity, p.unit
+		FROM transaction_detail_components tdc
+		JOIN products p ON p.id = tdc.product_id
+		JOIN transaction_details td ON td.id = tdc.transaction_detail_id
+		WHERE td.transaction_id = $1
+		ORDER BY tdc.id
+	`, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction detail components: %w", err)
+	}
+	defer componentRows.Close()
+
+	for componentRows.Next() {
+		var detailID int
+		var component model.ProductComponent
+		err := componentRows.Scan(&detailID, &component.ProductID, &component.Name, &component.Quantity, &component.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan detail component: %w", err)
+		}
+		for i := range details {
+			if details[i].ID == detailID {
+				details[i].Components = append(details[i].Components, component)
+			}
+		}
+	}
+	componentRows.Close()
+
+	// Attach the modifiers picked for each line
+	modifierRows, err := tx.Query(`
+		SELECT tdm.transaction_detail_id, COALESCE(tdm.modifier_id, 0), tdm.group_name, tdm.name, tdm.price_delta
+		FROM transaction_detail_modifiers tdm
+		JOIN transaction_details td ON td.id = tdm.transaction_detail_id
+		WHERE td.transaction_id = $1
+		ORDER BY tdm.id
+	`, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction detail modifiers: %w", err)
+	}
+	defer modifierRows.Close()
+
+	for modifierRows.Next() {
+		var detailID int
+		var modifier model.TransactionDetailModifier
+		err := modifierRows.Scan(&detailID, &modifier.ModifierID, &modifier.GroupName, &modifier.Name, &modifier.PriceDelta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan detail modifier: %w", err)
+		}
+		for i := range details {
+			if details[i].ID == detailID {
+				details[i].Modifiers = append(details[i].Modifiers, modifier)
+			}
+		}
+	}
+	modifierRows.Close()
+
+	// Attach the gift cards gift card lines issued
+	giftCardRows, err := tx.Query(`
+		SELECT `+giftCardColumns+`
+		FROM gift_cards
+		WHERE transaction_detail_id IN (SELECT id FROM transaction_details WHERE transaction_id = $1)
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction detail gift cards: %w", err)
+	}
+	defer giftCardRows.Close()
+
+	for giftCardRows.Next() {
+		var card model.GiftCard
+		if err := scanGiftCard(giftCardRows.Scan, &card); err != nil {
+			return nil, fmt.Errorf("failed to scan detail gift card: %w", err)
+		}
+		for i := range details {
+			if card.SoldIn != nil && details[i].ID == *card.SoldIn {
+				details[i].GiftCards = append(details[i].GiftCards, card)
+			}
+		}
+	}
 
 	return details, nil
 
 }
+
+// priceLine prices a checkout item at the given unit price and returns the
+// quantity to take from stock in the product's unit. A price label fixes
+// the amount, packs are priced as a pack, and modifiers change the price of
+// every unit or pack of the line.
+func priceLine(tx *sql.Tx, item model.CheckoutItem, productName string, productPrice int) (decimal.Decimal, *decimal.Decimal, int, error) {
+	quantity := item.Quantity
+	subtotal := quantity.Amount(productPrice)
+	if item.Amount != nil {
+		subtotal = *item.Amount
+	}
+
+	var packQuantity *decimal.Decimal
+	if item.Pack != "" {
+		pack, err := getProductPack(tx, item.ProductID, item.Pack)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+		if !item.Quantity.IsInteger() {
+			return 0, nil, 0, fmt.Errorf("quantity %s must be a whole number of %s", item.Quantity, pack.Name)
+		}
+		packs := item.Quantity
+		packQuantity = &packs
+		quantity = item.Quantity.Mul(pack.Factor)
+		subtotal = quantity.Amount(productPrice)
+		if pack.Price > 0 {
+			subtotal = item.Quantity.Amount(pack.Price)
+		}
+	}
+
+	modifierPrice := 0
+	for _, modifier := range item.Modifiers {
+		modifierPrice += modifier.PriceDelta
+	}
+	subtotal += item.Quantity.Amount(modifierPrice)
+	if subtotal < 0 {
+		return 0, nil, 0, fmt.Errorf("modifiers take the price of %s below zero", productName)
+	}
+	return quantity, packQuantity, subtotal, nil
+}
+
+// takeSaleStock takes a sold quantity of a product from the store, first
+// from the lots that expire first, and records the sale in the ledger
+func takeSaleStock(tx *sql.Tx, productID, storeID int, productName string, stock, quantity decimal.Decimal,
+	today model.Date, allowExpired bool, transactionID int) ([]model.TransactionDetailLot, error) {
+	// Check if we have enough stock
+	if stock < quantity {
+		return nil, fmt.Errorf("insufficient stock for product %s. Available: %s, Requested: %s",
+			productName, stock, quantity)
+	}
+
+	// Take the quantity from the lots that expire first
+	lots, err := consumeStockLots(tx, productID, storeID, stock, quantity, today, allowExpired)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrease product stock through the ledger
+	err = recordStockMovement(tx, &model.StockMovement{
+		ProductID:     productID,
+		StoreID:       storeID,
+		Type:          model.MovementSale,
+		Quantity:      -quantity,
+		ReferenceType: "transaction",
+		ReferenceID:   &transactionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-cashier-api/internal/testutil"
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/tenant"
+)
+
+// TestTransactionsByStoreDay checks that a store day is cut at midnight in
+// the store's timezone, whatever the timezone of the database session
+func TestTransactionsByStoreDay(t *testing.T) {
+	db := testutil.DB(t)
+	repo := NewTransactionRepository(db)
+	tn := testutil.CreateTenant(t, db, "tenant-wib", "transactions", "stores")
+	ctx := tenant.WithID(context.Background(), tn.ID)
+
+	wib, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("load Asia/Jakarta: %v", err)
+	}
+	lateSale := time.Date(2026, 5, 12, 23, 30, 0, 0, wib)
+	earlySale := time.Date(2026, 5, 13, 0, 30, 0, 0, wib)
+
+	tx, err := beginTx(ctx, db)
+	if err != nil {
+		t.Fatalf("beginTx: %v", err)
+	}
+	defer tx.Rollback()
+
+	var storeID int
+	err = tx.QueryRow("INSERT INTO stores (name, timezone) VALUES ('WIB store', 'Asia/Jakarta') RETURNING id").Scan(&storeID)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	// Written as NOW() would have written them at that instant
+	for amount, at := range map[int]time.Time{1000: lateSale, 2000: earlySale} {
+		_, err := tx.Exec("INSERT INTO transactions (store_id, total_amount, created_at) VALUES ($1, $2, session_timestamp($3))",
+			storeID, amount, at)
+		if err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		day    int
+		amount int
+	}{
+		{"23:30 WIB is on the 12th", 12, 1000},
+		{"00:30 WIB is on the 13th", 13, 2000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2026, 5, tc.day, 0, 0, 0, 0, wib)
+			end := start.AddDate(0, 0, 1)
+
+			transactions, _, revenue, _, _, err := repo.GetTransactionsByDate(ctx, start, end, storeID, false)
+			if err != nil {
+				t.Fatalf("GetTransactionsByDate: %v", err)
+			}
+			if len(transactions) != 1 || transactions[0].TotalAmount != tc.amount {
+				t.Fatalf("transactions = %+v, want only the sale of %d", transactions, tc.amount)
+			}
+			if revenue != tc.amount {
+				t.Errorf("revenue = %d, want %d", revenue, tc.amount)
+			}
+
+			var streamed []model.Transaction
+			err = repo.StreamTransactions(ctx, start, end, storeID, func(tr model.Transaction) error {
+				streamed = append(streamed, tr)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("StreamTransactions: %v", err)
+			}
+			if len(streamed) != 1 || streamed[0].TotalAmount != tc.amount {
+				t.Errorf("streamed = %+v, want only the sale of %d", streamed, tc.amount)
+			}
+		})
+	}
+}
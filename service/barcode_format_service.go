@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/barcode"
+	"go-cashier-api/pkg/decimal"
+	"go-cashier-api/repository"
+)
+
+type BarcodeFormatService interface {
+	GetAll(ctx context.Context) ([]model.BarcodeFormat, error)
+	Create(ctx context.Context, format *model.BarcodeFormat) error
+	Update(ctx context.Context, id int, format *model.BarcodeFormat) error
+	Delete(ctx context.Context, id int) error
+}
+
+type BarcodeFormatServiceImpl struct {
+	repo repository.BarcodeFormatRepository
+}
+
+func NewBarcodeFormatService(repo repository.BarcodeFormatRepository) BarcodeFormatService {
+	return &BarcodeFormatServiceImpl{repo: repo}
+}
+
+func (s *BarcodeFormatServiceImpl) GetAll(ctx context.Context) ([]model.BarcodeFormat, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *BarcodeFormatServiceImpl) Create(ctx context.Context, format *model.BarcodeFormat) error {
+	if err := validateBarcodeFormat(format); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, format)
+}
+
+func (s *BarcodeFormatServiceImpl) Update(ctx context.Context, id int, format *model.BarcodeFormat) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("barcode format not found")
+	}
+
+	// The layout is replaced as a whole, an empty name keeps the existing one
+	format.ID = id
+	if strings.TrimSpace(format.Name) == "" {
+		format.Name = existing.Name
+	}
+	if err := validateBarcodeFormat(format); err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.repo.Update(ctx, format)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("failed to update barcode format")
+	}
+
+	return nil
+}
+
+func (s *BarcodeFormatServiceImpl) Delete(ctx context.Context, id int) error {
+	rowsAffected, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("barcode format not found")
+	}
+
+	return nil
+}
+
+func validateBarcodeFormat(format *model.BarcodeFormat) error {
+	format.Name = strings.TrimSpace(format.Name)
+	format.Prefix = strings.TrimSpace(format.Prefix)
+	if format.Name == "" {
+		return errors.New("barcode format name is required")
+	}
+
+	return toBarcodeFormat(*format).Validate()
+}
+
+func toBarcodeFormat(f model.BarcodeFormat) barcode.Format {
+	return barcode.Format{
+		Prefix:        f.Prefix,
+		CodeLength:    f.CodeLength,
+		ValueType:     f.ValueType,
+		ValueLength:   f.ValueLength,
+		ValueDecimals: f.ValueDecimals,
+	}
+}
+
+// scanBarcode finds the product behind a scanned code. A barcode assigned
+// to a product wins; otherwise the code is matched against the in-store
+// formats, whose item code is the product's PLU. A weight label becomes a
+// quantity priced at the product price, a price label an amount and the
+// quantity it buys. It returns nil when nothing matches.
+func scanBarcode(ctx context.Context, productRepo repository.ProductRepository,
+	formatRepo repository.BarcodeFormatRepository, code string) (*model.ScannedProduct, error) {
+	normalized, err := barcode.Normalize(code)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := productRepo.GetByBarcode(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if product != nil {
+		scanned := &model.ScannedProduct{Product: *product}
+		for i := range product.Packs {
+			if product.Packs[i].Barcode == normalized {
+				scanned.Pack = &product.Packs[i]
+			}
+		}
+		return scanned, nil
+	}
+
+	formats, err := formatRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range formats {
+		format := toBarcodeFormat(f)
+		embedded, ok := format.Parse(normalized)
+		if !ok {
+			continue
+		}
+
+		product, err := productRepo.GetByPLU(ctx, embedded.Code)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, fmt.Errorf("product with plu %d not found", embedded.Code)
+		}
+
+		var quantity decimal.Decimal
+		var amount int
+		switch format.ValueType {
+		case barcode.ValueWeight:
+			quantity = decimal.FromScaled(embedded.Value, format.ValueDecimals)
+			amount = quantity.Amount(product.Price)
+		case barcode.ValuePrice:
+			if product.Price <= 0 {
+				return nil, fmt.Errorf("product %s has no price to weigh a price label against", product.Name)
+			}
+			amount = int(embedded.Value)
+			places, _ := model.UnitPrecision(product.Unit)
+			quantity = decimal.Div(amount, product.Price).Round(places)
+		}
+		if quantity <= 0 {
+			return nil, fmt.Errorf("barcode %s carries no quantity", code)
+		}
+
+		return &model.ScannedProduct{Product: *product, Quantity: &quantity, Amount: &amount}, nil
+	}
+
+	return nil, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -9,11 +10,11 @@ import (
 )
 
 type CategoryService interface {
-	GetAll() ([]model.Category, error)
-	GetByID(id int) (*model.Category, error)
-	Create(category *model.Category) error
-	Update(id int, category *model.Category) error
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]model.Category, error)
+	GetByID(ctx context.Context, id int) (*model.Category, error)
+	Create(ctx context.Context, category *model.Category) error
+	Update(ctx context.Context, id int, category *model.Category) error
+	Delete(ctx context.Context, id int) error
 }
 
 type CategoryServiceImpl struct {
@@ -24,8 +25,8 @@ func NewCategoryService(repo repository.CategoryRepository) CategoryService {
 	return &CategoryServiceImpl{repo: repo}
 }
 
-func (s *CategoryServiceImpl) GetAll() ([]model.Category, error) {
-	categories, err := s.repo.GetAll()
+func (s *CategoryServiceImpl) GetAll(ctx context.Context) ([]model.Category, error) {
+	categories, err := s.repo.GetAll(ctx)
 
 	if err != nil {
 		return nil, err
@@ -34,20 +35,21 @@ func (s *CategoryServiceImpl) GetAll() ([]model.Category, error) {
 	return categories, nil
 }
 
-func (s *CategoryServiceImpl) Create(category *model.Category) error {
+func (s *CategoryServiceImpl) Create(ctx context.Context, category *model.Category) error {
 	// Business validation
 	if strings.TrimSpace(category.Name) == "" {
 		return errors.New("category name is required")
 	}
+	category.Station = normalizeStation(category.Station)
 
 	// Check for duplicate name (business rule)
 	// ...
 
-	return s.repo.Create(category)
+	return s.repo.Create(ctx, category)
 }
 
-func (s *CategoryServiceImpl) GetByID(id int) (*model.Category, error) {
-	category, err := s.repo.GetByID(id)
+func (s *CategoryServiceImpl) GetByID(ctx context.Context, id int) (*model.Category, error) {
+	category, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +61,9 @@ func (s *CategoryServiceImpl) GetByID(id int) (*model.Category, error) {
 	return category, nil
 }
 
-func (s *CategoryServiceImpl) Update(id int, category *model.Category) error {
+func (s *CategoryServiceImpl) Update(ctx context.Context, id int, category *model.Category) error {
 	// 1. Get existing
-	existing, err := s.repo.GetByID(id)
+	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -83,12 +85,17 @@ func (s *CategoryServiceImpl) Update(id int, category *model.Category) error {
 		updated = true
 	}
 
+	if station := normalizeStation(category.Station); station != existing.Station {
+		existing.Station = station
+		updated = true
+	}
+
 	// 3. Save if changes were made
 	if !updated {
 		return nil // No changes needed
 	}
 
-	rowsAffected, err := s.repo.Update(existing)
+	rowsAffected, err := s.repo.Update(ctx, existing)
 	if err != nil {
 		return err
 	}
@@ -100,7 +107,7 @@ func (s *CategoryServiceImpl) Update(id int, category *model.Category) error {
 	return nil
 }
 
-func (s *CategoryServiceImpl) Delete(id int) error {
+func (s *CategoryServiceImpl) Delete(ctx context.Context, id int) error {
 	// 1. Consider implementing a soft delete pattern
 	//    (add DeletedAt field to your model)
 
@@ -116,7 +123,7 @@ func (s *CategoryServiceImpl) Delete(id int) error {
 	// }
 
 	// 3. Single attempt with proper error handling
-	rowsAffected, err := s.repo.Delete(id)
+	rowsAffected, err := s.repo.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -132,3 +139,9 @@ func (s *CategoryServiceImpl) Delete(id int) error {
 
 	return nil
 }
+
+// normalizeStation trims and lowercases a station name so "Bar" and "bar "
+// share one queue
+func normalizeStation(station string) string {
+	return strings.ToLower(strings.TrimSpace(station))
+}
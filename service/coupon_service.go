@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type CouponService interface {
+	GetAll(ctx context.Context) ([]model.Coupon, error)
+	GetByID(ctx context.Context, id int) (*model.Coupon, error)
+	Create(ctx context.Context, coupon *model.Coupon) error
+	Update(ctx context.Context, id int, coupon *model.Coupon) error
+	Delete(ctx context.Context, id int) error
+}
+
+type CouponServiceImpl struct {
+	repo repository.CouponRepository
+}
+
+func NewCouponService(repo repository.CouponRepository) CouponService {
+	return &CouponServiceImpl{repo: repo}
+}
+
+func (s *CouponServiceImpl) GetAll(ctx context.Context) ([]model.Coupon, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *CouponServiceImpl) GetByID(ctx context.Context, id int) (*model.Coupon, error) {
+	coupon, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if coupon == nil {
+		return nil, errors.New("coupon not found")
+	}
+
+	return coupon, nil
+}
+
+func (s *CouponServiceImpl) Create(ctx context.Context, coupon *model.Coupon) error {
+	if err := validateCoupon(coupon); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, coupon)
+}
+
+func (s *CouponServiceImpl) Update(ctx context.Context, id int, coupon *model.Coupon) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("coupon not found")
+	}
+
+	// The coupon is replaced as a whole, an empty code keeps the existing one
+	coupon.ID = id
+	if strings.TrimSpace(coupon.Code) == "" {
+		coupon.Code = existing.Code
+	}
+	if err := validateCoupon(coupon); err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.repo.Update(ctx, coupon)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("failed to update coupon")
+	}
+
+	coupon.TimesUsed = existing.TimesUsed
+	coupon.CreatedAt = existing.CreatedAt
+	return nil
+}
+
+func (s *CouponServiceImpl) Delete(ctx context.Context, id int) error {
+	rowsAffected, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("coupon not found")
+	}
+
+	return nil
+}
+
+func validateCoupon(coupon *model.Coupon) error {
+	coupon.Code = normalizeCouponCode(coupon.Code)
+	coupon.Description = strings.TrimSpace(coupon.Description)
+	if coupon.Code == "" {
+		return errors.New("coupon code is required")
+	}
+	if strings.ContainsAny(coupon.Code, " \t") {
+		return errors.New("coupon code cannot contain spaces")
+	}
+
+	switch coupon.DiscountType {
+	case model.DiscountPercent:
+		if coupon.DiscountValue < 1 || coupon.DiscountValue > 100 {
+			return errors.New("percent discount_value must be between 1 and 100")
+		}
+	case model.DiscountFixed:
+		if coupon.DiscountValue <= 0 {
+			return errors.New("fixed discount_value must be greater than 0")
+		}
+	default:
+		return errors.New("discount_type must be percent or fixed")
+	}
+
+	if coupon.MinSpend < 0 || coupon.UsageLimit < 0 || coupon.PerCustomerLimit < 0 {
+		return errors.New("min_spend, usage_limit and per_customer_limit cannot be negative")
+	}
+	if coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	if coupon.ProductIDs == nil {
+		coupon.ProductIDs = []int{}
+	}
+	if coupon.CategoryIDs == nil {
+		coupon.CategoryIDs = []int{}
+	}
+	return nil
+}
+
+// normalizeCouponCode makes codes case-insensitive, HEMAT10 and hemat10
+// are the same coupon
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
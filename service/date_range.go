@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+// maxRangeDays is the longest range a report covers, a year including a
+// leap day
+const maxRangeDays = 366
+
+// parseDateRange parses a start and end and returns the half-open range
+// [start, end) they cover. A date is a whole day in loc, so an end date
+// includes that day; an RFC 3339 timestamp is taken as is.
+func parseDateRange(startDateStr, endDateStr string, loc *time.Location) (time.Time, time.Time, error) {
+	startDate, err := parseRangeBound(startDateStr, loc, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format. Use YYYY-MM-DD or RFC 3339")
+	}
+
+	endDate, err := parseRangeBound(endDateStr, loc, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format. Use YYYY-MM-DD or RFC 3339")
+	}
+
+	if !endDate.After(startDate) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date must be after start date")
+	}
+	if endDate.After(startDate.AddDate(0, 0, maxRangeDays)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range must not exceed %d days", maxRangeDays)
+	}
+
+	return startDate, endDate, nil
+}
+
+// parseRangeBound parses one end of a range. The end of a date range is
+// the midnight that follows it; AddDate keeps that right across DST
+// changes, where a day is not 24 hours.
+func parseRangeBound(s string, loc *time.Location, end bool) (time.Time, error) {
+	if len(s) != len(model.DateLayout) {
+		return time.Parse(time.RFC3339, s)
+	}
+
+	date, err := time.ParseInLocation(model.DateLayout, s, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+// dayRange returns the half-open range of the day t falls on in loc
+func dayRange(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// storeLocation returns the timezone of a store, or of the first store
+// when storeID is 0 as sales default to it
+func storeLocation(ctx context.Context, stores repository.StoreRepository, storeID int) (*time.Location, error) {
+	timezone := model.DefaultTimezone
+	if storeID != 0 {
+		store, err := stores.GetByID(ctx, storeID)
+		if err != nil {
+			return nil, err
+		}
+		if store == nil {
+			return nil, fmt.Errorf("store id %d not found", storeID)
+		}
+		timezone = store.Timezone
+	} else {
+		all, err := stores.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(all) > 0 {
+			timezone = all[0].Timezone
+		}
+	}
+
+	return time.LoadLocation(timezone)
+}
+
+// inLocation relabels a wall-clock time read from a timestamp column as
+// being in loc, e.g. a report period bucketed in store time
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseDateRange(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		loc       *time.Location
+		wantStart string // RFC 3339
+		wantEnd   string
+		wantErr   string
+	}{
+		{
+			name:  "single day",
+			start: "2026-05-12", end: "2026-05-12", loc: jakarta,
+			wantStart: "2026-05-12T00:00:00+07:00", wantEnd: "2026-05-13T00:00:00+07:00",
+		},
+		{
+			name:  "month boundary",
+			start: "2026-01-31", end: "2026-02-01", loc: jakarta,
+			wantStart: "2026-01-31T00:00:00+07:00", wantEnd: "2026-02-02T00:00:00+07:00",
+		},
+		{
+			name:  "end of February",
+			start: "2026-02-28", end: "2026-02-28", loc: jakarta,
+			wantStart: "2026-02-28T00:00:00+07:00", wantEnd: "2026-03-01T00:00:00+07:00",
+		},
+		{
+			name:  "leap day",
+			start: "2024-02-29", end: "2024-02-29", loc: jakarta,
+			wantStart: "2024-02-29T00:00:00+07:00", wantEnd: "2024-03-01T00:00:00+07:00",
+		},
+		{
+			name:  "no leap day in a common year",
+			start: "2023-02-29", end: "2023-03-01", loc: jakarta,
+			wantErr: "invalid start date",
+		},
+		{
+			name:  "year boundary",
+			start: "2025-12-31", end: "2026-01-01", loc: jakarta,
+			wantStart: "2025-12-31T00:00:00+07:00", wantEnd: "2026-01-02T00:00:00+07:00",
+		},
+		{
+			name:  "DST starts, 23 hour day",
+			start: "2026-03-08", end: "2026-03-08", loc: newYork,
+			wantStart: "2026-03-08T00:00:00-05:00", wantEnd: "2026-03-09T00:00:00-04:00",
+		},
+		{
+			name:  "DST ends, 25 hour day",
+			start: "2026-11-01", end: "2026-11-01", loc: newYork,
+			wantStart: "2026-11-01T00:00:00-04:00", wantEnd: "2026-11-02T00:00:00-05:00",
+		},
+		{
+			name:  "across the spring change",
+			start: "2026-03-07", end: "2026-03-09", loc: newYork,
+			wantStart: "2026-03-07T00:00:00-05:00", wantEnd: "2026-03-10T00:00:00-04:00",
+		},
+		{
+			name:  "across the autumn change",
+			start: "2026-10-31", end: "2026-11-02", loc: newYork,
+			wantStart: "2026-10-31T00:00:00-04:00", wantEnd: "2026-11-03T00:00:00-05:00",
+		},
+		{
+			name:  "timestamps are taken as is",
+			start: "2026-03-08T01:30:00-05:00", end: "2026-03-08T03:30:00-04:00", loc: newYork,
+			wantStart: "2026-03-08T06:30:00Z", wantEnd: "2026-03-08T07:30:00Z",
+		},
+		{
+			name:  "366 days in a leap year",
+			start: "2024-01-01", end: "2024-12-31", loc: jakarta,
+			wantStart: "2024-01-01T00:00:00+07:00", wantEnd: "2025-01-01T00:00:00+07:00",
+		},
+		{
+			name:  "366 days across DST",
+			start: "2026-01-01", end: "2027-01-01", loc: newYork,
+			wantStart: "2026-01-01T00:00:00-05:00", wantEnd: "2027-01-02T00:00:00-05:00",
+		},
+		{
+			name:  "367 days",
+			start: "2025-01-01", end: "2026-01-02", loc: jakarta,
+			wantErr: "must not exceed 366 days",
+		},
+		{
+			name:  "start after end",
+			start: "2026-03-10", end: "2026-03-09", loc: jakarta,
+			wantErr: "end date must be after start date",
+		},
+		{
+			name:  "empty timestamp range",
+			start: "2026-03-10T10:00:00Z", end: "2026-03-10T10:00:00Z", loc: jakarta,
+			wantErr: "end date must be after start date",
+		},
+		{
+			name:  "invalid end",
+			start: "2026-03-10", end: "10/03/2026", loc: jakarta,
+			wantErr: "invalid end date",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := parseDateRange(tc.start, tc.end, tc.loc)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			wantStart, _ := time.Parse(time.RFC3339, tc.wantStart)
+			wantEnd, _ := time.Parse(time.RFC3339, tc.wantEnd)
+			if !start.Equal(wantStart) {
+				t.Errorf("start = %s, want %s", start.Format(time.RFC3339), tc.wantStart)
+			}
+			if !end.Equal(wantEnd) {
+				t.Errorf("end = %s, want %s", end.Format(time.RFC3339), tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestDayRange(t *testing.T) {
+	jakarta := mustLoadLocation(t, "Asia/Jakarta")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name      string
+		at        string // RFC 3339
+		loc       *time.Location
+		wantStart string
+		wantHours float64
+	}{
+		{"evening UTC is the next day in Jakarta", "2026-01-31T20:00:00Z", jakarta, "2026-02-01T00:00:00+07:00", 24},
+		{"new year in Jakarta", "2025-12-31T17:00:00Z", jakarta, "2026-01-01T00:00:00+07:00", 24},
+		{"leap day", "2024-02-29T12:00:00+07:00", jakarta, "2024-02-29T00:00:00+07:00", 24},
+		{"DST starts", "2026-03-08T12:00:00-04:00", newYork, "2026-03-08T00:00:00-05:00", 23},
+		{"DST ends", "2026-11-01T12:00:00-05:00", newYork, "2026-11-01T00:00:00-04:00", 25},
+		{"just before midnight", "2026-11-01T23:59:59-05:00", newYork, "2026-11-01T00:00:00-04:00", 25},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			at, _ := time.Parse(time.RFC3339, tc.at)
+			wantStart, _ := time.Parse(time.RFC3339, tc.wantStart)
+
+			start, end := dayRange(at, tc.loc)
+			if !start.Equal(wantStart) {
+				t.Errorf("start = %s, want %s", start.Format(time.RFC3339), tc.wantStart)
+			}
+			if hours := end.Sub(start).Hours(); hours != tc.wantHours {
+				t.Errorf("day is %v hours, want %v", hours, tc.wantHours)
+			}
+		})
+	}
+}
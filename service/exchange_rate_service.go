@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+	"go-cashier-api/repository"
+)
+
+type ExchangeRateService interface {
+	GetAll(ctx context.Context, currency string) ([]model.ExchangeRate, error)
+	Create(ctx context.Context, rate *model.ExchangeRate) error
+}
+
+type ExchangeRateServiceImpl struct {
+	repo repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(repo repository.ExchangeRateRepository) ExchangeRateService {
+	return &ExchangeRateServiceImpl{repo: repo}
+}
+
+func (s *ExchangeRateServiceImpl) GetAll(ctx context.Context, currency string) ([]model.ExchangeRate, error) {
+	return s.repo.GetAll(ctx, strings.ToUpper(strings.TrimSpace(currency)))
+}
+
+// Create records a rate. Rates are never changed, a correction is a new
+// rate effective from the time it applies.
+func (s *ExchangeRateServiceImpl) Create(ctx context.Context, rate *model.ExchangeRate) error {
+	rate.Currency = strings.ToUpper(strings.TrimSpace(rate.Currency))
+	rate.BaseCurrency = strings.ToUpper(strings.TrimSpace(rate.BaseCurrency))
+	if rate.BaseCurrency == "" {
+		rate.BaseCurrency = model.CurrencyIDR
+	}
+
+	for _, currency := range []string{rate.Currency, rate.BaseCurrency} {
+		if _, ok := model.MinorUnits(currency); !ok {
+			return fmt.Errorf("unknown currency %q", currency)
+		}
+	}
+	if rate.Currency == rate.BaseCurrency {
+		return errors.New("currency and base_currency must differ")
+	}
+	// Rates are rounded to decimal.RatePlaces when read, a tiny rate may
+	// round to 0
+	if rate.Rate <= 0 {
+		return fmt.Errorf("rate must be greater than 0 at %d decimal places", decimal.RatePlaces)
+	}
+	if rate.EffectiveFrom.IsZero() {
+		rate.EffectiveFrom = time.Now()
+	}
+
+	return s.repo.Create(ctx, rate)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/export"
+	"go-cashier-api/repository"
+)
+
+// Reports that can be exported, named as their /api/reports route
+const (
+	ReportSales                     = "sales"
+	ReportShrinkage                 = "shrinkage"
+	ReportBundleSales               = "bundle-sales"
+	ReportReorderSuggestions        = "reorder-suggestions"
+	ReportExpiringLots              = "expiring-lots"
+	ReportOutstandingPurchaseOrders = "outstanding-purchase-orders"
+)
+
+// Column headers of the exports. Columns are only ever added at the end so
+// spreadsheets built on an export keep working.
+var (
+	transactionColumns = []string{"id", "created_at", "store_id", "currency", "total_amount", "discount_amount",
+		"coupon_code", "rounding_amount", "change", "expired_override_by"}
+	transactionDetailColumns = []string{"transaction_id", "created_at", "store_id", "currency", "line_id",
+		"product_id", "product_name", "parent_id", "quantity", "unit", "pack", "pack_quantity", "subtotal", "discount", "net"}
+	reportColumns = map[string][]string{
+		ReportSales:     {"period", "id", "name", "quantity", "transactions", "gross", "discounts", "net"},
+		ReportShrinkage: {"period", "reason_code", "reason_name", "quantity", "value"},
+		ReportBundleSales: {"product_id", "name", "quantity_sold", "revenue",
+			"component_id", "component_name", "component_quantity", "component_unit"},
+		ReportReorderSuggestions: {"product_id", "name", "stock", "min_stock", "reorder_qty",
+			"average_daily_sales", "days_of_cover", "suggested_quantity"},
+		ReportExpiringLots: {"lot_id", "product_id", "product_name", "store_id", "batch_number",
+			"expiry_date", "quantity", "days_left"},
+		ReportOutstandingPurchaseOrders: {"supplier_id", "supplier_name", "open_orders",
+			"outstanding_quantity", "outstanding_value"},
+	}
+)
+
+// ExportService writes sales and reports as CSV or XLSX
+type ExportService interface {
+	Transactions(ctx context.Context, query model.ExportQuery, w io.Writer) error
+	TransactionDetails(ctx context.Context, query model.ExportQuery, w io.Writer) error
+	Report(ctx context.Context, name string, query model.ExportQuery, w io.Writer) error
+}
+
+type ExportServiceImpl struct {
+	transactionRepo repository.TransactionRepository
+	storeRepo       repository.StoreRepository
+	transactions    TransactionService
+	inventory       InventoryService
+	purchaseOrders  PurchaseOrderService
+}
+
+// NewExportService creates a new instance of ExportService
+func NewExportService(transactionRepo repository.TransactionRepository,
+	storeRepo repository.StoreRepository,
+	transactions TransactionService,
+	inventory InventoryService,
+	purchaseOrders PurchaseOrderService) ExportService {
+	return &ExportServiceImpl{
+		transactionRepo: transactionRepo,
+		storeRepo:       storeRepo,
+		transactions:    transactions,
+		inventory:       inventory,
+		purchaseOrders:  purchaseOrders,
+	}
+}
+
+// Transactions writes the sales of a date range, one row per sale. Rows are
+// written as they are read from the database, so a large range is never
+// held in memory. The file is started with the first row, so nothing is
+// written to w when the query fails before returning one.
+func (s *ExportServiceImpl) Transactions(ctx context.Context, query model.ExportQuery, w io.Writer) error {
+	loc, startDate, endDate, err := s.exportRange(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	writer, err := export.NewWriter(w, query.Format, query.Locale, "Transactions", transactionColumns)
+	if err != nil {
+		return err
+	}
+	err = s.transactionRepo.StreamTransactions(ctx, startDate, endDate, query.StoreID, func(t model.Transaction) error {
+		return writer.Write([]interface{}{t.ID, t.CreatedAt.In(loc), t.StoreID, t.Currency, t.TotalAmount,
+			t.DiscountAmount, t.CouponCode, t.RoundingAmount, t.Change, t.ExpiredOverrideBy})
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// TransactionDetails writes the sold lines of a date range, streamed like
+// Transactions. Net is the subtotal less the line's share of the coupon
+// discount.
+func (s *ExportServiceImpl) TransactionDetails(ctx context.Context, query model.ExportQuery, w io.Writer) error {
+	loc, startDate, endDate, err := s.exportRange(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	writer, err := export.NewWriter(w, query.Format, query.Locale, "Transaction details", transactionDetailColumns)
+	if err != nil {
+		return err
+	}
+	err = s.transactionRepo.StreamTransactionDetails(ctx, startDate, endDate, query.StoreID,
+		func(t model.Transaction, d model.TransactionDetail) error {
+			return writer.Write([]interface{}{t.ID, t.CreatedAt.In(loc), t.StoreID, t.Currency, d.ID,
+				d.ProductID, d.ProductName, d.ParentID, d.Quantity, d.Unit, d.Pack, d.PackQuantity,
+				d.Subtotal, d.Discount, d.Subtotal - d.Discount})
+		})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// Report writes one of the reports with the same rows as its JSON endpoint.
+// Reports are aggregates bounded by the number of periods, products or
+// suppliers, so they are built by the report services and then written.
+func (s *ExportServiceImpl) Report(ctx context.Context, name string, query model.ExportQuery, w io.Writer) error {
+	columns, ok := reportColumns[name]
+	if !ok {
+		return errors.New("report not found")
+	}
+	if err := export.Check(query.Format, query.Locale); err != nil {
+		return err
+	}
+
+	rows, err := s.reportRows(ctx, name, query)
+	if err != nil {
+		return err
+	}
+
+	writer, err := export.NewWriter(w, query.Format, query.Locale, name, columns)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// reportRows runs a report and returns its rows in the order of its columns
+func (s *ExportServiceImpl) reportRows(ctx context.Context, name string, query model.ExportQuery) ([][]interface{}, error) {
+	var rows [][]interface{}
+
+	switch name {
+	case ReportSales:
+		report, err := s.transactions.GetSalesReport(ctx, query.StartDate, query.EndDate, model.SalesReportQuery{
+			GroupBy: query.GroupBy,
+			StoreID: query.StoreID,
+			Top:     query.Top,
+			Bottom:  query.Bottom,
+			RollUp:  query.RollUp,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range report.Rows {
+			rows = append(rows, []interface{}{r.Period, r.ID, r.Name, r.Quantity, r.Transactions,
+				r.Gross, r.Discounts, r.Net})
+		}
+
+	case ReportShrinkage:
+		report, err := s.inventory.GetShrinkageReport(ctx, query.StartDate, query.EndDate, query.Period, query.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range report {
+			rows = append(rows, []interface{}{r.Period, r.ReasonCode, r.ReasonName, r.Quantity, r.Value})
+		}
+
+	case ReportBundleSales:
+		report, err := s.inventory.GetBundleSalesReport(ctx, query.StartDate, query.EndDate, query.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		// One row per component, repeating the bundle
+		for _, r := range report {
+			for _, c := range r.Components {
+				rows = append(rows, []interface{}{r.ProductID, r.Name, r.QuantitySold, r.Revenue,
+					c.ProductID, c.Name, c.Quantity, c.Unit})
+			}
+		}
+
+	case ReportReorderSuggestions:
+		report, err := s.inventory.GetReorderSuggestions(ctx, query.Days, query.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range report {
+			rows = append(rows, []interface{}{r.ProductID, r.Name, r.Stock, r.MinStock, r.ReorderQty,
+				r.AverageDailySales, r.DaysOfCover, r.SuggestedQuantity})
+		}
+
+	case ReportExpiringLots:
+		report, err := s.inventory.GetExpiringLots(ctx, query.Days, query.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range report {
+			rows = append(rows, []interface{}{r.LotID, r.ProductID, r.ProductName, r.StoreID, r.BatchNumber,
+				r.ExpiryDate.Format(model.DateLayout), r.Quantity, r.DaysLeft})
+		}
+
+	case ReportOutstandingPurchaseOrders:
+		report, err := s.purchaseOrders.GetOutstanding(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range report {
+			rows = append(rows, []interface{}{r.SupplierID, r.SupplierName, r.OpenOrders,
+				r.OutstandingQuantity, r.OutstandingValue})
+		}
+	}
+
+	return rows, nil
+}
+
+// exportRange checks the format and returns the store timezone and the
+// date range of an export
+func (s *ExportServiceImpl) exportRange(ctx context.Context, query model.ExportQuery) (*time.Location, time.Time, time.Time, error) {
+	if err := export.Check(query.Format, query.Locale); err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	loc, err := storeLocation(ctx, s.storeRepo, query.StoreID)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	startDate, endDate, err := parseDateRange(query.StartDate, query.EndDate, loc)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	return loc, startDate, endDate, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type GiftCardService interface {
+	GetAll(ctx context.Context) ([]model.GiftCard, error)
+	Lookup(ctx context.Context, code string) (*model.GiftCard, error)
+	Issue(ctx context.Context, card *model.GiftCard) error
+}
+
+type GiftCardServiceImpl struct {
+	repo repository.GiftCardRepository
+}
+
+func NewGiftCardService(repo repository.GiftCardRepository) GiftCardService {
+	return &GiftCardServiceImpl{repo: repo}
+}
+
+func (s *GiftCardServiceImpl) GetAll(ctx context.Context) ([]model.GiftCard, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Lookup returns a gift card by its code with its balance ledger
+func (s *GiftCardServiceImpl) Lookup(ctx context.Context, code string) (*model.GiftCard, error) {
+	code = normalizeGiftCardCode(code)
+	if code == "" {
+		return nil, errors.New("gift card code is required")
+	}
+
+	card, err := s.repo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if card == nil {
+		return nil, errors.New("gift card not found")
+	}
+
+	return card, nil
+}
+
+// Issue creates a store-credit voucher outside of a sale, e.g. for a
+// refund. Its code is generated.
+func (s *GiftCardServiceImpl) Issue(ctx context.Context, card *model.GiftCard) error {
+	if card.Code != "" {
+		return errors.New("gift card codes are generated")
+	}
+	if card.InitialBalance <= 0 {
+		return errors.New("gift card initial balance must be positive")
+	}
+	if card.ValidityDays < 0 {
+		return errors.New("validity days cannot be negative")
+	}
+
+	if err := s.repo.Create(ctx, card); err != nil {
+		return err
+	}
+
+	issued, err := s.Lookup(ctx, card.Code)
+	if err != nil {
+		return err
+	}
+	*card = *issued
+	return nil
+}
+
+// normalizeGiftCardCode uppercases a code and drops the spaces and dashes
+// it may be printed or typed with
+func normalizeGiftCardCode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(strings.TrimSpace(code)))
+}
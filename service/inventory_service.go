@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+	"go-cashier-api/repository"
+)
+
+// InventoryService interface defines the methods for stock operations
+type InventoryService interface {
+	GetMovements(ctx context.Context, productID, storeID int) ([]model.StockMovement, error)
+	GetAdjustmentReasons(ctx context.Context) ([]model.AdjustmentReason, error)
+	CreateAdjustmentReason(ctx context.Context, reason *model.AdjustmentReason) error
+	CreateAdjustment(ctx context.Context, adjustment *model.StockAdjustment) error
+	GetShrinkageReport(ctx context.Context, startDateStr, endDateStr, period string, storeID int) ([]model.ShrinkageReportRow, error)
+	GetBundleSalesReport(ctx context.Context, startDateStr, endDateStr string, storeID int) ([]model.BundleSalesRow, error)
+	GetLowStock(ctx context.Context, storeID int) ([]model.LowStockProduct, error)
+	GetReorderSuggestions(ctx context.Context, days, storeID int) ([]model.ReorderSuggestion, error)
+	GetLots(ctx context.Context, productID, storeID int) ([]model.StockLot, error)
+	CreateLot(ctx context.Context, lot *model.StockLot, createdBy string) error
+	GetExpiringLots(ctx context.Context, days, storeID int) ([]model.ExpiringLot, error)
+}
+
+type InventoryServiceImpl struct {
+	movementRepo   repository.StockMovementRepository
+	adjustmentRepo repository.StockAdjustmentRepository
+	lotRepo        repository.StockLotRepository
+	productRepo    repository.ProductRepository
+	storeRepo      repository.StoreRepository
+}
+
+// NewInventoryService creates a new instance of InventoryService
+func NewInventoryService(movementRepo repository.StockMovementRepository,
+	adjustmentRepo repository.StockAdjustmentRepository,
+	lotRepo repository.StockLotRepository,
+	productRepo repository.ProductRepository,
+	storeRepo repository.StoreRepository) InventoryService {
+	return &InventoryServiceImpl{
+		movementRepo:   movementRepo,
+		adjustmentRepo: adjustmentRepo,
+		lotRepo:        lotRepo,
+		productRepo:    productRepo,
+		storeRepo:      storeRepo,
+	}
+}
+
+// GetMovements returns the stock ledger of a single product, optionally
+// limited to one store
+func (s *InventoryServiceImpl) GetMovements(ctx context.Context, productID, storeID int) ([]model.StockMovement, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	return s.movementRepo.GetByProductID(ctx, productID, storeID)
+}
+
+// GetAdjustmentReasons returns the configured adjustment reason codes
+func (s *InventoryServiceImpl) GetAdjustmentReasons(ctx context.Context) ([]model.AdjustmentReason, error) {
+	return s.adjustmentRepo.GetReasons(ctx)
+}
+
+// CreateAdjustmentReason adds a new adjustment reason code
+func (s *InventoryServiceImpl) CreateAdjustmentReason(ctx context.Context, reason *model.AdjustmentReason) error {
+	reason.Code = strings.ToLower(strings.TrimSpace(reason.Code))
+	if reason.Code == "" {
+		return errors.New("reason code is required")
+	}
+
+	if strings.TrimSpace(reason.Name) == "" {
+		return errors.New("reason name is required")
+	}
+
+	if reason.Direction != model.DirectionIn && reason.Direction != model.DirectionOut {
+		return errors.New("reason direction must be in or out")
+	}
+
+	existing, err := s.adjustmentRepo.GetReasonByCode(ctx, reason.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("reason code already exists")
+	}
+
+	return s.adjustmentRepo.CreateReason(ctx, reason)
+}
+
+// CreateAdjustment validates and applies a multi-line stock adjustment
+func (s *InventoryServiceImpl) CreateAdjustment(ctx context.Context, adjustment *model.StockAdjustment) error {
+	if len(adjustment.Lines) == 0 {
+		return errors.New("lines cannot be empty")
+	}
+
+	for _, line := range adjustment.Lines {
+		if line.ProductID <= 0 {
+			return errors.New("invalid product id")
+		}
+		if line.Quantity <= 0 {
+			return fmt.Errorf("quantity must be greater than 0 for product id %d", line.ProductID)
+		}
+	}
+
+	reason, err := s.adjustmentRepo.GetReasonByCode(ctx, adjustment.ReasonCode)
+	if err != nil {
+		return err
+	}
+	if reason == nil {
+		return errors.New("reason code not found")
+	}
+
+	return s.adjustmentRepo.Create(ctx, adjustment, reason.Direction)
+}
+
+// GetShrinkageReport returns adjustments per reason and period in a date range
+func (s *InventoryServiceImpl) GetShrinkageReport(ctx context.Context, startDateStr, endDateStr, period string, storeID int) ([]model.ShrinkageReportRow, error) {
+	loc, err := storeLocation(ctx, s.storeRepo, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := parseDateRange(startDateStr, endDateStr, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	if period == "" {
+		period = "month"
+	}
+	if period != "day" && period != "week" && period != "month" {
+		return nil, errors.New("invalid period. Use day, week or month")
+	}
+
+	report, err := s.adjustmentRepo.GetShrinkage(ctx, startDate, endDate, period, loc.String(), storeID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range report {
+		report[i].Period = inLocation(report[i].Period, loc)
+	}
+	return report, nil
+}
+
+// GetBundleSalesReport returns the composite products sold in a date range
+// with what they took from their components
+func (s *InventoryServiceImpl) GetBundleSalesReport(ctx context.Context, startDateStr, endDateStr string, storeID int) ([]model.BundleSalesRow, error) {
+	loc, err := storeLocation(ctx, s.storeRepo, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := parseDateRange(startDateStr, endDateStr, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.productRepo.GetBundleSales(ctx, startDate, endDate, storeID)
+}
+
+// GetLowStock returns products at or below their reorder point in a store
+func (s *InventoryServiceImpl) GetLowStock(ctx context.Context, storeID int) ([]model.LowStockProduct, error) {
+	return s.productRepo.GetLowStock(ctx, storeID)
+}
+
+// GetReorderSuggestions estimates days of cover from the average daily sales
+// over the last days and suggests what to reorder. Only products that are
+// low on stock or would run out within that many days are returned. With a
+// storeID both stock and sales are that store's, otherwise every store's.
+func (s *InventoryServiceImpl) GetReorderSuggestions(ctx context.Context, days, storeID int) ([]model.ReorderSuggestion, error) {
+	if days <= 0 {
+		return nil, errors.New("days must be greater than 0")
+	}
+
+	var storeStock map[int]decimal.Decimal
+	if storeID != 0 {
+		store, err := s.storeRepo.GetByID(ctx, storeID)
+		if err != nil {
+			return nil, err
+		}
+		if store == nil {
+			return nil, fmt.Errorf("store id %d not found", storeID)
+		}
+		storeStock, err = s.productRepo.GetStoreStock(ctx, storeID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parents, err := s.productRepo.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock is kept per variant, so those are reordered rather than the
+	// parent, and composite products are restocked through their components
+	products := make([]model.Product, 0, len(parents))
+	for _, p := range append(parents, flattenVariants(parents)...) {
+		if len(p.Variants) == 0 && len(p.Components) == 0 {
+			products = append(products, p)
+		}
+	}
+
+	sales, err := s.productRepo.GetSalesSince(ctx, time.Now().AddDate(0, 0, -days), storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]model.ReorderSuggestion, 0)
+	for _, p := range products {
+		if storeStock != nil {
+			p.Stock = storeStock[p.ID]
+		}
+		suggestion := model.ReorderSuggestion{
+			ProductID:         p.ID,
+			Name:              p.Name,
+			Stock:             p.Stock,
+			MinStock:          p.MinStock,
+			ReorderQty:        p.ReorderQty,
+			AverageDailySales: sales[p.ID].Float64() / float64(days),
+		}
+
+		lowStock := p.MinStock > 0 && p.Stock <= p.MinStock
+		runsOut := false
+		if suggestion.AverageDailySales > 0 {
+			cover := p.Stock.Float64() / suggestion.AverageDailySales
+			suggestion.DaysOfCover = &cover
+			runsOut = cover < float64(days)
+		}
+		if !lowStock && !runsOut {
+			continue
+		}
+
+		// Cover the same number of days again, but never less than the
+		// configured reorder quantity. Round up to what the unit allows.
+		places, _ := model.UnitPrecision(p.Unit)
+		needed := decimal.FromFloat(suggestion.AverageDailySales*float64(days) - p.Stock.Float64()).RoundUp(places)
+		suggestion.SuggestedQuantity = max(needed, p.ReorderQty, 0)
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
+
+// GetLots returns the lots of a product that still hold stock
+func (s *InventoryServiceImpl) GetLots(ctx context.Context, productID, storeID int) ([]model.StockLot, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	return s.lotRepo.GetByProductID(ctx, productID, storeID)
+}
+
+// CreateLot receives stock into a new lot
+func (s *InventoryServiceImpl) CreateLot(ctx context.Context, lot *model.StockLot, createdBy string) error {
+	if strings.TrimSpace(lot.BatchNumber) == "" {
+		return errors.New("batch number is required")
+	}
+
+	if lot.Quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, lot.ProductID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return errors.New("product does not exist")
+	}
+
+	return s.lotRepo.Create(ctx, lot, createdBy)
+}
+
+// GetExpiringLots returns lots expiring within the given number of days,
+// together with lots that already expired
+func (s *InventoryServiceImpl) GetExpiringLots(ctx context.Context, days, storeID int) ([]model.ExpiringLot, error) {
+	if days < 0 {
+		return nil, errors.New("days cannot be negative")
+	}
+
+	// Expiry dates are dates, today is the date at the store
+	loc, err := storeLocation(ctx, s.storeRepo, storeID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return s.lotRepo.GetExpiring(ctx, model.Date{Time: today}, model.Date{Time: today.AddDate(0, 0, days+1)}, storeID)
+}
+
+// flattenVariants returns the variants of all the given parent products
+func flattenVariants(parents []model.Product) []model.Product {
+	var variants []model.Product
+	for _, p := range parents {
+		variants = append(variants, p.Variants...)
+	}
+	return variants
+}
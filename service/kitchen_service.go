@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/event"
+	"go-cashier-api/pkg/tenant"
+	"go-cashier-api/repository"
+)
+
+type KitchenService interface {
+	GetQueue(ctx context.Context, station, status string) ([]model.KitchenItem, error)
+	Bump(ctx context.Context, id int) (*model.KitchenItem, error)
+	Recall(ctx context.Context, id int) (*model.KitchenItem, error)
+	Subscribe(ctx context.Context, station string) (<-chan model.KitchenItem, func())
+}
+
+type KitchenServiceImpl struct {
+	repo   repository.KitchenRepository
+	events *event.Bus // Kitchen displays subscribe to item changes
+}
+
+func NewKitchenService(repo repository.KitchenRepository, events *event.Bus) KitchenService {
+	return &KitchenServiceImpl{
+		repo:   repo,
+		events: events,
+	}
+}
+
+// GetQueue returns the items of a station oldest first. Without a status
+// it returns everything not yet served.
+func (s *KitchenServiceImpl) GetQueue(ctx context.Context, station, status string) ([]model.KitchenItem, error) {
+	if status != "" && kitchenStep(status) < 0 {
+		return nil, fmt.Errorf("invalid status %q", status)
+	}
+
+	return s.repo.GetQueue(ctx, normalizeStation(station), status)
+}
+
+// Bump moves an item one step on, e.g. from preparing to ready
+func (s *KitchenServiceImpl) Bump(ctx context.Context, id int) (*model.KitchenItem, error) {
+	return s.move(ctx, id, 1, "bumped")
+}
+
+// Recall moves an item one step back, e.g. when it was bumped by mistake
+func (s *KitchenServiceImpl) Recall(ctx context.Context, id int) (*model.KitchenItem, error) {
+	return s.move(ctx, id, -1, "recalled")
+}
+
+func (s *KitchenServiceImpl) move(ctx context.Context, id, step int, action string) (*model.KitchenItem, error) {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, errors.New("kitchen item not found")
+	}
+
+	next := kitchenStep(item.Status) + step
+	if next < 0 || next >= len(model.KitchenStatuses) {
+		return nil, fmt.Errorf("kitchen item with status %s cannot be %s", item.Status, action)
+	}
+
+	rowsAffected, err := s.repo.UpdateStatus(ctx, id, item.Status, model.KitchenStatuses[next])
+	if err != nil {
+		return nil, err
+	}
+
+	// Status changed between reading and updating
+	if rowsAffected == 0 {
+		return nil, errors.New("kitchen item was modified, please retry")
+	}
+
+	item, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	publishKitchenItems(ctx, s.events, []model.KitchenItem{*item})
+	return item, nil
+}
+
+// Subscribe returns the items of a station as they are routed or change
+// status, until the returned function is called. Items are dropped when the
+// subscriber falls behind; the display can poll the queue to catch up.
+func (s *KitchenServiceImpl) Subscribe(ctx context.Context, station string) (<-chan model.KitchenItem, func()) {
+	tenantID, _ := tenant.FromContext(ctx)
+	station = normalizeStation(station)
+
+	items := make(chan model.KitchenItem, 64)
+	unsubscribe := s.events.Subscribe(event.KitchenItem, func(e event.Event) {
+		item, ok := e.Payload.(model.KitchenItem)
+		if !ok || item.TenantID != tenantID || item.Station != station {
+			return
+		}
+		select {
+		case items <- item:
+		default:
+		}
+	})
+	return items, unsubscribe
+}
+
+func kitchenStep(status string) int {
+	for i, s := range model.KitchenStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// publishKitchenItems tells subscribed displays about routed or changed
+// items, tagged with the tenant they belong to
+func publishKitchenItems(ctx context.Context, events *event.Bus, items []model.KitchenItem) {
+	tenantID, _ := tenant.FromContext(ctx)
+	for _, item := range items {
+		item.TenantID = tenantID
+		events.Publish(event.KitchenItem, item)
+	}
+}
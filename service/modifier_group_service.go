@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type ModifierGroupService interface {
+	GetAll(ctx context.Context) ([]model.ModifierGroup, error)
+	GetByID(ctx context.Context, id int) (*model.ModifierGroup, error)
+	Create(ctx context.Context, group *model.ModifierGroup) error
+	Update(ctx context.Context, id int, group *model.ModifierGroup) error
+	Delete(ctx context.Context, id int) error
+}
+
+type ModifierGroupServiceImpl struct {
+	repo repository.ModifierGroupRepository
+}
+
+func NewModifierGroupService(repo repository.ModifierGroupRepository) ModifierGroupService {
+	return &ModifierGroupServiceImpl{repo: repo}
+}
+
+func (s *ModifierGroupServiceImpl) GetAll(ctx context.Context) ([]model.ModifierGroup, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *ModifierGroupServiceImpl) GetByID(ctx context.Context, id int) (*model.ModifierGroup, error) {
+	group, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if group == nil {
+		return nil, errors.New("modifier group not found")
+	}
+
+	return group, nil
+}
+
+func (s *ModifierGroupServiceImpl) Create(ctx context.Context, group *model.ModifierGroup) error {
+	for i := range group.Modifiers {
+		group.Modifiers[i].ID = 0
+	}
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, group)
+}
+
+func (s *ModifierGroupServiceImpl) Update(ctx context.Context, id int, group *model.ModifierGroup) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("modifier group not found")
+	}
+
+	// The group is replaced as a whole, an empty name keeps the existing one
+	group.ID = id
+	if strings.TrimSpace(group.Name) == "" {
+		group.Name = existing.Name
+	}
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.repo.Update(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("failed to update modifier group")
+	}
+
+	return nil
+}
+
+func (s *ModifierGroupServiceImpl) Delete(ctx context.Context, id int) error {
+	rowsAffected, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("modifier group not found")
+	}
+
+	return nil
+}
+
+func validateModifierGroup(group *model.ModifierGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		return errors.New("modifier group name is required")
+	}
+
+	if group.MinSelect < 0 || group.MaxSelect < 0 {
+		return errors.New("min_select and max_select cannot be negative")
+	}
+	if group.MaxSelect > 0 && group.MinSelect > group.MaxSelect {
+		return errors.New("min_select cannot be more than max_select")
+	}
+	if group.MinSelect > len(group.Modifiers) {
+		return fmt.Errorf("modifier group %s needs at least %d modifiers to choose from", group.Name, group.MinSelect)
+	}
+
+	names := make(map[string]bool)
+	for i := range group.Modifiers {
+		modifier := &group.Modifiers[i]
+		modifier.Name = strings.TrimSpace(modifier.Name)
+		if modifier.Name == "" {
+			return errors.New("modifier name is required")
+		}
+		if names[strings.ToLower(modifier.Name)] {
+			return fmt.Errorf("modifier %s is listed twice", modifier.Name)
+		}
+		names[strings.ToLower(modifier.Name)] = true
+	}
+
+	if group.Modifiers == nil {
+		group.Modifiers = make([]model.Modifier, 0)
+	}
+	return nil
+}
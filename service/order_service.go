@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/event"
+	"go-cashier-api/repository"
+)
+
+type OrderService interface {
+	GetAll(ctx context.Context, status string, storeID int) ([]model.Order, error)
+	GetByID(ctx context.Context, id int) (*model.Order, error)
+	Create(ctx context.Context, order *model.Order) error
+	AddLine(ctx context.Context, orderID int, line *model.OrderLine) (*model.Order, error)
+	UpdateLine(ctx context.Context, orderID, lineID int, line *model.OrderLine) (*model.Order, error)
+	DeleteLine(ctx context.Context, orderID, lineID int) (*model.Order, error)
+	Hold(ctx context.Context, id int) (*model.Order, error)
+	Recall(ctx context.Context, id int) (*model.Order, error)
+	Cancel(ctx context.Context, id int) (*model.Order, error)
+	Checkout(ctx context.Context, id int, request model.CheckoutRequest) (*model.TransactionResponse, error)
+	Split(ctx context.Context, id int, request model.OrderSplitRequest, createdBy string) (*model.Order, error)
+	PayShare(ctx context.Context, id, number int, tenders []model.Tender) (*model.Order, error)
+	Send(ctx context.Context, id int) (*model.Order, error)
+}
+
+type OrderServiceImpl struct {
+	repo         repository.OrderRepository
+	productRepo  repository.ProductRepository
+	formatRepo   repository.BarcodeFormatRepository
+	rounding     repository.RoundingRuleRepository // Shares are rounded like sales
+	transactions TransactionService                // Checks out orders like any other sale
+	events       *event.Bus                        // Kitchen displays subscribe to routed lines
+}
+
+func NewOrderService(repo repository.OrderRepository, productRepo repository.ProductRepository,
+	formatRepo repository.BarcodeFormatRepository, rounding repository.RoundingRuleRepository,
+	transactions TransactionService, events *event.Bus) OrderService {
+	return &OrderServiceImpl{
+		repo:         repo,
+		productRepo:  productRepo,
+		formatRepo:   formatRepo,
+		rounding:     rounding,
+		transactions: transactions,
+		events:       events,
+	}
+}
+
+func (s *OrderServiceImpl) GetAll(ctx context.Context, status string, storeID int) ([]model.Order, error) {
+	return s.repo.GetAll(ctx, status, storeID)
+}
+
+func (s *OrderServiceImpl) GetByID(ctx context.Context, id int) (*model.Order, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+
+	return order, nil
+}
+
+// Create opens an empty order
+func (s *OrderServiceImpl) Create(ctx context.Context, order *model.Order) error {
+	order.TableName = strings.TrimSpace(order.TableName)
+	if len(order.Lines) > 0 {
+		return errors.New("lines are added to an order one at a time")
+	}
+
+	if err := s.repo.Create(ctx, order); err != nil {
+		return err
+	}
+
+	created, err := s.GetByID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	*order = *created
+	return nil
+}
+
+// AddLine adds a product to an open order. Barcodes, packs and modifiers
+// are checked the same way as at checkout.
+func (s *OrderServiceImpl) AddLine(ctx context.Context, orderID int, line *model.OrderLine) (*model.Order, error) {
+	if line.Barcode != "" {
+		if line.ProductID != 0 {
+			return nil, errors.New("line must have either product_id or barcode, not both")
+		}
+
+		scanned, err := scanBarcode(ctx, s.productRepo, s.formatRepo, line.Barcode)
+		if err != nil {
+			return nil, err
+		}
+		if scanned == nil {
+			return nil, fmt.Errorf("product with barcode %s not found", line.Barcode)
+		}
+		line.ProductID = scanned.ID
+		if scanned.Pack != nil {
+			line.Pack = scanned.Pack.Name
+		}
+		if scanned.Quantity != nil {
+			line.Quantity = *scanned.Quantity
+			line.Amount = scanned.Amount
+		}
+	}
+
+	if line.ProductID <= 0 {
+		return nil, errors.New("invalid product id")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, line.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product id %d not found", line.ProductID)
+	}
+	if len(product.Variants) > 0 {
+		return nil, fmt.Errorf("product %s has variants, add one of them instead", product.Name)
+	}
+
+	if line.Pack != "" {
+		found := false
+		for _, pack := range product.Packs {
+			if pack.Name == line.Pack {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("product %s has no pack %s", product.Name, line.Pack)
+		}
+	}
+
+	if err := s.checkLine(product, line); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddLine(ctx, orderID, line); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, orderID)
+}
+
+// UpdateLine changes the quantity, modifiers or notes of a line. The
+// product and pack of a line stay fixed, a different one is a new line.
+func (s *OrderServiceImpl) UpdateLine(ctx context.Context, orderID, lineID int, line *model.OrderLine) (*model.Order, error) {
+	if line.ProductID != 0 || line.Barcode != "" || line.Pack != "" {
+		return nil, errors.New("product and pack of a line are fixed, add a new line instead")
+	}
+
+	order, err := s.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing *model.OrderLine
+	for i := range order.Lines {
+		if order.Lines[i].ID == lineID {
+			existing = &order.Lines[i]
+			break
+		}
+	}
+	if existing == nil {
+		return nil, errors.New("order line not found")
+	}
+	if existing.SentAt != nil {
+		return nil, errors.New("order line sent to its station cannot be changed, remove it and add a new line")
+	}
+
+	// A price label fixes the quantity as well as the amount
+	if existing.Amount != nil && line.Quantity != 0 && line.Quantity != existing.Quantity {
+		return nil, errors.New("quantity of a price-labelled line is fixed by its label")
+	}
+	if line.Quantity == 0 {
+		line.Quantity = existing.Quantity
+	}
+	if line.ModifierIDs == nil {
+		line.ModifierIDs = existing.ModifierIDs
+	}
+
+	product, err := s.productRepo.GetByID(ctx, existing.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product id %d not found", existing.ProductID)
+	}
+
+	line.ID = lineID
+	if err := s.checkLine(product, line); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateLine(ctx, orderID, line); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, orderID)
+}
+
+func (s *OrderServiceImpl) checkLine(product *model.Product, line *model.OrderLine) error {
+	if line.Quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+
+	if line.ModifierIDs == nil {
+		line.ModifierIDs = []int{}
+	}
+	_, err := model.SelectModifiers(product.Name, product.ModifierGroups, line.ModifierIDs)
+	return err
+}
+
+func (s *OrderServiceImpl) DeleteLine(ctx context.Context, orderID, lineID int) (*model.Order, error) {
+	if err := s.repo.DeleteLine(ctx, orderID, lineID); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, orderID)
+}
+
+// Hold parks an open order so the till can serve the next customer
+func (s *OrderServiceImpl) Hold(ctx context.Context, id int) (*model.Order, error) {
+	return s.transition(ctx, id, []string{model.OrderOpen}, model.OrderHeld, "held")
+}
+
+// Recall reopens a held order
+func (s *OrderServiceImpl) Recall(ctx context.Context, id int) (*model.Order, error) {
+	return s.transition(ctx, id, []string{model.OrderHeld}, model.OrderOpen, "recalled")
+}
+
+// Cancel discards an order without touching stock. An order cannot be
+// cancelled while orders split off it are still open.
+func (s *OrderServiceImpl) Cancel(ctx context.Context, id int) (*model.Order, error) {
+	order, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, childID := range order.ChildIDs {
+		child, err := s.GetByID(ctx, childID)
+		if err != nil {
+			return nil, err
+		}
+		if child.Status != model.OrderPaid && child.Status != model.OrderCancelled {
+			return nil, fmt.Errorf("order with open split order %d cannot be cancelled", childID)
+		}
+	}
+
+	return s.transition(ctx, id, []string{model.OrderOpen, model.OrderHeld}, model.OrderCancelled, "cancelled")
+}
+
+func (s *OrderServiceImpl) transition(ctx context.Context, id int, from []string, to, action string) (*model.Order, error) {
+	order, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, status := range from {
+		if order.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("order with status %s cannot be %s", order.Status, action)
+	}
+
+	rowsAffected, err := s.repo.UpdateStatus(ctx, id, order.Status, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status changed between reading and updating
+	if rowsAffected == 0 {
+		return nil, errors.New("order was modified, please retry")
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Checkout sells the lines of an open or held order through the regular
+// checkout, which prices them, takes the stock and marks the order paid.
+// The order is locked and its status and lines read in the same database
+// transaction, so a line changed meanwhile is never sold or left out.
+// Only the override, coupon and tenders of the request are used.
+func (s *OrderServiceImpl) Checkout(ctx context.Context, id int, options model.CheckoutRequest) (*model.TransactionResponse, error) {
+	return s.transactions.Checkout(ctx, model.CheckoutRequest{
+		OverrideExpiredBy: options.OverrideExpiredBy,
+		Tenders:           options.Tenders,
+		CouponCode:        options.CouponCode,
+		Customer:          options.Customer,
+		OrderID:           id,
+		Shares:            options.Shares,
+	})
+}
+
+// Split divides an open order. With line IDs the lines move into a new
+// order for the same table that is paid on its own. With shares the order
+// is sold now and its total divided into that many equal shares, each
+// paid with its own tenders.
+func (s *OrderServiceImpl) Split(ctx context.Context, id int, request model.OrderSplitRequest, createdBy string) (*model.Order, error) {
+	if len(request.LineIDs) > 0 == (request.Shares != 0) {
+		return nil, errors.New("split by either line_ids or shares")
+	}
+
+	if request.Shares != 0 {
+		if request.Shares < 2 {
+			return nil, errors.New("shares must be at least 2")
+		}
+		if _, err := s.Checkout(ctx, id, model.CheckoutRequest{Shares: request.Shares}); err != nil {
+			return nil, err
+		}
+		return s.GetByID(ctx, id)
+	}
+
+	seen := make(map[int]bool, len(request.LineIDs))
+	for _, lineID := range request.LineIDs {
+		if seen[lineID] {
+			return nil, fmt.Errorf("line id %d appears more than once", lineID)
+		}
+		seen[lineID] = true
+	}
+
+	childID, err := s.repo.SplitLines(ctx, id, request.LineIDs, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, childID)
+}
+
+// PayShare pays one share of an order split into equal shares
+func (s *OrderServiceImpl) PayShare(ctx context.Context, id, number int, tenders []model.Tender) (*model.Order, error) {
+	if len(tenders) == 0 {
+		return nil, errors.New("tenders cannot be empty")
+	}
+	if err := checkTenders(tenders); err != nil {
+		return nil, err
+	}
+
+	rules, err := roundingRules(ctx, s.rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PayShare(ctx, id, number, tenders, rules); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Send routes the lines not sent yet to their preparation stations, lines
+// still unsent at checkout are sent then
+func (s *OrderServiceImpl) Send(ctx context.Context, id int) (*model.Order, error) {
+	items, err := s.repo.Send(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	publishKitchenItems(ctx, s.events, items)
+
+	return s.GetByID(ctx, id)
+}
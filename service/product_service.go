@@ -1,39 +1,47 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"go-cashier-api/model"
+	"go-cashier-api/pkg/barcode"
+	"go-cashier-api/pkg/decimal"
 	"go-cashier-api/repository"
 )
 
 // ProductService interface defines the methods for product service
 type ProductService interface {
-	GetAll(name string) ([]model.Product, error)
-	GetByID(id int) (*model.Product, error)
-	Create(product *model.Product) error
-	Update(id int, product *model.Product) error
-	Delete(id int) error
+	GetAll(ctx context.Context, name string) ([]model.Product, error)
+	GetByID(ctx context.Context, id int) (*model.Product, error)
+	Lookup(ctx context.Context, code, sku string) (*model.ScannedProduct, error)
+	Create(ctx context.Context, product *model.Product) error
+	Update(ctx context.Context, id int, request *model.UpdateProductRequest) error
+	Delete(ctx context.Context, id int) error
 }
 
 type ProductServiceImpl struct {
 	productRepo  repository.ProductRepository
 	categoryRepo repository.CategoryRepository
+	formatRepo   repository.BarcodeFormatRepository
 }
 
 // NewProductService creates a new instance of ProductService
 // this called at main.go to initialize the service with the repository
-func NewProductService(productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository) ProductService {
+func NewProductService(productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository,
+	formatRepo repository.BarcodeFormatRepository) ProductService {
 	return &ProductServiceImpl{
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
+		formatRepo:   formatRepo,
 	}
 }
 
 // GetAll retrieves all products using the repository
-func (s *ProductServiceImpl) GetAll(name string) ([]model.Product, error) {
-	products, err := s.productRepo.GetAll(name)
+func (s *ProductServiceImpl) GetAll(ctx context.Context, name string) ([]model.Product, error) {
+	products, err := s.productRepo.GetAll(ctx, name)
 
 	if err != nil {
 		return nil, err
@@ -43,13 +51,34 @@ func (s *ProductServiceImpl) GetAll(name string) ([]model.Product, error) {
 }
 
 // Create adds a new product using the repository
-func (s *ProductServiceImpl) Create(product *model.Product) error {
+func (s *ProductServiceImpl) Create(ctx context.Context, product *model.Product) error {
+	if product.ParentID != nil && *product.ParentID == 0 {
+		product.ParentID = nil
+	}
+
+	// A variant takes its unit and category from the parent and may leave
+	// its name and price to it
+	if product.ParentID != nil {
+		if err := s.prepareVariant(ctx, product); err != nil {
+			return err
+		}
+	} else {
+		attributes, err := normalizeVariantAttributes(product.VariantAttributes)
+		if err != nil {
+			return err
+		}
+		product.VariantAttributes = attributes
+		if len(product.Attributes) > 0 {
+			return errors.New("only variants have attribute values, set parent_id")
+		}
+	}
+
 	// Validate input
 	if strings.TrimSpace(product.Name) == "" {
 		return errors.New("product name is required")
 	}
 
-	if product.Price <= 0 {
+	if product.Price <= 0 && !product.InheritsPrice {
 		return errors.New("product price must be positive")
 	}
 
@@ -57,19 +86,59 @@ func (s *ProductServiceImpl) Create(product *model.Product) error {
 		return errors.New("product stock cannot be negative")
 	}
 
+	if product.MinStock < 0 || product.ReorderQty < 0 {
+		return errors.New("product min stock and reorder quantity cannot be negative")
+	}
+
+	if product.PLU < 0 {
+		return errors.New("product plu cannot be negative")
+	}
+
+	if product.Unit == "" {
+		product.Unit = model.UnitPiece
+	}
+	if err := validateUnitQuantities(product); err != nil {
+		return err
+	}
+
+	product.SKU = strings.TrimSpace(product.SKU)
+	barcodes, err := normalizeBarcodes(product.Barcodes)
+	if err != nil {
+		return err
+	}
+	product.Barcodes = barcodes
+
+	if err := normalizePacks(product); err != nil {
+		return err
+	}
+
+	if len(product.Components) > 0 && product.Stock != 0 {
+		return errors.New("a composite product holds no stock of its own")
+	}
+	if err := s.checkComponents(ctx, product); err != nil {
+		return err
+	}
+
+	if product.Type == "" {
+		product.Type = model.ProductStandard
+	}
+	if err := checkProductType(product); err != nil {
+		return err
+	}
+
 	// Check if category exists
-	_, err := s.categoryRepo.GetByID(product.CategoryID)
+	_, err = s.categoryRepo.GetByID(ctx, product.CategoryID)
 	if err != nil {
 		return errors.New("category does not exist")
 	}
 
-	return s.productRepo.Create(product)
+	return s.productRepo.Create(ctx, product)
 
 }
 
 // GetByID retrieves a product by its ID using the repository
-func (s *ProductServiceImpl) GetByID(id int) (*model.Product, error) {
-	product, err := s.productRepo.GetByID(id)
+func (s *ProductServiceImpl) GetByID(ctx context.Context, id int) (*model.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -82,9 +151,42 @@ func (s *ProductServiceImpl) GetByID(id int) (*model.Product, error) {
 
 }
 
-// Update modifies an existing product using the repository
-func (s *ProductServiceImpl) Update(id int, product *model.Product) error {
-	existing, err := s.productRepo.GetByID(id)
+// Lookup finds a product by exact barcode or SKU, as a scanner needs.
+// Scale and other in-store labels also return the quantity and amount
+// they carry.
+func (s *ProductServiceImpl) Lookup(ctx context.Context, code, sku string) (*model.ScannedProduct, error) {
+	var scanned *model.ScannedProduct
+
+	switch {
+	case code != "":
+		var err error
+		scanned, err = scanBarcode(ctx, s.productRepo, s.formatRepo, code)
+		if err != nil {
+			return nil, err
+		}
+	case strings.TrimSpace(sku) != "":
+		product, err := s.productRepo.GetBySKU(ctx, strings.TrimSpace(sku))
+		if err != nil {
+			return nil, err
+		}
+		if product != nil {
+			scanned = &model.ScannedProduct{Product: *product}
+		}
+	default:
+		return nil, errors.New("barcode or sku is required")
+	}
+
+	if scanned == nil {
+		return nil, errors.New("product not found")
+	}
+
+	return scanned, nil
+}
+
+// Update modifies an existing product using the repository. Only the
+// fields sent in the request change.
+func (s *ProductServiceImpl) Update(ctx context.Context, id int, request *model.UpdateProductRequest) error {
+	existing, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -93,31 +195,145 @@ func (s *ProductServiceImpl) Update(id int, product *model.Product) error {
 		return errors.New("product not found")
 	}
 
+	// Stock only changes through the movement ledger
+	if request.Stock != nil {
+		return errors.New("stock cannot be set on a product, post a stock adjustment with a reason code instead")
+	}
+
 	// 2. Apply partial updates
 	updated := false
 
-	if strings.TrimSpace(product.Name) != "" && product.Name != existing.Name {
-		existing.Name = product.Name
+	if strings.TrimSpace(request.Name) != "" && request.Name != existing.Name {
+		existing.Name = request.Name
 		updated = true
 	}
 
-	if product.Price != existing.Price {
-		existing.Price = product.Price
+	// A variant set to price 0 goes back to the parent's price
+	if request.Price != nil && *request.Price != existing.Price {
+		existing.Price = *request.Price
+		existing.InheritsPrice = existing.ParentID != nil && *request.Price == 0
 		updated = true
 	}
 
-	if product.Stock != existing.Stock {
-		existing.Stock = product.Stock
+	if (request.MinStock != nil && *request.MinStock < 0) || (request.ReorderQty != nil && *request.ReorderQty < 0) {
+		return errors.New("product min stock and reorder quantity cannot be negative")
+	}
+
+	if request.MinStock != nil && *request.MinStock != existing.MinStock {
+		existing.MinStock = *request.MinStock
 		updated = true
 	}
 
-	if product.CategoryID > 0 {
+	if request.ReorderQty != nil && *request.ReorderQty != existing.ReorderQty {
+		existing.ReorderQty = *request.ReorderQty
+		updated = true
+	}
+
+	if request.PLU < 0 {
+		return errors.New("product plu cannot be negative")
+	}
+
+	if request.Unit != "" && request.Unit != existing.Unit {
+		if existing.ParentID != nil {
+			return errors.New("unit of a variant follows its parent")
+		}
+		if len(existing.Variants) > 0 {
+			return errors.New("unit of a product with variants cannot be changed")
+		}
+		existing.Unit = request.Unit
+		updated = true
+	}
+
+	if request.VariantAttributes != nil {
+		if existing.ParentID != nil {
+			return errors.New("only a parent product has variant attributes")
+		}
+		attributes, err := normalizeVariantAttributes(request.VariantAttributes)
+		if err != nil {
+			return err
+		}
+		if len(existing.Variants) > 0 && strings.Join(attributes, ",") != strings.Join(existing.VariantAttributes, ",") {
+			return errors.New("variant attributes cannot be changed while the product has variants")
+		}
+		existing.VariantAttributes = attributes
+		updated = true
+	}
+
+	if request.Attributes != nil {
+		if existing.ParentID == nil {
+			return errors.New("only variants have attribute values")
+		}
+		parent, err := s.productRepo.GetByID(ctx, *existing.ParentID)
+		if err != nil {
+			return err
+		}
+		if err := checkVariantValues(parent, request.Attributes); err != nil {
+			return err
+		}
+		existing.Attributes = request.Attributes
+		updated = true
+	}
+
+	if request.Type != "" && request.Type != existing.Type {
+		return errors.New("product type cannot be changed")
+	}
+
+	// Validity days left out keep the existing ones, 0 removes the expiry
+	if request.ValidityDays != nil {
+		existing.ValidityDays = request.ValidityDays
+		if *request.ValidityDays == 0 {
+			existing.ValidityDays = nil
+		}
+		updated = true
+	}
+
+	if request.PLU != 0 && request.PLU != existing.PLU {
+		existing.PLU = request.PLU
+		updated = true
+	}
+
+	if sku := strings.TrimSpace(request.SKU); sku != "" && sku != existing.SKU {
+		existing.SKU = sku
+		updated = true
+	}
+
+	// Barcodes left out keep the existing ones, an empty list removes them
+	if request.Barcodes != nil {
+		barcodes, err := normalizeBarcodes(request.Barcodes)
+		if err != nil {
+			return err
+		}
+		existing.Barcodes = barcodes
+		updated = true
+	}
+
+	// Packs and components follow the same rule as barcodes
+	if request.Packs != nil {
+		existing.Packs = request.Packs
+		updated = true
+	}
+
+	if request.ModifierGroupIDs != nil {
+		existing.ModifierGroupIDs = request.ModifierGroupIDs
+		updated = true
+	}
+
+	wasComposite := len(existing.Components) > 0
+	if request.Components != nil {
+		existing.Components = request.Components
+		updated = true
+	}
+
+	if request.CategoryID > 0 && request.CategoryID != existing.CategoryID {
+		if existing.ParentID != nil {
+			return errors.New("category of a variant follows its parent")
+		}
 		// Check if new category exists
-		_, err := s.categoryRepo.GetByID(product.CategoryID)
+		_, err := s.categoryRepo.GetByID(ctx, request.CategoryID)
 		if err != nil {
 			return errors.New("category does not exist")
 		}
-		existing.CategoryID = product.CategoryID
+		existing.CategoryID = request.CategoryID
 	}
 
 	// 3. Save if changes were made
@@ -125,7 +341,26 @@ func (s *ProductServiceImpl) Update(id int, product *model.Product) error {
 		return nil // No changes needed
 	}
 
-	rowsAffected, err := s.productRepo.Update(existing)
+	if err := validateUnitQuantities(existing); err != nil {
+		return err
+	}
+	if err := normalizePacks(existing); err != nil {
+		return err
+	}
+
+	// The stock shown for a composite product is what its components allow,
+	// a product only becomes one once its own stock is gone
+	if len(existing.Components) > 0 && !wasComposite && existing.Stock != 0 {
+		return errors.New("a composite product holds no stock of its own, remove its stock first")
+	}
+	if err := s.checkComponents(ctx, existing); err != nil {
+		return err
+	}
+	if err := checkProductType(existing); err != nil {
+		return err
+	}
+
+	rowsAffected, err := s.productRepo.Update(ctx, existing)
 	if err != nil {
 		return err
 	}
@@ -138,8 +373,8 @@ func (s *ProductServiceImpl) Update(id int, product *model.Product) error {
 }
 
 // Delete removes a product by its ID using the repository
-func (s *ProductServiceImpl) Delete(id int) error {
-	rowsAffected, err := s.productRepo.Delete(id)
+func (s *ProductServiceImpl) Delete(ctx context.Context, id int) error {
+	rowsAffected, err := s.productRepo.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -153,3 +388,243 @@ func (s *ProductServiceImpl) Delete(id int) error {
 
 	return nil
 }
+
+// normalizeBarcodes validates barcodes and drops duplicates
+func normalizeBarcodes(codes []string) ([]string, error) {
+	barcodes := make([]string, 0, len(codes))
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		normalized, err := barcode.Normalize(code)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, code)
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		barcodes = append(barcodes, normalized)
+	}
+	return barcodes, nil
+}
+
+// checkComponents checks the components of a composite product and fills
+// in their names and units. Components are stocked products, not parents
+// with variants or other composite products, and a component can't become
+// composite itself.
+func (s *ProductServiceImpl) checkComponents(ctx context.Context, product *model.Product) error {
+	if len(product.Components) > 0 && len(product.VariantAttributes) > 0 {
+		return errors.New("a product with variants cannot have components, give them to its variants")
+	}
+	if len(product.Components) > 0 && product.ID != 0 {
+		used, err := s.productRepo.IsComponent(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		if used {
+			return fmt.Errorf("product %s is a component of another product and cannot have components", product.Name)
+		}
+	}
+
+	seen := make(map[int]bool)
+	for i := range product.Components {
+		component := &product.Components[i]
+
+		if component.ProductID == product.ID && product.ID != 0 {
+			return errors.New("a product cannot be a component of itself")
+		}
+		if seen[component.ProductID] {
+			return fmt.Errorf("component product id %d is listed twice", component.ProductID)
+		}
+		seen[component.ProductID] = true
+
+		c, err := s.productRepo.GetByID(ctx, component.ProductID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("component product id %d not found", component.ProductID)
+		}
+		if len(c.Components) > 0 || len(c.Variants) > 0 {
+			return fmt.Errorf("product %s cannot be a component, use a stocked product", c.Name)
+		}
+
+		if component.Quantity <= 0 {
+			return fmt.Errorf("component %s quantity must be positive", c.Name)
+		}
+		if err := model.CheckQuantity(c.Unit, component.Quantity); err != nil {
+			return err
+		}
+		component.Name = c.Name
+		component.Unit = c.Unit
+	}
+	return nil
+}
+
+// prepareVariant checks a new variant against its parent and fills in what
+// it takes from the parent
+func (s *ProductServiceImpl) prepareVariant(ctx context.Context, product *model.Product) error {
+	parent, err := s.productRepo.GetByID(ctx, *product.ParentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return errors.New("parent product not found")
+	}
+	if parent.ParentID != nil {
+		return errors.New("a variant cannot have variants of its own")
+	}
+	if len(product.VariantAttributes) > 0 {
+		return errors.New("only a parent product has variant attributes")
+	}
+	if err := checkVariantValues(parent, product.Attributes); err != nil {
+		return err
+	}
+
+	product.Unit = parent.Unit
+	product.CategoryID = parent.CategoryID
+	if product.Price == 0 {
+		product.Price = parent.Price
+		product.InheritsPrice = true
+	}
+	if strings.TrimSpace(product.Name) == "" {
+		values := make([]string, 0, len(parent.VariantAttributes))
+		for _, attribute := range parent.VariantAttributes {
+			values = append(values, product.Attributes[attribute])
+		}
+		product.Name = parent.Name + " " + strings.Join(values, " / ")
+	}
+	return nil
+}
+
+// checkVariantValues trims the attribute values of a variant and checks
+// there is one for each variant attribute of the parent and no others
+func checkVariantValues(parent *model.Product, values map[string]string) error {
+	if len(parent.VariantAttributes) == 0 {
+		return fmt.Errorf("product %s has no variant attributes", parent.Name)
+	}
+	for _, attribute := range parent.VariantAttributes {
+		value := strings.TrimSpace(values[attribute])
+		if value == "" {
+			return fmt.Errorf("variant %s is required", attribute)
+		}
+		values[attribute] = value
+	}
+	if len(values) != len(parent.VariantAttributes) {
+		return fmt.Errorf("variant attributes must be %s", strings.Join(parent.VariantAttributes, ", "))
+	}
+	return nil
+}
+
+// normalizeVariantAttributes trims and lower-cases the attribute names of a
+// parent product and rejects empty or repeated ones
+func normalizeVariantAttributes(attributes []string) ([]string, error) {
+	normalized := make([]string, 0, len(attributes))
+	seen := make(map[string]bool)
+	for _, attribute := range attributes {
+		attribute = strings.ToLower(strings.TrimSpace(attribute))
+		if attribute == "" {
+			return nil, errors.New("variant attribute name is required")
+		}
+		if seen[attribute] {
+			return nil, fmt.Errorf("variant attribute %s is listed twice", attribute)
+		}
+		seen[attribute] = true
+		normalized = append(normalized, attribute)
+	}
+	return normalized, nil
+}
+
+// normalizePacks validates the packs of a product and normalizes their
+// barcodes. A pack factor is in the product's unit, so it must fit its
+// decimal places.
+func normalizePacks(product *model.Product) error {
+	if product.Packs == nil {
+		product.Packs = make([]model.ProductPack, 0)
+	}
+
+	names := make(map[string]bool)
+	for i := range product.Packs {
+		pack := &product.Packs[i]
+
+		pack.Name = strings.TrimSpace(pack.Name)
+		if pack.Name == "" {
+			return errors.New("pack name is required")
+		}
+		if names[strings.ToLower(pack.Name)] {
+			return fmt.Errorf("pack %s is listed twice", pack.Name)
+		}
+		names[strings.ToLower(pack.Name)] = true
+
+		if pack.Factor <= 0 {
+			return fmt.Errorf("pack %s factor must be positive", pack.Name)
+		}
+		if err := model.CheckQuantity(product.Unit, pack.Factor); err != nil {
+			return fmt.Errorf("pack %s factor: %w", pack.Name, err)
+		}
+		if pack.Price < 0 {
+			return fmt.Errorf("pack %s price cannot be negative", pack.Name)
+		}
+
+		if pack.Barcode != "" {
+			normalized, err := barcode.Normalize(pack.Barcode)
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, pack.Barcode)
+			}
+			for _, code := range product.Barcodes {
+				if code == normalized {
+					return fmt.Errorf("pack %s cannot share barcode %s with the product", pack.Name, code)
+				}
+			}
+			pack.Barcode = normalized
+		}
+	}
+	return nil
+}
+
+// validateUnitQuantities checks the unit of a product and that its stock
+// levels fit the decimal places of that unit
+func validateUnitQuantities(product *model.Product) error {
+	if _, ok := model.UnitPrecision(product.Unit); !ok {
+		return errors.New("product unit must be one of pcs, kg, g, l or m")
+	}
+
+	for _, quantity := range []decimal.Decimal{product.Stock, product.MinStock, product.ReorderQty} {
+		if err := model.CheckQuantity(product.Unit, quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkProductType checks what a product of its type may have. A gift card
+// product is a plain piece product without stock that issues a gift card
+// per unit sold.
+func checkProductType(product *model.Product) error {
+	switch product.Type {
+	case model.ProductStandard:
+		if product.ValidityDays != nil {
+			return errors.New("only gift card products have validity days")
+		}
+		return nil
+	case model.ProductGiftCard:
+	default:
+		return fmt.Errorf("invalid product type %q", product.Type)
+	}
+
+	if product.ValidityDays != nil && *product.ValidityDays <= 0 {
+		return errors.New("validity days must be greater than 0")
+	}
+	if product.ParentID != nil || len(product.VariantAttributes) > 0 {
+		return errors.New("a gift card product cannot have variants")
+	}
+	if len(product.Packs) > 0 || len(product.Components) > 0 || len(product.ModifierGroupIDs) > 0 {
+		return errors.New("a gift card product cannot have packs, components or modifiers")
+	}
+	if product.Unit != model.UnitPiece {
+		return errors.New("a gift card product is sold in pieces")
+	}
+	if product.Stock != 0 || product.MinStock != 0 || product.ReorderQty != 0 {
+		return errors.New("a gift card product holds no stock")
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type PurchaseOrderService interface {
+	GetAll(ctx context.Context, status string, supplierID int) ([]model.PurchaseOrder, error)
+	GetByID(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	Create(ctx context.Context, order *model.PurchaseOrder) error
+	Update(ctx context.Context, id int, order *model.PurchaseOrder) error
+	Send(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	Receive(ctx context.Context, receipt *model.GoodsReceipt) (*model.PurchaseOrder, error)
+	Close(ctx context.Context, id int) (*model.PurchaseOrder, error)
+	GetOutstanding(ctx context.Context) ([]model.OutstandingPurchaseOrders, error)
+}
+
+type PurchaseOrderServiceImpl struct {
+	repo         repository.PurchaseOrderRepository
+	supplierRepo repository.SupplierRepository
+	productRepo  repository.ProductRepository
+}
+
+func NewPurchaseOrderService(repo repository.PurchaseOrderRepository,
+	supplierRepo repository.SupplierRepository,
+	productRepo repository.ProductRepository) PurchaseOrderService {
+	return &PurchaseOrderServiceImpl{
+		repo:         repo,
+		supplierRepo: supplierRepo,
+		productRepo:  productRepo,
+	}
+}
+
+func (s *PurchaseOrderServiceImpl) GetAll(ctx context.Context, status string, supplierID int) ([]model.PurchaseOrder, error) {
+	return s.repo.GetAll(ctx, status, supplierID)
+}
+
+func (s *PurchaseOrderServiceImpl) GetByID(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		return nil, errors.New("purchase order not found")
+	}
+
+	return order, nil
+}
+
+func (s *PurchaseOrderServiceImpl) Create(ctx context.Context, order *model.PurchaseOrder) error {
+	if err := s.validate(ctx, order); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, order)
+}
+
+// Update replaces the content of a draft purchase order
+func (s *PurchaseOrderServiceImpl) Update(ctx context.Context, id int, order *model.PurchaseOrder) error {
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.Status != model.PurchaseOrderDraft {
+		return errors.New("only draft purchase orders can be changed")
+	}
+
+	if order.SupplierID == 0 {
+		order.SupplierID = existing.SupplierID
+	}
+	if order.StoreID == 0 {
+		order.StoreID = existing.StoreID
+	}
+	if err := s.validate(ctx, order); err != nil {
+		return err
+	}
+
+	order.ID = id
+	rowsAffected, err := s.repo.Update(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("only draft purchase orders can be changed")
+	}
+
+	return nil
+}
+
+// Send marks a draft purchase order as sent to the supplier
+func (s *PurchaseOrderServiceImpl) Send(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	return s.transition(ctx, id, []string{model.PurchaseOrderDraft}, model.PurchaseOrderSent)
+}
+
+// Close finishes a purchase order. Partially received orders can be closed
+// when the rest of the delivery is not expected anymore.
+func (s *PurchaseOrderServiceImpl) Close(ctx context.Context, id int) (*model.PurchaseOrder, error) {
+	return s.transition(ctx, id, []string{model.PurchaseOrderReceived, model.PurchaseOrderPartiallyReceived}, model.PurchaseOrderClosed)
+}
+
+// Receive books a delivery against a sent purchase order
+func (s *PurchaseOrderServiceImpl) Receive(ctx context.Context, receipt *model.GoodsReceipt) (*model.PurchaseOrder, error) {
+	if len(receipt.Lines) == 0 {
+		return nil, errors.New("lines cannot be empty")
+	}
+
+	for _, line := range receipt.Lines {
+		if line.ProductID <= 0 {
+			return nil, errors.New("invalid product id")
+		}
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity must be greater than 0 for product id %d", line.ProductID)
+		}
+		if line.UnitCost < 0 {
+			return nil, fmt.Errorf("unit cost cannot be negative for product id %d", line.ProductID)
+		}
+	}
+
+	return s.repo.Receive(ctx, receipt)
+}
+
+// GetOutstanding returns quantities still to be delivered per supplier
+func (s *PurchaseOrderServiceImpl) GetOutstanding(ctx context.Context) ([]model.OutstandingPurchaseOrders, error) {
+	return s.repo.GetOutstanding(ctx)
+}
+
+func (s *PurchaseOrderServiceImpl) transition(ctx context.Context, id int, from []string, to string) (*model.PurchaseOrder, error) {
+	order, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, status := range from {
+		if order.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("purchase order with status %s cannot be %s", order.Status, to)
+	}
+
+	rowsAffected, err := s.repo.UpdateStatus(ctx, id, order.Status, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status changed between reading and updating
+	if rowsAffected == 0 {
+		return nil, errors.New("purchase order was modified, please retry")
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *PurchaseOrderServiceImpl) validate(ctx context.Context, order *model.PurchaseOrder) error {
+	supplier, err := s.supplierRepo.GetByID(ctx, order.SupplierID)
+	if err != nil {
+		return err
+	}
+	if supplier == nil {
+		return errors.New("supplier does not exist")
+	}
+
+	if len(order.Items) == 0 {
+		return errors.New("items cannot be empty")
+	}
+
+	seen := make(map[int]bool, len(order.Items))
+	for _, item := range order.Items {
+		if item.QuantityOrdered <= 0 {
+			return fmt.Errorf("quantity must be greater than 0 for product id %d", item.ProductID)
+		}
+		if item.UnitCost < 0 {
+			return fmt.Errorf("unit cost cannot be negative for product id %d", item.ProductID)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("product id %d appears more than once", item.ProductID)
+		}
+		seen[item.ProductID] = true
+
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return fmt.Errorf("product id %d does not exist", item.ProductID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type RoundingRuleService interface {
+	GetAll(ctx context.Context) ([]model.RoundingRule, error)
+	Save(ctx context.Context, tenderType string, rule *model.RoundingRule) error
+	Delete(ctx context.Context, tenderType string) error
+}
+
+type RoundingRuleServiceImpl struct {
+	repo repository.RoundingRuleRepository
+}
+
+func NewRoundingRuleService(repo repository.RoundingRuleRepository) RoundingRuleService {
+	return &RoundingRuleServiceImpl{repo: repo}
+}
+
+func (s *RoundingRuleServiceImpl) GetAll(ctx context.Context) ([]model.RoundingRule, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Save sets how amounts settled with a tender type are rounded
+func (s *RoundingRuleServiceImpl) Save(ctx context.Context, tenderType string, rule *model.RoundingRule) error {
+	rule.TenderType = tenderType
+	switch rule.TenderType {
+	case model.TenderCash, model.TenderCard, model.TenderQRIS:
+	case model.TenderGiftCard:
+		return errors.New("gift card tenders cannot be rounded, they pay from a balance")
+	default:
+		return fmt.Errorf("unknown tender type %q", rule.TenderType)
+	}
+
+	rule.Mode = strings.ToLower(strings.TrimSpace(rule.Mode))
+	if rule.Mode == "" {
+		rule.Mode = model.RoundNearest
+	}
+	switch rule.Mode {
+	case model.RoundNearest, model.RoundUp, model.RoundDown:
+	default:
+		return errors.New("mode must be nearest, up or down")
+	}
+	if rule.Increment <= 1 {
+		return errors.New("increment must be greater than 1")
+	}
+
+	return s.repo.Save(ctx, rule)
+}
+
+func (s *RoundingRuleServiceImpl) Delete(ctx context.Context, tenderType string) error {
+	rowsAffected, err := s.repo.Delete(ctx, tenderType)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("rounding rule not found")
+	}
+
+	return nil
+}
+
+// roundingRules returns the rounding rules by tender type
+func roundingRules(ctx context.Context, repo repository.RoundingRuleRepository) (map[string]model.RoundingRule, error) {
+	rules, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]model.RoundingRule, len(rules))
+	for _, rule := range rules {
+		byType[rule.TenderType] = rule
+	}
+	return byType, nil
+}
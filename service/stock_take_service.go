@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type StockTakeService interface {
+	GetAll(ctx context.Context) ([]model.StockTake, error)
+	GetByID(ctx context.Context, id int) (*model.StockTake, error)
+	Create(ctx context.Context, stockTake *model.StockTake) error
+	AddCounts(ctx context.Context, id int, batch *model.StockTakeCountBatch) (*model.StockTake, error)
+	Post(ctx context.Context, id int, postedBy string) (*model.StockTake, error)
+	Cancel(ctx context.Context, id int) (*model.StockTake, error)
+}
+
+type StockTakeServiceImpl struct {
+	repo         repository.StockTakeRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewStockTakeService(repo repository.StockTakeRepository, categoryRepo repository.CategoryRepository) StockTakeService {
+	return &StockTakeServiceImpl{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *StockTakeServiceImpl) GetAll(ctx context.Context) ([]model.StockTake, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *StockTakeServiceImpl) GetByID(ctx context.Context, id int) (*model.StockTake, error) {
+	stockTake, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if stockTake == nil {
+		return nil, errors.New("stock take not found")
+	}
+
+	return stockTake, nil
+}
+
+// Create opens a session for the selected categories
+func (s *StockTakeServiceImpl) Create(ctx context.Context, stockTake *model.StockTake) error {
+	if strings.TrimSpace(stockTake.Name) == "" {
+		return errors.New("stock take name is required")
+	}
+
+	if len(stockTake.CategoryIDs) == 0 {
+		return errors.New("at least one category is required")
+	}
+
+	seen := make(map[int]bool, len(stockTake.CategoryIDs))
+	for _, categoryID := range stockTake.CategoryIDs {
+		if seen[categoryID] {
+			return fmt.Errorf("category id %d appears more than once", categoryID)
+		}
+		seen[categoryID] = true
+
+		category, err := s.categoryRepo.GetByID(ctx, categoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return fmt.Errorf("category id %d does not exist", categoryID)
+		}
+	}
+
+	if err := s.repo.Create(ctx, stockTake); err != nil {
+		return err
+	}
+
+	created, err := s.GetByID(ctx, stockTake.ID)
+	if err != nil {
+		return err
+	}
+	*stockTake = *created
+	return nil
+}
+
+// AddCounts records a batch from one scanner and returns the updated session
+func (s *StockTakeServiceImpl) AddCounts(ctx context.Context, id int, batch *model.StockTakeCountBatch) (*model.StockTake, error) {
+	if len(batch.Lines) == 0 {
+		return nil, errors.New("lines cannot be empty")
+	}
+
+	for _, line := range batch.Lines {
+		if line.ProductID <= 0 {
+			return nil, errors.New("invalid product id")
+		}
+		if line.Quantity < 0 {
+			return nil, fmt.Errorf("quantity cannot be negative for product id %d", line.ProductID)
+		}
+	}
+
+	if err := s.repo.AddCounts(ctx, id, batch); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Post applies the variances of the session to stock
+func (s *StockTakeServiceImpl) Post(ctx context.Context, id int, postedBy string) (*model.StockTake, error) {
+	if err := s.repo.Post(ctx, id, postedBy); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Cancel discards an open session without touching stock
+func (s *StockTakeServiceImpl) Cancel(ctx context.Context, id int) (*model.StockTake, error) {
+	stockTake, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if stockTake.Status != model.StockTakeOpen {
+		return nil, fmt.Errorf("stock take with status %s cannot be cancelled", stockTake.Status)
+	}
+
+	rowsAffected, err := s.repo.UpdateStatus(ctx, id, model.StockTakeOpen, model.StockTakeCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, errors.New("stock take was modified, please retry")
+	}
+
+	return s.GetByID(ctx, id)
+}
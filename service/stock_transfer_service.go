@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+	"go-cashier-api/repository"
+)
+
+type StockTransferService interface {
+	GetAll(ctx context.Context, status string) ([]model.StockTransfer, error)
+	GetByID(ctx context.Context, id int) (*model.StockTransfer, error)
+	Send(ctx context.Context, request model.SendStockTransferRequest, sentBy string) (*model.StockTransfer, error)
+	Receive(ctx context.Context, id int, request model.ReceiveStockTransferRequest, receivedBy string) (*model.StockTransfer, error)
+}
+
+type StockTransferServiceImpl struct {
+	repo      repository.StockTransferRepository
+	storeRepo repository.StoreRepository
+}
+
+func NewStockTransferService(repo repository.StockTransferRepository, storeRepo repository.StoreRepository) StockTransferService {
+	return &StockTransferServiceImpl{repo: repo, storeRepo: storeRepo}
+}
+
+func (s *StockTransferServiceImpl) GetAll(ctx context.Context, status string) ([]model.StockTransfer, error) {
+	if status != "" && status != model.TransferInTransit && status != model.TransferReceived {
+		return nil, errors.New("invalid status")
+	}
+
+	return s.repo.GetAll(ctx, status)
+}
+
+func (s *StockTransferServiceImpl) GetByID(ctx context.Context, id int) (*model.StockTransfer, error) {
+	transfer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer == nil {
+		return nil, errors.New("stock transfer not found")
+	}
+
+	return transfer, nil
+}
+
+// Send moves stock out of one store towards another
+func (s *StockTransferServiceImpl) Send(ctx context.Context, request model.SendStockTransferRequest, sentBy string) (*model.StockTransfer, error) {
+	if request.FromStoreID == request.ToStoreID {
+		return nil, errors.New("from and to store must be different")
+	}
+
+	for _, storeID := range []int{request.FromStoreID, request.ToStoreID} {
+		store, err := s.storeRepo.GetByID(ctx, storeID)
+		if err != nil {
+			return nil, err
+		}
+		if store == nil {
+			return nil, fmt.Errorf("store id %d does not exist", storeID)
+		}
+	}
+
+	if len(request.Lines) == 0 {
+		return nil, errors.New("lines cannot be empty")
+	}
+
+	transfer := &model.StockTransfer{
+		FromStoreID: request.FromStoreID,
+		ToStoreID:   request.ToStoreID,
+		Notes:       request.Notes,
+		SentBy:      sentBy,
+	}
+
+	seen := make(map[int]bool)
+	for _, line := range request.Lines {
+		if line.ProductID <= 0 {
+			return nil, errors.New("invalid product id")
+		}
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity must be greater than 0 for product id %d", line.ProductID)
+		}
+		if seen[line.ProductID] {
+			return nil, fmt.Errorf("product id %d is listed more than once", line.ProductID)
+		}
+		seen[line.ProductID] = true
+
+		transfer.Items = append(transfer.Items, model.StockTransferItem{
+			ProductID:    line.ProductID,
+			QuantitySent: line.Quantity,
+		})
+	}
+
+	if err := s.repo.Send(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, transfer.ID)
+}
+
+// Receive books a transfer into the receiving store
+func (s *StockTransferServiceImpl) Receive(ctx context.Context, id int, request model.ReceiveStockTransferRequest, receivedBy string) (*model.StockTransfer, error) {
+	received := make(map[int]decimal.Decimal)
+	for _, line := range request.Lines {
+		if line.ProductID <= 0 {
+			return nil, errors.New("invalid product id")
+		}
+		if line.Quantity < 0 {
+			return nil, fmt.Errorf("quantity cannot be negative for product id %d", line.ProductID)
+		}
+		if _, ok := received[line.ProductID]; ok {
+			return nil, fmt.Errorf("product id %d is listed more than once", line.ProductID)
+		}
+		received[line.ProductID] = line.Quantity
+	}
+
+	if err := s.repo.Receive(ctx, id, received, receivedBy); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type StoreService interface {
+	GetAll(ctx context.Context) ([]model.Store, error)
+	GetByID(ctx context.Context, id int) (*model.Store, error)
+	Create(ctx context.Context, store *model.Store) error
+	Update(ctx context.Context, id int, store *model.Store) error
+	GetProductStock(ctx context.Context, productID int) ([]model.ProductStock, error)
+}
+
+type StoreServiceImpl struct {
+	repo        repository.StoreRepository
+	productRepo repository.ProductRepository
+}
+
+func NewStoreService(repo repository.StoreRepository, productRepo repository.ProductRepository) StoreService {
+	return &StoreServiceImpl{repo: repo, productRepo: productRepo}
+}
+
+func (s *StoreServiceImpl) GetAll(ctx context.Context) ([]model.Store, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *StoreServiceImpl) Create(ctx context.Context, store *model.Store) error {
+	if strings.TrimSpace(store.Name) == "" {
+		return errors.New("store name is required")
+	}
+
+	store.Currency = strings.ToUpper(strings.TrimSpace(store.Currency))
+	if store.Currency == "" {
+		store.Currency = model.CurrencyIDR
+	}
+	if _, ok := model.MinorUnits(store.Currency); !ok {
+		return fmt.Errorf("unknown currency %q", store.Currency)
+	}
+
+	store.Timezone = strings.TrimSpace(store.Timezone)
+	if store.Timezone == "" {
+		store.Timezone = model.DefaultTimezone
+	}
+	if _, err := time.LoadLocation(store.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", store.Timezone)
+	}
+
+	return s.repo.Create(ctx, store)
+}
+
+func (s *StoreServiceImpl) GetByID(ctx context.Context, id int) (*model.Store, error) {
+	store, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if store == nil {
+		return nil, errors.New("store not found")
+	}
+
+	return store, nil
+}
+
+func (s *StoreServiceImpl) Update(ctx context.Context, id int, store *model.Store) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("store not found")
+	}
+
+	// Apply partial updates, an empty name, currency or timezone keeps the
+	// existing one.
+	// A new currency applies to sales from then on.
+	if strings.TrimSpace(store.Name) != "" {
+		existing.Name = store.Name
+	}
+	existing.Address = store.Address
+	if currency := strings.ToUpper(strings.TrimSpace(store.Currency)); currency != "" {
+		if _, ok := model.MinorUnits(currency); !ok {
+			return fmt.Errorf("unknown currency %q", currency)
+		}
+		existing.Currency = currency
+	}
+	if timezone := strings.TrimSpace(store.Timezone); timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", timezone)
+		}
+		existing.Timezone = timezone
+	}
+
+	rowsAffected, err := s.repo.Update(ctx, existing)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("failed to update store")
+	}
+
+	return nil
+}
+
+// GetProductStock returns the per-store stock of a product
+func (s *StoreServiceImpl) GetProductStock(ctx context.Context, productID int) ([]model.ProductStock, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	return s.repo.GetProductStock(ctx, productID)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type SupplierService interface {
+	GetAll(ctx context.Context) ([]model.Supplier, error)
+	GetByID(ctx context.Context, id int) (*model.Supplier, error)
+	Create(ctx context.Context, supplier *model.Supplier) error
+	Update(ctx context.Context, id int, supplier *model.Supplier) error
+	Delete(ctx context.Context, id int) error
+}
+
+type SupplierServiceImpl struct {
+	repo repository.SupplierRepository
+}
+
+func NewSupplierService(repo repository.SupplierRepository) SupplierService {
+	return &SupplierServiceImpl{repo: repo}
+}
+
+func (s *SupplierServiceImpl) GetAll(ctx context.Context) ([]model.Supplier, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *SupplierServiceImpl) Create(ctx context.Context, supplier *model.Supplier) error {
+	if strings.TrimSpace(supplier.Name) == "" {
+		return errors.New("supplier name is required")
+	}
+
+	return s.repo.Create(ctx, supplier)
+}
+
+func (s *SupplierServiceImpl) GetByID(ctx context.Context, id int) (*model.Supplier, error) {
+	supplier, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	return supplier, nil
+}
+
+func (s *SupplierServiceImpl) Update(ctx context.Context, id int, supplier *model.Supplier) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("supplier not found")
+	}
+
+	// Apply partial updates, empty name keeps the existing one
+	if strings.TrimSpace(supplier.Name) != "" {
+		existing.Name = supplier.Name
+	}
+	existing.Phone = supplier.Phone
+	existing.Email = supplier.Email
+	existing.Address = supplier.Address
+
+	rowsAffected, err := s.repo.Update(ctx, existing)
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("failed to update supplier")
+	}
+
+	return nil
+}
+
+func (s *SupplierServiceImpl) Delete(ctx context.Context, id int) error {
+	rowsAffected, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		// Suppliers referenced by purchase orders can't be removed
+		if strings.Contains(err.Error(), "foreign key") {
+			return errors.New("supplier is in use by purchase orders")
+		}
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("supplier not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"go-cashier-api/model"
+	"go-cashier-api/repository"
+)
+
+type TenantService interface {
+	Resolve(apiKey, host string) (*model.Tenant, error)
+}
+
+type TenantServiceImpl struct {
+	repo repository.TenantRepository
+}
+
+func NewTenantService(repo repository.TenantRepository) TenantService {
+	return &TenantServiceImpl{repo: repo}
+}
+
+// Resolve finds the tenant of a request. The API key wins over the host
+// name, a key that doesn't match any tenant is rejected rather than
+// falling back to the host.
+func (s *TenantServiceImpl) Resolve(apiKey, host string) (*model.Tenant, error) {
+	if apiKey != "" {
+		tenant, err := s.repo.GetByAPIKey(apiKey)
+		if err != nil {
+			return nil, err
+		}
+		if tenant == nil {
+			return nil, errors.New("invalid api key")
+		}
+		return tenant, nil
+	}
+
+	// Drop the port, host names are stored without it
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return nil, errors.New("tenant not found")
+	}
+
+	tenant, err := s.repo.GetByHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, errors.New("tenant not found")
+	}
+	return tenant, nil
+}
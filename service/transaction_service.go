@@ -1,58 +1,134 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"go-cashier-api/model"
+	"go-cashier-api/pkg/decimal"
+	"go-cashier-api/pkg/event"
+	"go-cashier-api/pkg/tenant"
 	"go-cashier-api/repository"
 )
 
 // Business logic interface
 type TransactionService interface {
-	Checkout(request model.CheckoutRequest) (*model.TransactionResponse, error)
-	GetTransactionsByDate(startDateStr, endDateStr string) (*model.TransactionsResponse, error)
-	GetTransactionsToday() (*model.TransactionsResponse, error)
+	Checkout(ctx context.Context, request model.CheckoutRequest) (*model.TransactionResponse, error)
+	GetTransactionsByDate(ctx context.Context, startDateStr, endDateStr string, storeID int, rollUp bool) (*model.TransactionsResponse, error)
+	GetTransactionsToday(ctx context.Context, storeID int, rollUp bool) (*model.TransactionsResponse, error)
+	GetSalesReport(ctx context.Context, startDateStr, endDateStr string, query model.SalesReportQuery) (*model.SalesReport, error)
 }
 
 // Service implementation with dependencies
 type TransactionServiceImpl struct {
-	repo        repository.TransactionRepository // Transaction operations
-	productRepo repository.ProductRepository     // Product operations
+	repo        repository.TransactionRepository   // Transaction operations
+	productRepo repository.ProductRepository       // Product operations
+	formatRepo  repository.BarcodeFormatRepository // In-store barcode layouts
+	rounding    repository.RoundingRuleRepository  // Rounding of the amount due per tender type
+	storeRepo   repository.StoreRepository         // Store timezones for report dates
+	events      *event.Bus                         // Notifications such as low stock
 }
 
 // Constructor with dependency injection
-func NewTransactionService(repo repository.TransactionRepository,
-	productRepo repository.ProductRepository) TransactionService {
+func NewTransactionService(repo repository.TransactionRepository, productRepo repository.ProductRepository,
+	formatRepo repository.BarcodeFormatRepository, rounding repository.RoundingRuleRepository,
+	storeRepo repository.StoreRepository, events *event.Bus) TransactionService {
 	return &TransactionServiceImpl{
 		repo:        repo,
 		productRepo: productRepo,
+		formatRepo:  formatRepo,
+		rounding:    rounding,
+		storeRepo:   storeRepo,
+		events:      events,
 	}
 }
 
-func (s *TransactionServiceImpl) Checkout(request model.CheckoutRequest) (*model.TransactionResponse, error) {
-	// Validate request has at least one item
-	if len(request.Items) == 0 {
+func (s *TransactionServiceImpl) Checkout(ctx context.Context, request model.CheckoutRequest) (*model.TransactionResponse, error) {
+	// Validate request has at least one item, an order's items are its
+	// lines and are read when it is sold
+	if len(request.Items) == 0 && request.OrderID == 0 {
 		return nil, fmt.Errorf("items cannot be empty")
 	}
 
+	request.OverrideExpiredBy = strings.TrimSpace(request.OverrideExpiredBy)
+	request.CouponCode = normalizeCouponCode(request.CouponCode)
+	request.Customer = strings.TrimSpace(request.Customer)
+
+	if err := checkTenders(request.Tenders); err != nil {
+		return nil, err
+	}
+	if request.Shares > 0 && len(request.Tenders) > 0 {
+		return nil, fmt.Errorf("an order split into shares is paid share by share")
+	}
+	if len(request.Tenders) > 0 {
+		rules, err := roundingRules(ctx, s.rounding)
+		if err != nil {
+			return nil, err
+		}
+		request.Rounding = rules
+	}
+
 	// Validate each item
-	for _, item := range request.Items {
+	for i := range request.Items {
+		item := &request.Items[i]
+
+		if item.Barcode != "" {
+			if item.ProductID != 0 {
+				return nil, fmt.Errorf("item must have either product_id or barcode, not both")
+			}
+
+			// Resolve scanned barcodes to the product they belong to, scale
+			// and price labels also set the quantity and amount of the line
+			scanned, err := scanBarcode(ctx, s.productRepo, s.formatRepo, item.Barcode)
+			if err != nil {
+				return nil, err
+			}
+			if scanned == nil {
+				return nil, fmt.Errorf("product with barcode %s not found", item.Barcode)
+			}
+			item.ProductID = scanned.ID
+			if scanned.Pack != nil {
+				item.Pack = scanned.Pack.Name
+			}
+			if scanned.Quantity != nil {
+				item.Quantity = *scanned.Quantity
+				item.Amount = scanned.Amount
+			}
+		}
+
 		if item.ProductID <= 0 {
 			return nil, fmt.Errorf("invalid product id")
 		}
 		if item.Quantity <= 0 {
 			return nil, fmt.Errorf("quantity must be greater than 0")
 		}
+
+		// Check the picked modifiers against the product's modifier groups
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+		}
+		item.Modifiers, err = model.SelectModifiers(product.Name, product.ModifierGroups, item.ModifierIDs)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Call repository to create transaction
-	transaction, err := s.repo.CreateTransaction(request.Items)
+	transaction, err := s.repo.CreateTransaction(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
+	s.notifyLowStock(ctx, transaction)
+	publishKitchenItems(ctx, s.events, transaction.KitchenItems)
+
 	// Create success response
 	response := &model.TransactionResponse{
 		Success: true,
@@ -63,21 +139,44 @@ func (s *TransactionServiceImpl) Checkout(request model.CheckoutRequest) (*model
 	return response, nil
 }
 
-func (s *TransactionServiceImpl) GetTransactionsByDate(startDateStr, endDateStr string) (*model.TransactionsResponse, error) {
-	startDate, err := time.Parse("2026-01-31", startDateStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start date format. Use YYYY-MM-DD")
+// checkTenders checks each tender on its own, whether they cover the bill
+// is checked once the sale is priced
+func checkTenders(tenders []model.Tender) error {
+	for i := range tenders {
+		tender := &tenders[i]
+		switch tender.Type {
+		case model.TenderCash, model.TenderCard, model.TenderQRIS:
+		case model.TenderGiftCard:
+			tender.Reference = normalizeGiftCardCode(tender.Reference)
+			if tender.Reference == "" {
+				return fmt.Errorf("gift card tender needs the card code as reference")
+			}
+		default:
+			return fmt.Errorf("unknown tender type %q", tender.Type)
+		}
+		if tender.Amount <= 0 {
+			return fmt.Errorf("tender amount must be greater than 0")
+		}
+		tender.Currency = strings.ToUpper(strings.TrimSpace(tender.Currency))
+		if _, ok := model.MinorUnits(tender.Currency); tender.Currency != "" && !ok {
+			return fmt.Errorf("unknown currency %q", tender.Currency)
+		}
 	}
+	return nil
+}
 
-	endDate, err := time.Parse("2026-01-31", endDateStr)
+func (s *TransactionServiceImpl) GetTransactionsByDate(ctx context.Context, startDateStr, endDateStr string, storeID int, rollUp bool) (*model.TransactionsResponse, error) {
+	loc, err := storeLocation(ctx, s.storeRepo, storeID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid end date format. Use YYYY-MM-DD")
+		return nil, err
 	}
 
-	// Add one day to end date to include the entire day
-	endDate = endDate.Add(24 * time.Hour)
+	startDate, endDate, err := parseDateRange(startDateStr, endDateStr, loc)
+	if err != nil {
+		return nil, err
+	}
 
-	transactions, totalTransactions, totalRevenue, bestSellingProduct, err := s.repo.GetTransactionsByDate(startDate, endDate)
+	transactions, totalTransactions, totalRevenue, totalRounding, bestSellingProduct, err := s.repo.GetTransactionsByDate(ctx, startDate, endDate, storeID, rollUp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions by date: %w", err)
 	}
@@ -88,25 +187,20 @@ func (s *TransactionServiceImpl) GetTransactionsByDate(startDateStr, endDateStr
 		Data:               transactions,
 		TotalTransactions:  totalTransactions,
 		TotalRevenue:       totalRevenue,
+		TotalRounding:      totalRounding,
 		BestSellingProduct: bestSellingProduct,
 	}, nil
 }
 
-func (s *TransactionServiceImpl) GetTransactionsToday() (*model.TransactionsResponse, error) {
-	now := time.Now().UTC()
-
-	today := time.Date(
-		now.Year(),
-		now.Month(),
-		now.Day(),
-		0, 0, 0, 0,
-		now.Location(),
-	)
-
-	// Add one day to end date to include the entire day
-	endDate := today.Add(24 * time.Hour)
+func (s *TransactionServiceImpl) GetTransactionsToday(ctx context.Context, storeID int, rollUp bool) (*model.TransactionsResponse, error) {
+	// Today is the store's day, not the server's
+	loc, err := storeLocation(ctx, s.storeRepo, storeID)
+	if err != nil {
+		return nil, err
+	}
+	today, endDate := dayRange(time.Now(), loc)
 
-	transactions, totalTransactions, totalRevenue, bestSellingProduct, err := s.repo.GetTransactionsByDate(today, endDate)
+	transactions, totalTransactions, totalRevenue, totalRounding, bestSellingProduct, err := s.repo.GetTransactionsByDate(ctx, today, endDate, storeID, rollUp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions by date: %w", err)
 	}
@@ -121,6 +215,83 @@ func (s *TransactionServiceImpl) GetTransactionsToday() (*model.TransactionsResp
 		Data:               transactions,
 		TotalTransactions:  totalTransactions,
 		TotalRevenue:       totalRevenue,
+		TotalRounding:      totalRounding,
 		BestSellingProduct: bestSellingProduct,
 	}, nil
 }
+
+// notifyLowStock publishes a low stock event for every product this sale
+// took from above its reorder point to at or below it in the store of the
+// sale. What the lines took is added up per product first, so a product on
+// several lines is checked once. Composite lines take their stock from
+// their components.
+func (s *TransactionServiceImpl) notifyLowStock(ctx context.Context, transaction *model.Transaction) {
+	sold := make(map[int]decimal.Decimal)
+	for _, detail := range transaction.Details {
+		if len(detail.Components) > 0 {
+			for _, component := range detail.Components {
+				sold[component.ProductID] += component.Quantity
+			}
+		} else {
+			sold[detail.ProductID] += detail.Quantity
+		}
+	}
+
+	lowStock, err := s.productRepo.GetLowStock(ctx, transaction.StoreID)
+	if err != nil {
+		return
+	}
+	// Subscribers are not in the request, so the event names its tenant
+	tenantID, _ := tenant.FromContext(ctx)
+	for _, p := range lowStock {
+		if quantity, ok := sold[p.ProductID]; ok && p.Stock+quantity > p.MinStock {
+			p.TenantID = tenantID
+			s.events.Publish(event.LowStock, p)
+		}
+	}
+}
+
+// GetSalesReport groups the sales of a date range by period, category or
+// product
+func (s *TransactionServiceImpl) GetSalesReport(ctx context.Context, startDateStr, endDateStr string, query model.SalesReportQuery) (*model.SalesReport, error) {
+	loc, err := storeLocation(ctx, s.storeRepo, query.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	query.StartDate, query.EndDate, err = parseDateRange(startDateStr, endDateStr, loc)
+	if err != nil {
+		return nil, err
+	}
+	query.Timezone = loc.String()
+
+	if query.GroupBy == "" {
+		query.GroupBy = model.SalesByDay
+	}
+	switch query.GroupBy {
+	case model.SalesByHour, model.SalesByDay, model.SalesByWeek, model.SalesByMonth:
+		if query.Top > 0 || query.Bottom > 0 {
+			return nil, errors.New("top and bottom must be used with group_by category or product")
+		}
+	case model.SalesByCategory, model.SalesByProduct:
+	default:
+		return nil, errors.New("invalid group_by. Use hour, day, week, month, category or product")
+	}
+
+	if query.Top < 0 || query.Bottom < 0 {
+		return nil, errors.New("top and bottom must not be negative")
+	}
+	if query.Top > 0 && query.Bottom > 0 {
+		return nil, errors.New("top and bottom must not be used together")
+	}
+
+	report, err := s.repo.GetSalesReport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range report.Rows {
+		if period := report.Rows[i].Period; period != nil {
+			*period = inLocation(*period, loc)
+		}
+	}
+	return report, nil
+}